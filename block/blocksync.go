@@ -0,0 +1,89 @@
+package block
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	abciconv "github.com/dymensionxyz/dymint/conv/abci"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// requestMissingBlocks fetches the blocks missing between the store height and the lowest cached gossiped
+// block from the peers. Only a single request is in progress at a time. The fetched blocks are validated
+// and handed to the ApplyBlockLoop, so they are applied in order along with the gossiped blocks.
+func (m *Manager) requestMissingBlocks(ctx context.Context) {
+	if m.p2pClient == nil || len(m.syncCache) == 0 {
+		return
+	}
+	from := m.store.Height() + 1
+	to := uint64(0)
+	for cachedHeight := range m.syncCache {
+		if to == 0 || cachedHeight-1 < to {
+			to = cachedHeight - 1
+		}
+	}
+	if to < from {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&m.blockSyncInProgress, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&m.blockSyncInProgress, 0)
+		for from <= to && ctx.Err() == nil {
+			m.logger.Debug("Requesting missing blocks from peers", "from", from, "to", to)
+			batch, err := m.p2pClient.GetBlocks(ctx, from, to)
+			if err != nil {
+				m.logger.Debug("Failed to get missing blocks from peers", "from", from, "to", to, "error", err)
+				return
+			}
+			if err := m.deliverSyncedBlocks(ctx, batch); err != nil {
+				return
+			}
+			from += uint64(len(batch.Blocks))
+		}
+	}()
+}
+
+// deliverSyncedBlocks validates the blocks received from peers and hands them to the ApplyBlockLoop. It waits
+// for the ApplyBlockLoop to take each block, so the next blocks are requested only once the previous ones
+// are being applied.
+func (m *Manager) deliverSyncedBlocks(ctx context.Context, batch *types.Batch) error {
+	for i, block := range batch.Blocks {
+		commit := batch.Commits[i]
+		if err := m.validateSyncedBlock(block, commit); err != nil {
+			m.logger.Error("Invalid block received from peers", "height", block.Header.Height, "error", err)
+			return err
+		}
+		select {
+		case m.syncedBlocks <- &cachedBlock{block: block, commit: commit}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// validateSyncedBlock validates a block received from a peer or the DA layer and checks that its commit was signed by the proposer.
+func (m *Manager) validateSyncedBlock(block *types.Block, commit *types.Commit) error {
+	if err := block.ValidateBasic(); err != nil {
+		return err
+	}
+	if commit.Height != block.Header.Height {
+		return errors.New("commit height mismatch")
+	}
+	if commit.HeaderHash != block.Header.Hash() {
+		return errors.New("commit header hash mismatch")
+	}
+	abciHeaderPb := abciconv.ToABCIHeaderPB(&block.Header)
+	abciHeaderBytes, err := abciHeaderPb.Marshal()
+	if err != nil {
+		return err
+	}
+	proposer, err := m.blockProposer(&block.Header)
+	if err != nil {
+		return err
+	}
+	return commit.Validate(proposer, abciHeaderBytes)
+}
@@ -2,6 +2,7 @@ package block
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -11,16 +12,19 @@ import (
 	"github.com/avast/retry-go"
 	abciconv "github.com/dymensionxyz/dymint/conv/abci"
 	"github.com/dymensionxyz/dymint/p2p"
+	"github.com/gogo/protobuf/proto"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	abci "github.com/tendermint/tendermint/abci/types"
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/libs/pubsub"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	"github.com/tendermint/tendermint/proxy"
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/dymensionxyz/dymint/config"
 	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/fraudproof"
 	"github.com/dymensionxyz/dymint/log"
 	"github.com/dymensionxyz/dymint/mempool"
 	"github.com/dymensionxyz/dymint/settlement"
@@ -36,6 +40,9 @@ const (
 	defaultDABlockTime = 30 * time.Second
 )
 
+// syncCacheSize is the max number of heights ahead of the store height for which gossiped blocks are cached
+const syncCacheSize = 1000
+
 const (
 	producedBlock blockSource = "produced"
 	gossipedBlock blockSource = "gossip"
@@ -47,9 +54,16 @@ type blockMetaData struct {
 	daHeight uint64
 }
 
+// cachedBlock is a gossiped block which is waiting for the blocks before it to be applied.
+type cachedBlock struct {
+	block  *types.Block
+	commit *types.Commit
+}
+
 // Manager is responsible for aggregating transactions into blocks.
 type Manager struct {
-	pubsub *pubsub.Server
+	pubsub   *pubsub.Server
+	eventBus *tmtypes.EventBus
 
 	p2pClient *p2p.Client
 
@@ -69,12 +83,30 @@ type Manager struct {
 
 	syncTargetDiode diodes.Diode
 
-	batchInProcess atomic.Value
+	// batchesInFlight are the batches which were created but not yet accepted by the SL, ordered by height
+	batchesInFlight   []*batchSubmission
+	batchesInFlightMu sync.Mutex
+	// daPosts tracks the running DA submissions of the batches in flight
+	daPosts sync.WaitGroup
+	// submitBatchCh notifies the SubmitLoop that batches might be ready for submission to the SL
+	submitBatchCh chan struct{}
+	// lastSubmissionTime is the time (unix nano) the last batch submission was started
+	lastSubmissionTime int64
+	// pendingBatchBytes estimates the encoded size of the produced blocks which weren't submitted yet
+	pendingBatchBytes uint64
 
 	syncTarget   uint64
 	isSyncedCond sync.Cond
 
-	syncCache map[uint64]*types.Block
+	// syncCache holds gossiped blocks which arrived before the blocks preceding them. It is only accessed by the ApplyBlockLoop.
+	syncCache map[uint64]*cachedBlock
+	// blockSyncInProgress is set while missing blocks are requested from peers
+	blockSyncInProgress int32
+	// syncedBlocks passes the blocks received from peers to the ApplyBlockLoop
+	syncedBlocks chan *cachedBlock
+
+	metrics   *Metrics
+	daMetrics *da.Metrics
 
 	logger log.Logger
 }
@@ -118,7 +150,7 @@ func NewManager(
 		conf.DABlockTime = defaultDABlockTime
 	}
 
-	exec := state.NewBlockExecutor(proposerAddress, conf.NamespaceID, genesis.ChainID, mempool, proxyApp, eventBus, logger)
+	exec := state.NewBlockExecutor(proposerAddress, conf.NamespaceID, genesis.ChainID, mempool, proxyApp, eventBus, conf.FraudProofs, logger)
 	if s.LastBlockHeight+1 == genesis.InitialHeight {
 		res, err := exec.InitChain(genesis)
 		if err != nil {
@@ -131,11 +163,17 @@ func NewManager(
 		}
 	}
 
-	batchInProcess := atomic.Value{}
-	batchInProcess.Store(false)
+	if conf.MaxBatchesInFlight == 0 {
+		conf.MaxBatchesInFlight = 1
+	}
+	// Batches above the max batch size can't be decoded by the full nodes
+	if conf.BlockBatchMaxSizeBytes == 0 || conf.BlockBatchMaxSizeBytes > types.MaxBatchSizeBytes {
+		conf.BlockBatchMaxSizeBytes = types.MaxBatchSizeBytes
+	}
 
 	agg := &Manager{
 		pubsub:           pubsub,
+		eventBus:         eventBus,
 		p2pClient:        p2pClient,
 		proposerKey:      proposerKey,
 		conf:             conf,
@@ -147,16 +185,38 @@ func NewManager(
 		settlementClient: settlementClient,
 		retriever:        dalc.(da.BatchRetriever),
 		// channels are buffered to avoid blocking on input/output operations, buffer sizes are arbitrary
-		syncTargetDiode: diodes.NewOneToOne(1, nil),
-		syncCache:       make(map[uint64]*types.Block),
-		isSyncedCond:    *sync.NewCond(new(sync.Mutex)),
-		batchInProcess:  batchInProcess,
-		logger:          logger,
+		syncTargetDiode:    diodes.NewOneToOne(1, nil),
+		syncCache:          make(map[uint64]*cachedBlock),
+		syncedBlocks:       make(chan *cachedBlock),
+		isSyncedCond:       *sync.NewCond(new(sync.Mutex)),
+		submitBatchCh:      make(chan struct{}, 1),
+		lastSubmissionTime: time.Now().UnixNano(),
+		metrics:            NopMetrics(),
+		daMetrics:          da.NopMetrics(),
+		logger:             logger,
+	}
+
+	// The manifests of batches split across several DA blobs are signed with the proposer key, and the manifests of
+	// keys other than the sequencers' are dropped before fetching any chunk
+	if manifestSigner, ok := dalc.(da.ManifestSigner); ok {
+		manifestSigner.SetManifestKeys(proposerKey, agg.isSequencerPubKey)
+	}
+
+	// Load the batches which were created but not yet accepted by the SL before the node was stopped
+	if err := agg.loadBatchSubmissions(); err != nil {
+		return nil, err
 	}
 
 	return agg, nil
 }
 
+// SetMetrics sets the metrics exported by the manager, including the metrics of its calls to the DA layer.
+// It must be called before the manager is started.
+func (m *Manager) SetMetrics(metrics *Metrics, daMetrics *da.Metrics) {
+	m.metrics = metrics
+	m.daMetrics = daMetrics
+}
+
 func getAddress(key crypto.PrivKey) ([]byte, error) {
 	rawKey, err := key.GetPublic().Raw()
 	if err != nil {
@@ -216,21 +276,26 @@ func (m *Manager) waitForSync(ctx context.Context) error {
 	m.isSyncedCond.L.Lock()
 	// Wait until we're synced and that we have got the latest batch (if we didn't, m.syncTarget == 0)
 	// before we start publishing blocks
-	for m.store.Height() < atomic.LoadUint64(&m.syncTarget) {
+	for ctx.Err() == nil && m.store.Height() < atomic.LoadUint64(&m.syncTarget) {
 		m.logger.Info("Waiting for sync", "current height", m.store.Height(), "syncTarget", atomic.LoadUint64(&m.syncTarget))
 		m.isSyncedCond.Wait()
 	}
 	m.isSyncedCond.L.Unlock()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	m.logger.Info("Synced, Starting to produce", "current height", m.store.Height(), "syncTarget", atomic.LoadUint64(&m.syncTarget))
 	return nil
 }
 
 // ProduceBlockLoop is calling publishBlock in a loop as long as wer'e synced.
 func (m *Manager) ProduceBlockLoop(ctx context.Context) {
-	// We want to wait until we are synced. After that, since there is no leader
-	// election yet, and leader are elected manually, we will not be out of sync until
-	// we are manually being replaced.
+	// We want to wait until we are synced. After that we will not be out of sync until
+	// another sequencer becomes the proposer, in which case the ProposerLoop stops this loop.
 	err := m.waitForSync(ctx)
+	if ctx.Err() != nil {
+		return
+	}
 	if err != nil {
 		m.logger.Error("failed to wait for sync", "err", err)
 	}
@@ -258,7 +323,7 @@ func (m *Manager) ProduceBlockLoop(ctx context.Context) {
 		case <-ticker.C:
 			produceBlockLoop()
 		case <-produceBlockCh:
-			for {
+			for ctx.Err() == nil {
 				produceBlockLoop()
 			}
 		}
@@ -282,6 +347,12 @@ func (m *Manager) SyncTargetLoop(ctx context.Context) {
 	} else {
 		m.updateSyncParams(ctx, resultRetrieveBatch.EndHeight)
 	}
+	// The SL is also polled periodically, so missed events don't stall the sync or the submission pipeline.
+	ticker := &time.Ticker{}
+	if m.conf.BatchSyncInterval > 0 {
+		ticker = time.NewTicker(m.conf.BatchSyncInterval)
+		defer ticker.Stop()
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -289,23 +360,65 @@ func (m *Manager) SyncTargetLoop(ctx context.Context) {
 		case event := <-subscription.Out():
 			m.logger.Info("Received state update event", "eventData", event.Data())
 			eventData := event.Data().(*settlement.EventDataNewSettlementBatchAccepted)
-			m.updateSyncParams(ctx, eventData.EndHeight)
-			// In case we are the aggregator and we've got an update, then we can stop blocking from
-			// the next batches to be published. For non-aggregators this is not needed.
-			// We only want to send the next once the previous has been published successfully.
-			// TODO(omritoptix): Once we have leader election, we can add a condition.
-			m.batchInProcess.Store(false)
+			m.onSettlementBatchAccepted(ctx, eventData.EndHeight)
+		case <-ticker.C:
+			resultRetrieveBatch, err := m.getLatestBatchFromSL(ctx)
+			if err != nil {
+				if err != settlement.ErrBatchNotFound {
+					m.logger.Error("failed to retrieve batch from SL", "err", err)
+				}
+				continue
+			}
+			if resultRetrieveBatch.EndHeight > atomic.LoadUint64(&m.syncTarget) {
+				m.logger.Info("Found batch accepted by SL without event", "endHeight", resultRetrieveBatch.EndHeight)
+				m.onSettlementBatchAccepted(ctx, resultRetrieveBatch.EndHeight)
+			}
 		case <-subscription.Cancelled():
 			m.logger.Info("Subscription canceled")
 		}
 	}
 }
 
+// onSettlementBatchAccepted handles a batch accepted by the SL up to the given height.
+func (m *Manager) onSettlementBatchAccepted(ctx context.Context, endHeight uint64) {
+	m.updateSyncParams(ctx, endHeight)
+	// In case we are the aggregator and we've got an update, then we can release the accepted
+	// batches from the submission pipeline. For non-aggregators this is a no-op.
+	// TODO(omritoptix): Once we have leader election, we can add a condition.
+	m.releaseAcceptedBatches(endHeight)
+}
+
 // updateSyncParams updates the sync target and state index if necessary
 func (m *Manager) updateSyncParams(ctx context.Context, endHeight uint64) {
 	m.logger.Info("Received new syncTarget", "syncTarget", endHeight)
 	atomic.StoreUint64(&m.syncTarget, endHeight)
+	m.metrics.SyncTarget.Set(float64(endHeight))
 	m.syncTargetDiode.Set(diodes.GenericDataType(&endHeight))
+	m.updateSettledHeight(endHeight)
+}
+
+// updateSettledHeight persists the height of the last block accepted by the SL and publishes
+// it on the event bus in case it advanced.
+func (m *Manager) updateSettledHeight(height uint64) {
+	settledHeight, err := m.store.LoadSettledHeight()
+	if err != nil {
+		m.logger.Error("Failed to load settled height", "error", err)
+		return
+	}
+	if height <= settledHeight {
+		return
+	}
+	if _, err := m.store.SaveSettledHeight(height, nil); err != nil {
+		m.logger.Error("Failed to save settled height", "height", height, "error", err)
+		return
+	}
+	if m.eventBus == nil {
+		return
+	}
+	err = m.eventBus.Publish(types.EventNewSettledHeight, types.EventDataNewSettledHeight{Height: height})
+	if err != nil {
+		m.logger.Error("Failed to publish settled height event", "height", height, "error", err)
+	}
 }
 
 // RetriveLoop listens for new sync messages written to a ring buffer and in turn
@@ -334,31 +447,54 @@ func (m *Manager) RetriveLoop(ctx context.Context) {
 }
 
 // syncUntilTarget syncs the block until the syncTarget is reached.
-// It fetches the batches from the settlement, gets the DA height and gets
-// the actual blocks from the DA.
+// It fetches the upcoming batches from the settlement and their blocks from the DA
+// concurrently, and applies the blocks in order.
 func (m *Manager) syncUntilTarget(ctx context.Context, syncTarget uint64) {
 	currentHeight := m.store.Height()
-	for currentHeight < syncTarget {
-		m.logger.Info("Syncing until target", "current height", currentHeight, "syncTarget", syncTarget)
-		resultRetrieveBatch, err := m.settlementClient.RetrieveBatch(atomic.LoadUint64(&m.lastState.SLStateIndex) + 1)
-		if err != nil {
-			m.logger.Error("Failed to sync until target. error while retrieving batch", "error", err)
-			continue
+	if currentHeight >= syncTarget {
+		return
+	}
+	startIndex := atomic.LoadUint64(&m.lastState.SLStateIndex) + 1
+	resultRetrieveBatch, err := m.getLatestBatchFromSL(ctx)
+	if err != nil {
+		m.logger.Error("Failed to sync until target. error while retrieving latest batch", "error", err)
+		return
+	}
+	endIndex := resultRetrieveBatch.StateIndex
+	m.logger.Info("Syncing until target", "current height", currentHeight, "syncTarget", syncTarget, "startIndex", startIndex, "endIndex", endIndex)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	prefetcher := newBatchPrefetcher(m, m.conf.SyncPrefetchWindow, m.conf.SyncPrefetchMaxBytes)
+	for fetched := range prefetcher.start(ctx, startIndex, endIndex) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-fetched.done:
 		}
-		err = m.processNextDABatch(ctx, resultRetrieveBatch.MetaData.DA.Height)
+		if fetched.err != nil {
+			m.logger.Error("Failed to sync until target. error while fetching batch", "stateIndex", fetched.stateIndex, "error", fetched.err)
+			return
+		}
+		daHeight := fetched.slBatch.MetaData.DA.Height
+		err := m.applyDABatches(ctx, []*types.Batch{fetched.daBatch}, daHeight)
+		prefetcher.release(fetched)
 		if err != nil {
-			m.logger.Error("Failed to sync until target. error while processing next DA batch", "error", err)
-			break
+			m.logger.Error("Failed to sync until target. error while applying DA batch", "daHeight", daHeight, "error", err)
+			return
 		}
-		err = m.updateStateIndex(resultRetrieveBatch.StateIndex)
+		err = m.updateStateIndex(fetched.stateIndex)
 		if err != nil {
 			return
 		}
-		currentHeight = m.store.Height()
+		prefetchedBatches, prefetchedBytes := prefetcher.stats()
+		m.logger.Info("Synced batch", "stateIndex", fetched.stateIndex, "height", m.store.Height(), "syncTarget", syncTarget,
+			"prefetchedBatches", prefetchedBatches, "prefetchedBytes", prefetchedBytes)
 	}
 }
 
-// ApplyBlockLoop is responsible for applying blocks retrieved from pubsub server.
+// ApplyBlockLoop is responsible for applying blocks retrieved from pubsub server, and the blocks received
+// from peers while filling the gaps between them.
 func (m *Manager) ApplyBlockLoop(ctx context.Context) {
 	subscription, err := m.pubsub.Subscribe(ctx, "ApplyBlockLoop", p2p.EventQueryNewNewGossipedBlock, 100)
 	if err != nil {
@@ -372,10 +508,9 @@ func (m *Manager) ApplyBlockLoop(ctx context.Context) {
 			eventData := blockEvent.Data().(p2p.GossipedBlock)
 			block := eventData.Block
 			commit := eventData.Commit
-			err := m.applyBlock(ctx, &block, &commit, blockMetaData{source: gossipedBlock})
-			if err != nil {
-				continue
-			}
+			m.applyGossipedBlock(ctx, &block, &commit)
+		case synced := <-m.syncedBlocks:
+			m.applyGossipedBlock(ctx, synced.block, synced.commit)
 		case <-ctx.Done():
 			return
 		case <-subscription.Cancelled():
@@ -384,69 +519,124 @@ func (m *Manager) ApplyBlockLoop(ctx context.Context) {
 	}
 }
 
+// applyGossipedBlock caches the gossiped block in case it is ahead of the store height and then applies
+// the cached blocks in order, as long as there is no gap from the store height.
+func (m *Manager) applyGossipedBlock(ctx context.Context, block *types.Block, commit *types.Commit) {
+	height := block.Header.Height
+	storeHeight := m.store.Height()
+	if height > storeHeight && height <= storeHeight+syncCacheSize {
+		if _, ok := m.syncCache[height]; !ok {
+			// Blocks are validated before being cached, so an invalid block doesn't take the place of the
+			// valid block of its height
+			if err := m.validateSyncedBlock(block, commit); err != nil {
+				m.logger.Debug("Dropping invalid gossiped block", "height", height, "error", err)
+			} else {
+				m.syncCache[height] = &cachedBlock{block: block, commit: commit}
+			}
+		}
+	} else if height > storeHeight {
+		m.logger.Debug("Dropping gossiped block too far ahead of the store height", "height", height, "storeHeight", storeHeight)
+	}
+
+	// Evict the cached blocks which were already applied, e.g. from the DA layer
+	for cachedHeight := range m.syncCache {
+		if cachedHeight <= storeHeight {
+			delete(m.syncCache, cachedHeight)
+		}
+	}
+	for {
+		nextHeight := m.store.Height() + 1
+		cached, ok := m.syncCache[nextHeight]
+		if !ok {
+			break
+		}
+		delete(m.syncCache, nextHeight)
+		if err := m.applyBlock(ctx, cached.block, cached.commit, blockMetaData{source: gossipedBlock}); err != nil {
+			break
+		}
+	}
+	if len(m.syncCache) > 0 {
+		m.logger.Debug("Gossiped blocks waiting for missing blocks", "storeHeight", m.store.Height(), "cachedBlocks", len(m.syncCache))
+		m.requestMissingBlocks(ctx)
+	}
+}
+
 func (m *Manager) applyBlock(ctx context.Context, block *types.Block, commit *types.Commit, blockMetaData blockMetaData) error {
 	if block.Header.Height > m.store.Height() {
 		m.logger.Info("Applying block", "height", block.Header.Height, "source", blockMetaData.source)
 
-		_, err := m.store.SaveBlock(block, commit, nil)
+		proposer, err := m.blockProposer(&block.Header)
 		if err != nil {
-			m.logger.Error("failed to save block", "error", err)
+			m.logger.Error("failed to get block proposer", "error", err)
 			return err
 		}
-
-		// Currently we're assuming proposer is never nil as it's a pre-condition for
-		// dymint to start
-		proposer := m.settlementClient.GetProposer()
 		// Apply the block but DONT commit
 		newState, responses, err := m.executor.ApplyBlock(ctx, m.lastState, block, commit, proposer)
 		if err != nil {
 			m.logger.Error("failed to ApplyBlock", "error", err)
+			var fraudErr *fraudproof.FraudError
+			if errors.As(err, &fraudErr) && m.p2pClient != nil {
+				_ = m.gossipFraudProof(ctx, fraudErr.Proof)
+			}
 			return err
 		}
 
-		// Commit the new state and block which writes to disk on the proxy app
-		err = m.executor.Commit(ctx, &newState, block, responses)
-		if err != nil {
-			m.logger.Error("failed to Commit to the block", "error", err)
-			return err
-		}
+		return m.commitBlock(ctx, block, commit, newState, responses)
+	}
+	return nil
+}
 
-		batch := m.store.NewBatch()
+// commitBlock saves the executed block and commits it on the app, along with the resulting state.
+func (m *Manager) commitBlock(ctx context.Context, block *types.Block, commit *types.Commit, newState types.State, responses *tmstate.ABCIResponses) error {
+	_, err := m.store.SaveBlock(block, commit, nil)
+	if err != nil {
+		m.logger.Error("failed to save block", "error", err)
+		return err
+	}
 
-		// SaveBlockResponses commits the DB tx
-		batch, err = m.store.SaveBlockResponses(block.Header.Height, responses, batch)
-		if err != nil {
-			batch.Discard()
-			return err
-		}
+	// Commit the new state and block which writes to disk on the proxy app
+	err = m.executor.Commit(ctx, &newState, block, responses)
+	if err != nil {
+		m.logger.Error("failed to Commit to the block", "error", err)
+		return err
+	}
 
-		// After this call m.lastState is the NEW state returned from ApplyBlock
-		m.lastState = newState
+	batch := m.store.NewBatch()
 
-		// UpdateState commits the DB tx
-		batch, err = m.store.UpdateState(m.lastState, batch)
-		if err != nil {
-			batch.Discard()
-			return err
-		}
+	// SaveBlockResponses commits the DB tx
+	batch, err = m.store.SaveBlockResponses(block.Header.Height, responses, batch)
+	if err != nil {
+		batch.Discard()
+		return err
+	}
 
-		// SaveValidators commits the DB tx
-		batch, err = m.store.SaveValidators(block.Header.Height, m.lastState.Validators, batch)
-		if err != nil {
-			batch.Discard()
-			return err
-		}
+	// After this call m.lastState is the NEW state returned from ApplyBlock
+	m.lastState = newState
 
-		err = batch.Commit()
-		if err != nil {
-			m.logger.Error("failed to persist batch to disk", "error", err)
-			return err
-		}
+	// UpdateState commits the DB tx
+	batch, err = m.store.UpdateState(m.lastState, batch)
+	if err != nil {
+		batch.Discard()
+		return err
+	}
 
-		// Only update the stored height after successfully committing to the DB
-		m.store.SetHeight(block.Header.Height)
+	// SaveValidators commits the DB tx
+	batch, err = m.store.SaveValidators(block.Header.Height, m.lastState.Validators, batch)
+	if err != nil {
+		batch.Discard()
+		return err
+	}
 
+	err = batch.Commit()
+	if err != nil {
+		m.logger.Error("failed to persist batch to disk", "error", err)
+		return err
 	}
+
+	// Only update the stored height after successfully committing to the DB
+	m.store.SetHeight(block.Header.Height)
+	m.metrics.Height.Set(float64(block.Header.Height))
+
 	return nil
 }
 
@@ -465,15 +655,66 @@ func (m *Manager) gossipBlock(ctx context.Context, block types.Block, commit typ
 
 }
 
+func (m *Manager) gossipFraudProof(ctx context.Context, proof *fraudproof.FraudProof) error {
+	m.logger.Info("Gossiping fraud proof", "height", proof.Block.Header.Height, "step", proof.StepIndex)
+	proofBytes, err := proof.MarshalBinary()
+	if err != nil {
+		m.logger.Error("Failed to marshal fraud proof", "error", err)
+		return err
+	}
+	if err := m.p2pClient.GossipFraudProof(ctx, proofBytes); err != nil {
+		m.logger.Error("Failed to gossip fraud proof", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (m *Manager) processNextDABatch(ctx context.Context, daHeight uint64) error {
 	m.logger.Debug("trying to retrieve batch from DA", "daHeight", daHeight)
-	batchResp, err := m.fetchBatch(daHeight)
+	batchResp, err := m.fetchBatch(ctx, daHeight)
 	if err != nil {
 		m.logger.Error("failed to retrieve batch from DA", "daHeight", daHeight, "error", err)
 		return err
 	}
 	m.logger.Debug("retrieved batches", "n", len(batchResp.Batches), "daHeight", daHeight)
-	for _, batch := range batchResp.Batches {
+	return m.applyDABatches(ctx, batchResp.Batches, daHeight)
+}
+
+// selectDABatch returns the DA batch matching the SL batch. Anyone can post blobs to the namespace, so batches whose
+// height range doesn't match the SL batch, or whose blocks weren't signed by the proposer, are dropped.
+func (m *Manager) selectDABatch(slBatch *settlement.Batch, daBatches []*types.Batch) (*types.Batch, error) {
+	for _, batch := range daBatches {
+		if batch == nil || batch.StartHeight != slBatch.StartHeight || batch.EndHeight != slBatch.EndHeight {
+			continue
+		}
+		if err := m.validateDABatch(batch); err != nil {
+			m.logger.Info("Dropping invalid DA batch", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "error", err)
+			continue
+		}
+		return batch, nil
+	}
+	return nil, fmt.Errorf("no valid batch matching SL batch [%d, %d] at DA height %d", slBatch.StartHeight, slBatch.EndHeight, slBatch.MetaData.DA.Height)
+}
+
+// validateDABatch checks that the batch holds the blocks of its height range, each signed by the proposer.
+func (m *Manager) validateDABatch(batch *types.Batch) error {
+	if uint64(len(batch.Blocks)) != batch.EndHeight-batch.StartHeight+1 || len(batch.Commits) != len(batch.Blocks) {
+		return errors.New("number of blocks doesn't match batch height range")
+	}
+	for i, block := range batch.Blocks {
+		if block.Header.Height != batch.StartHeight+uint64(i) {
+			return fmt.Errorf("unexpected block height %d at position %d", block.Header.Height, i)
+		}
+		if err := m.validateSyncedBlock(block, batch.Commits[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDABatches applies the blocks of the batches retrieved from the given DA height.
+func (m *Manager) applyDABatches(ctx context.Context, batches []*types.Batch, daHeight uint64) error {
+	for _, batch := range batches {
 		for i, block := range batch.Blocks {
 			err := m.applyBlock(ctx, block, batch.Commits[i], blockMetaData{source: daBlock, daHeight: daHeight})
 			if err != nil {
@@ -484,18 +725,54 @@ func (m *Manager) processNextDABatch(ctx context.Context, daHeight uint64) error
 	return nil
 }
 
-func (m *Manager) fetchBatch(daHeight uint64) (da.ResultRetrieveBatch, error) {
+func (m *Manager) fetchBatch(ctx context.Context, daHeight uint64) (da.ResultRetrieveBatch, error) {
 	var err error
-	batchRes := m.retriever.RetrieveBatches(daHeight)
+	start := time.Now()
+	batchRes := m.retriever.RetrieveBatches(ctx, daHeight)
+	m.daMetrics.RetrieveDuration.Observe(time.Since(start).Seconds())
 	switch batchRes.Code {
 	case da.StatusError:
 		err = fmt.Errorf("failed to retrieve batch: %s", batchRes.Message)
 	case da.StatusTimeout:
 		err = fmt.Errorf("timeout during retrieve batch: %s", batchRes.Message)
 	}
+	if err == nil {
+		err = m.verifyBatchInclusion(ctx, daHeight, batchRes)
+	}
+	if err != nil {
+		m.daMetrics.RetrieveFailures.Add(1)
+	} else {
+		m.daMetrics.LastRetrievedHeight.Set(float64(daHeight))
+	}
 	return batchRes, err
 }
 
+// verifyBatchInclusion verifies the inclusion proofs of the retrieved batches against the DA header, if the DA client
+// is able to provide headers. Otherwise nothing proves the batches were included in the DA layer, and they are
+// refused unless the DA inclusion is trusted by configuration.
+func (m *Manager) verifyBatchInclusion(ctx context.Context, daHeight uint64, batchRes da.ResultRetrieveBatch) error {
+	headerRetriever, ok := m.retriever.(da.HeaderRetriever)
+	if !ok {
+		if len(batchRes.Batches) == 0 || m.conf.TrustDAInclusion {
+			return nil
+		}
+		return fmt.Errorf("%w: DA client %s doesn't provide inclusion proofs", da.ErrInvalidInclusionProof, m.dalc.GetClientType())
+	}
+	if len(batchRes.Proofs) != len(batchRes.Batches) {
+		return fmt.Errorf("%w: expected %d proofs, got %d", da.ErrInvalidInclusionProof, len(batchRes.Batches), len(batchRes.Proofs))
+	}
+	header, err := headerRetriever.RetrieveHeader(ctx, daHeight)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve DA header: %w", err)
+	}
+	for i, batch := range batchRes.Batches {
+		if err := batchRes.Proofs[i].Verify(header, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *Manager) produceBlock(ctx context.Context) error {
 	var lastCommit *types.Commit
 	var lastHeaderHash [32]byte
@@ -531,11 +808,22 @@ func (m *Manager) produceBlock(ctx context.Context) error {
 			m.logger.Error("Loaded block but failed to load commit", "height", newHeight, "error", err)
 			return err
 		}
+		if err := m.applyBlock(ctx, block, commit, blockMetaData{source: producedBlock}); err != nil {
+			return err
+		}
 	} else {
 		m.logger.Info("Creating block", "height", newHeight)
 		block = m.executor.CreateBlock(newHeight, lastCommit, lastHeaderHash, m.lastState)
 		m.logger.Debug("block info", "num_tx", len(block.Data.Txs))
 
+		// The block is executed before it's signed, as the execution sets the intermediate state roots the header
+		// commits to
+		newState, responses, err := m.executor.ExecuteBlock(ctx, m.lastState, block)
+		if err != nil {
+			m.logger.Error("failed to ExecuteBlock", "error", err)
+			return err
+		}
+
 		abciHeaderPb := abciconv.ToABCIHeaderPB(&block.Header)
 		abciHeaderBytes, err := abciHeaderPb.Marshal()
 		if err != nil {
@@ -551,53 +839,23 @@ func (m *Manager) produceBlock(ctx context.Context) error {
 			Signatures: []types.Signature{sign},
 		}
 
+		if err := m.commitBlock(ctx, block, commit, newState, responses); err != nil {
+			return err
+		}
 	}
 
-	// Gossip the block as soon as it is produced
 	if err := m.gossipBlock(ctx, *block, *commit); err != nil {
 		return err
 	}
 
-	if err := m.applyBlock(ctx, block, commit, blockMetaData{source: producedBlock}); err != nil {
-		return err
-	}
+	atomic.AddUint64(&m.pendingBatchBytes, blockEncodedSize(block, commit))
 
-	// Submit batch if we've reached the batch size and there isn't another batch currently in submission process.
-	// SyncTarget is the height of the last block in the last batch as seen by this node.
-	syncTarget := atomic.LoadUint64(&m.syncTarget)
-	if block.Header.Height-syncTarget >= m.conf.BlockBatchSize && m.batchInProcess.Load() == false {
-		m.batchInProcess.Store(true)
-		go m.submitNextBatch(ctx)
-	}
+	// Enqueue the next batch if we've reached one of the batch limits and there is room in the submission pipeline.
+	m.enqueueNextBatch(ctx)
 
 	return nil
 }
 
-func (m *Manager) submitNextBatch(ctx context.Context) {
-	// Get the batch start and end height
-	startHeight := atomic.LoadUint64(&m.syncTarget) + 1
-	endHeight := startHeight + m.conf.BlockBatchSize - 1
-	m.logger.Info("Submitting next batch", "startHeight", startHeight, "endHeight", endHeight)
-	// Create the batch
-	nextBatch, err := m.createNextDABatch(startHeight, endHeight)
-	if err != nil {
-		m.logger.Error("Failed to create next batch", "startHeight", startHeight, "endHeight", endHeight, "error", err)
-		return
-	}
-
-	// Submit batch to the DA
-	resultSubmitToDA, err := m.submitBatchToDA(ctx, nextBatch)
-	if err != nil {
-		m.logger.Error("Failed to submit next batch to DA Layer", "startHeight", startHeight, "endHeight", endHeight, "error", err)
-		return
-	}
-
-	// Submit batch to SL
-	// TODO(omritoptix): Handle a case where the SL submission fails due to syncTarget out of sync with the latestHeight in the SL.
-	// In that case we'll want to update the syncTarget before returning.
-	m.submitBatchToSL(ctx, nextBatch, resultSubmitToDA)
-}
-
 func (m *Manager) updateStateIndex(stateIndex uint64) error {
 	atomic.StoreUint64(&m.lastState.SLStateIndex, stateIndex)
 	_, err := m.store.UpdateState(m.lastState, nil)
@@ -616,9 +874,9 @@ func (m *Manager) createNextDABatch(startHeight uint64, endHeight uint64) (*type
 		Blocks:      make([]*types.Block, 0, m.conf.BlockBatchSize),
 		Commits:     make([]*types.Commit, 0, m.conf.BlockBatchSize),
 	}
-	// Populate the batch
+	// Populate the batch, starting with the size of the batch fields other than the blocks and commits
+	batchSize := uint64(batch.ToProto().Size())
 	for height := startHeight; height <= endHeight; height++ {
-		m.logger.Debug("Adding element to batch", "height", height)
 		block, err := m.store.LoadBlock(height)
 		if err != nil {
 			m.logger.Error("Failed to load block", "height", height)
@@ -629,13 +887,40 @@ func (m *Manager) createNextDABatch(startHeight uint64, endHeight uint64) (*type
 			m.logger.Error("Failed to load commit", "height", height)
 			return nil, err
 		}
+		// Stop adding blocks once the batch would exceed the max size. The first block is always added
+		// so a single oversized block doesn't stall the submission.
+		batchSize += blockEncodedSize(block, commit)
+		if m.conf.BlockBatchMaxSizeBytes > 0 && batchSize > m.conf.BlockBatchMaxSizeBytes && len(batch.Blocks) > 0 {
+			m.logger.Info("Batch max size reached, cutting batch", "startHeight", startHeight, "endHeight", height-1)
+			batch.EndHeight = height - 1
+			break
+		}
+		m.logger.Debug("Adding element to batch", "height", height)
 		batch.Blocks = append(batch.Blocks, block)
 		batch.Commits = append(batch.Commits, commit)
 	}
 	return batch, nil
 }
 
-func (m *Manager) submitBatchToSL(ctx context.Context, batch *types.Batch, resultSubmitToDA *da.ResultSubmitBatch) *settlement.ResultSubmitBatch {
+// blockEncodedSize returns the number of bytes the block and its commit add to an encoded batch.
+func blockEncodedSize(block *types.Block, commit *types.Commit) uint64 {
+	blockSize := block.ToProto().Size()
+	commitSize := commit.ToProto().Size()
+	// Every repeated field element is prefixed with a one byte tag and its varint encoded length
+	return uint64(2 + proto.SizeVarint(uint64(blockSize)) + blockSize + proto.SizeVarint(uint64(commitSize)) + commitSize)
+}
+
+// batchEncodedSize returns the sum of the encoded sizes of the batch blocks and commits.
+// It matches the sizes accumulated in pendingBatchBytes.
+func batchEncodedSize(batch *types.Batch) uint64 {
+	size := uint64(0)
+	for i := range batch.Blocks {
+		size += blockEncodedSize(batch.Blocks[i], batch.Commits[i])
+	}
+	return size
+}
+
+func (m *Manager) submitBatchToSL(ctx context.Context, batch *types.Batch, resultSubmitToDA *da.ResultSubmitBatch) (*settlement.ResultSubmitBatch, error) {
 	var resultSubmitToSL *settlement.ResultSubmitBatch
 	// Submit batch to SL
 	err := retry.Do(func() error {
@@ -647,17 +932,24 @@ func (m *Manager) submitBatchToSL(ctx context.Context, batch *types.Batch, resul
 		return nil
 	}, retry.Context(ctx), retry.LastErrorOnly(true))
 	if err != nil {
-		m.logger.Error("Failed to submit batch to SL Layer", batch, err)
-		panic(err)
+		m.logger.Error("Failed to submit batch to SL Layer", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "error", err)
+		return nil, err
 	}
-	return resultSubmitToSL
+	return resultSubmitToSL, nil
 }
 
 func (m *Manager) submitBatchToDA(ctx context.Context, batch *types.Batch) (*da.ResultSubmitBatch, error) {
 	var res da.ResultSubmitBatch
+	attempts := 0
+	start := time.Now()
 	err := retry.Do(func() error {
-		res = m.dalc.SubmitBatch(batch)
+		if attempts > 0 {
+			m.daMetrics.SubmitRetries.Add(1)
+		}
+		attempts++
+		res = m.dalc.SubmitBatch(ctx, batch)
 		if res.Code != da.StatusSuccess {
+			m.daMetrics.SubmitFailures.Add(1)
 			return fmt.Errorf("failed to submit batch to DA layer: %s", res.Message)
 		}
 		return nil
@@ -665,6 +957,12 @@ func (m *Manager) submitBatchToDA(ctx context.Context, batch *types.Batch) (*da.
 	if err != nil {
 		return nil, err
 	}
+	m.daMetrics.SubmitDuration.Observe(time.Since(start).Seconds())
+	m.daMetrics.SubmittedBytes.Add(float64(res.Size))
+	m.daMetrics.BlobsPerBatch.Observe(float64(res.Blobs))
+	m.daMetrics.FeesPaid.Add(float64(res.Fee))
+	m.daMetrics.SubmitClientRetries.Add(float64(res.Retries))
+	m.daMetrics.LastSubmittedHeight.Set(float64(res.DAHeight))
 	return &res, nil
 }
 
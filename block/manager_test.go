@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/avast/retry-go"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 
+	"github.com/dymensionxyz/dymint/faults"
 	"github.com/dymensionxyz/dymint/log/test"
 	mempoolv1 "github.com/dymensionxyz/dymint/mempool/v1"
 	"github.com/dymensionxyz/dymint/mocks"
@@ -17,6 +19,7 @@ import (
 	"github.com/dymensionxyz/dymint/settlement"
 	"github.com/dymensionxyz/dymint/testutil"
 	"github.com/dymensionxyz/dymint/types"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,7 +33,9 @@ import (
 
 	"github.com/dymensionxyz/dymint/config"
 	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/da/faulty"
 	mockda "github.com/dymensionxyz/dymint/da/mock"
+	dymintlog "github.com/dymensionxyz/dymint/log"
 	nodemempool "github.com/dymensionxyz/dymint/node/mempool"
 	slmock "github.com/dymensionxyz/dymint/settlement/mock"
 	slregistry "github.com/dymensionxyz/dymint/settlement/registry"
@@ -127,6 +132,7 @@ func TestWaitUntilSynced(t *testing.T) {
 	defer cancel()
 	// Run syncTargetLoop so that we update the syncTarget.
 	go manager.SyncTargetLoop(ctx)
+	go manager.SubmitLoop(ctx)
 	go manager.ProduceBlockLoop(ctx)
 	select {
 	case <-ctx.Done():
@@ -163,6 +169,31 @@ func TestWaitUntilSynced(t *testing.T) {
 	}
 }
 
+// TestSyncTargetPolling tests that batches accepted by the SL are picked up by polling the SL when their events are
+// never received.
+func TestSyncTargetPolling(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(&SettlementLayerClientDropEvents{}, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	manager.conf.BatchSyncInterval = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.SyncTargetLoop(ctx)
+	// let the loop retrieve the latest batch when it starts, before there is any
+	time.Sleep(200 * time.Millisecond)
+
+	batch, err := testutil.GenerateBatch(1, defaultBatchSize, manager.proposerKey)
+	require.NoError(err)
+	daResult := &da.ResultSubmitBatch{BaseResult: da.BaseResult{DAHeight: 1}}
+	resultSubmitBatch := manager.settlementClient.SubmitBatch(batch, manager.dalc.GetClientType(), daResult)
+	require.Equal(settlement.StatusSuccess, resultSubmitBatch.Code)
+
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&manager.syncTarget) == batch.EndHeight
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
 // TestPublishAfterSynced should test that we are resuming publishing blocks after we are synced
 // 1. Validate blocks are not produced by adding a batch and outsyncing the manager
 // 2. Sync the manager
@@ -181,7 +212,7 @@ func TestPublishAfterSynced(t *testing.T) {
 	for i := 0; i < numBatchesToAdd; i++ {
 		batch, err = testutil.GenerateBatch(nextBatchStartHeight, nextBatchStartHeight+uint64(defaultBatchSize-1), manager.proposerKey)
 		assert.NoError(t, err)
-		daResultSubmitBatch := manager.dalc.SubmitBatch(batch)
+		daResultSubmitBatch := manager.dalc.SubmitBatch(context.Background(), batch)
 		assert.Equal(t, daResultSubmitBatch.Code, da.StatusSuccess)
 		resultSubmitBatch := manager.settlementClient.SubmitBatch(batch, manager.dalc.GetClientType(), &daResultSubmitBatch)
 		assert.Equal(t, resultSubmitBatch.Code, settlement.StatusSuccess)
@@ -221,6 +252,295 @@ func TestPublishAfterSynced(t *testing.T) {
 	}
 }
 
+// TestSequencerRotation tests that the block production is handed over once the proposer is rotated on the SL.
+// 1. Validate blocks are produced and settled.
+// 2. Rotate the proposer to another sequencer and validate blocks are not produced.
+// 3. Rotate the proposer back and validate blocks are produced again.
+func TestSequencerRotation(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.SyncTargetLoop(ctx)
+	go manager.RetriveLoop(ctx)
+	go manager.ProposerLoop(ctx)
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&manager.syncTarget) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	rawKey, err := manager.proposerKey.GetPublic().Raw()
+	require.NoError(err)
+	address, err := getAddress(manager.proposerKey)
+	require.NoError(err)
+	sequencer := types.Sequencer{PublicKey: &ed25519.PubKey{Key: rawKey}}
+	otherSequencer := types.Sequencer{PublicKey: ed25519.GenPrivKey().PubKey()}
+	publishSequencers := func(proposer *types.Sequencer, inactive *types.Sequencer) {
+		proposer.Status = types.Proposer
+		inactive.Status = types.Inactive
+		err := manager.pubsub.PublishWithEvents(ctx, &settlement.EventDataSequencersListUpdated{
+			Sequencers: []types.Sequencer{sequencer, otherSequencer},
+		}, map[string][]string{settlement.EventTypeKey: {settlement.EventSequencersListUpdated}})
+		require.NoError(err)
+	}
+
+	t.Log("Rotating the proposer to another sequencer")
+	publishSequencers(&otherSequencer, &sequencer)
+	require.Eventually(func() bool {
+		return manager.settlementClient.GetProposer().PublicKey.Equals(otherSequencer.PublicKey)
+	}, time.Second, 10*time.Millisecond)
+	// Wait for the block production to stop
+	time.Sleep(500 * time.Millisecond)
+	storeHeight := manager.store.Height()
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(storeHeight, manager.store.Height())
+
+	// The settled blocks are verified against the sequencer which proposed them, the rest against the new proposer
+	settledHeight := atomic.LoadUint64(&manager.syncTarget)
+	proposer, err := manager.blockProposer(&types.Header{Height: settledHeight, ProposerAddress: address})
+	require.NoError(err)
+	assert.True(proposer.PublicKey.Equals(sequencer.PublicKey))
+	proposer, err = manager.blockProposer(&types.Header{Height: storeHeight + 1, ProposerAddress: address})
+	require.NoError(err)
+	assert.True(proposer.PublicKey.Equals(otherSequencer.PublicKey))
+
+	t.Log("Rotating the proposer back")
+	publishSequencers(&sequencer, &otherSequencer)
+	assert.Eventually(func() bool {
+		return manager.store.Height() > storeHeight
+	}, 2*time.Second, 50*time.Millisecond)
+}
+
+func TestSyncWithPrefetcher(t *testing.T) {
+	cases := []struct {
+		name     string
+		window   uint64
+		maxBytes uint64
+	}{
+		{"serial", 1, 0},
+		{"window", 4, 0},
+		{"memory cap", 4, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			assert := assert.New(t)
+			manager, err := getManager(nil, nil, 1, 1, 0, nil)
+			require.NoError(err)
+			manager.conf.SyncPrefetchWindow = c.window
+			manager.conf.SyncPrefetchMaxBytes = c.maxBytes
+
+			// Submit batches to the DA and the SL which the manager has to sync
+			numBatches := 6
+			var daResultSubmitBatch da.ResultSubmitBatch
+			for i := 0; i < numBatches; i++ {
+				startHeight := uint64(i*defaultBatchSize + 1)
+				batch, err := testutil.GenerateBatch(startHeight, startHeight+uint64(defaultBatchSize-1), manager.proposerKey)
+				require.NoError(err)
+				daResultSubmitBatch = manager.dalc.SubmitBatch(context.Background(), batch)
+				require.Equal(da.StatusSuccess, daResultSubmitBatch.Code)
+				resultSubmitBatch := manager.settlementClient.SubmitBatch(batch, manager.dalc.GetClientType(), &daResultSubmitBatch)
+				require.Equal(settlement.StatusSuccess, resultSubmitBatch.Code)
+			}
+			require.Eventually(func() bool {
+				return manager.dalc.CheckBatchAvailability(context.Background(), daResultSubmitBatch.DAHeight).DataAvailable
+			}, 5*time.Second, 50*time.Millisecond)
+
+			syncTarget := uint64(numBatches * defaultBatchSize)
+			manager.syncUntilTarget(context.Background(), syncTarget)
+			assert.Equal(syncTarget, manager.store.Height())
+			assert.Equal(uint64(numBatches), manager.lastState.SLStateIndex)
+		})
+	}
+}
+
+func TestApplyGossipedBlocksOutOfOrder(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, 8, manager.proposerKey)
+	require.NoError(err)
+	gossip := func(height uint64) {
+		manager.applyGossipedBlock(context.Background(), batch.Blocks[height-1], batch.Commits[height-1])
+	}
+
+	// Future blocks are cached until the gap is filled
+	gossip(3)
+	gossip(2)
+	assert.Equal(uint64(0), manager.store.Height())
+	assert.Len(manager.syncCache, 2)
+	gossip(1)
+	assert.Equal(uint64(3), manager.store.Height())
+	assert.Empty(manager.syncCache)
+
+	// Blocks too far ahead are not cached
+	farBatch, err := testutil.GenerateBatch(syncCacheSize+4, syncCacheSize+4, manager.proposerKey)
+	require.NoError(err)
+	manager.applyGossipedBlock(context.Background(), farBatch.Blocks[0], farBatch.Commits[0])
+	assert.Empty(manager.syncCache)
+
+	// Cached blocks which were applied from another source are evicted
+	gossip(6)
+	assert.Len(manager.syncCache, 1)
+	for height := 4; height <= 6; height++ {
+		err = manager.applyBlock(context.Background(), batch.Blocks[height-1], batch.Commits[height-1], blockMetaData{source: daBlock})
+		require.NoError(err)
+	}
+	gossip(8)
+	assert.Len(manager.syncCache, 1)
+	assert.NotContains(manager.syncCache, uint64(6))
+	gossip(7)
+	assert.Equal(uint64(8), manager.store.Height())
+	assert.Empty(manager.syncCache)
+}
+
+// TestApplyGossipedBlocksInvalidFirst tests that an invalid gossiped block doesn't prevent the valid block of
+// the same height from being cached.
+func TestApplyGossipedBlocksInvalidFirst(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, 3, manager.proposerKey)
+	require.NoError(err)
+	otherKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	invalidBatch, err := testutil.GenerateBatch(1, 3, otherKey)
+	require.NoError(err)
+
+	manager.applyGossipedBlock(context.Background(), invalidBatch.Blocks[2], invalidBatch.Commits[2])
+	require.Empty(manager.syncCache)
+	manager.applyGossipedBlock(context.Background(), batch.Blocks[2], batch.Commits[2])
+	manager.applyGossipedBlock(context.Background(), invalidBatch.Blocks[1], invalidBatch.Commits[1])
+	manager.applyGossipedBlock(context.Background(), batch.Blocks[1], batch.Commits[1])
+	require.Len(manager.syncCache, 2)
+	manager.applyGossipedBlock(context.Background(), batch.Blocks[0], batch.Commits[0])
+	require.Equal(uint64(3), manager.store.Height())
+	require.Empty(manager.syncCache)
+}
+
+// TestDeliverSyncedBlocks tests that the blocks received from peers are applied by the ApplyBlockLoop without
+// going through its gossiped blocks subscription, which keeps receiving gossiped blocks afterwards.
+func TestDeliverSyncedBlocks(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.ApplyBlockLoop(ctx)
+
+	// More blocks than the capacity of the gossiped blocks subscription
+	numBlocks := 250
+	batch, err := testutil.GenerateBatch(1, uint64(numBlocks+1), manager.proposerKey)
+	require.NoError(err)
+	synced := &types.Batch{Blocks: batch.Blocks[:numBlocks], Commits: batch.Commits[:numBlocks]}
+	require.NoError(manager.deliverSyncedBlocks(ctx, synced))
+
+	gossipedBlock := p2p.GossipedBlock{Block: *batch.Blocks[numBlocks], Commit: *batch.Commits[numBlocks]}
+	require.Eventually(func() bool {
+		// Publish again until the subscription of the ApplyBlockLoop is registered
+		if manager.store.Height() == uint64(numBlocks) {
+			err := manager.pubsub.PublishWithEvents(ctx, gossipedBlock, map[string][]string{p2p.EventTypeKey: {p2p.EventNewGossipedBlock}})
+			require.NoError(err)
+		}
+		return manager.store.Height() == uint64(numBlocks+1)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Invalid blocks are not delivered
+	otherKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	otherBatch, err := testutil.GenerateBatch(uint64(numBlocks+2), uint64(numBlocks+2), otherKey)
+	require.NoError(err)
+	require.Error(manager.deliverSyncedBlocks(ctx, otherBatch))
+}
+
+func TestValidateSyncedBlock(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, 2, manager.proposerKey)
+	require.NoError(err)
+
+	for i, block := range batch.Blocks {
+		require.NoError(manager.validateSyncedBlock(block, batch.Commits[i]))
+	}
+
+	// Commit of another block
+	require.Error(manager.validateSyncedBlock(batch.Blocks[0], batch.Commits[1]))
+
+	// Block which was tampered after it was signed
+	tampered := *batch.Blocks[1]
+	tampered.Header.Time++
+	tamperedCommit := *batch.Commits[1]
+	tamperedCommit.HeaderHash = tampered.Header.Hash()
+	require.ErrorIs(manager.validateSyncedBlock(&tampered, &tamperedCommit), types.ErrInvalidSignature)
+
+	// Block signed by someone other than the proposer
+	otherKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	otherBatch, err := testutil.GenerateBatch(1, 1, otherKey)
+	require.NoError(err)
+	require.ErrorIs(manager.validateSyncedBlock(otherBatch.Blocks[0], otherBatch.Commits[0]), types.ErrInvalidSignature)
+}
+
+func TestSelectDABatch(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, 3, manager.proposerKey)
+	require.NoError(err)
+	slBatch := &settlement.Batch{StartHeight: 1, EndHeight: 3, MetaData: &settlement.BatchMetaData{DA: &settlement.DAMetaData{Height: 1}}}
+
+	// Batch posted to the namespace by someone other than the proposer
+	otherKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	foreignBatch, err := testutil.GenerateBatch(1, 3, otherKey)
+	require.NoError(err)
+	// Batch of the proposer which doesn't match the SL batch
+	otherRangeBatch, err := testutil.GenerateBatch(1, 2, manager.proposerKey)
+	require.NoError(err)
+	// Batch claiming the SL batch range while missing blocks
+	truncatedBatch := *batch
+	truncatedBatch.Blocks = batch.Blocks[:2]
+	truncatedBatch.Commits = batch.Commits[:2]
+
+	selected, err := manager.selectDABatch(slBatch, []*types.Batch{nil, foreignBatch, otherRangeBatch, &truncatedBatch, batch})
+	require.NoError(err)
+	require.Equal(batch, selected)
+
+	_, err = manager.selectDABatch(slBatch, []*types.Batch{nil, foreignBatch, otherRangeBatch, &truncatedBatch})
+	require.Error(err)
+}
+
+func TestUpdateSettledHeight(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	manager.eventBus = tmtypes.NewEventBus()
+	require.NoError(manager.eventBus.Start())
+	defer func() {
+		_ = manager.eventBus.Stop()
+	}()
+	subscription, err := manager.eventBus.Subscribe(context.Background(), "test", types.EventQueryNewSettledHeight, 10)
+	require.NoError(err)
+
+	manager.updateSettledHeight(5)
+	settledHeight, err := manager.store.LoadSettledHeight()
+	require.NoError(err)
+	require.Equal(uint64(5), settledHeight)
+	event := <-subscription.Out()
+	require.Equal(types.EventDataNewSettledHeight{Height: 5}, event.Data())
+
+	// Heights which were already settled are ignored
+	manager.updateSettledHeight(3)
+	settledHeight, err = manager.store.LoadSettledHeight()
+	require.NoError(err)
+	require.Equal(uint64(5), settledHeight)
+	require.Empty(subscription.Out())
+}
+
 func TestPublishWhenSettlementLayerDisconnected(t *testing.T) {
 	manager, err := getManager(&SettlementLayerClientSubmitBatchError{}, nil, 1, 1, 0, nil)
 	retry.DefaultAttempts = 2
@@ -230,16 +550,13 @@ func TestPublishWhenSettlementLayerDisconnected(t *testing.T) {
 	nextBatchStartHeight := atomic.LoadUint64(&manager.syncTarget) + 1
 	batch, err := testutil.GenerateBatch(nextBatchStartHeight, nextBatchStartHeight+uint64(defaultBatchSize-1), manager.proposerKey)
 	assert.NoError(t, err)
-	daResultSubmitBatch := manager.dalc.SubmitBatch(batch)
+	daResultSubmitBatch := manager.dalc.SubmitBatch(context.Background(), batch)
 	assert.Equal(t, daResultSubmitBatch.Code, da.StatusSuccess)
 	resultSubmitBatch := manager.settlementClient.SubmitBatch(batch, manager.dalc.GetClientType(), &daResultSubmitBatch)
 	assert.Equal(t, resultSubmitBatch.Code, settlement.StatusError)
 
-	defer func() {
-		err := recover().(error)
-		assert.ErrorContains(t, err, connectionRefusedErrorMessage)
-	}()
-	manager.submitBatchToSL(context.Background(), nil, nil)
+	_, err = manager.submitBatchToSL(context.Background(), batch, &daResultSubmitBatch)
+	assert.ErrorContains(t, err, connectionRefusedErrorMessage)
 }
 
 func TestPublishWhenDALayerDisconnected(t *testing.T) {
@@ -251,7 +568,7 @@ func TestPublishWhenDALayerDisconnected(t *testing.T) {
 	nextBatchStartHeight := atomic.LoadUint64(&manager.syncTarget) + 1
 	batch, err := testutil.GenerateBatch(nextBatchStartHeight, nextBatchStartHeight+uint64(defaultBatchSize-1), manager.proposerKey)
 	assert.NoError(t, err)
-	daResultSubmitBatch := manager.dalc.SubmitBatch(batch)
+	daResultSubmitBatch := manager.dalc.SubmitBatch(context.Background(), batch)
 	assert.Equal(t, daResultSubmitBatch.Code, da.StatusError)
 
 	_, err = manager.submitBatchToDA(context.Background(), nil)
@@ -267,6 +584,155 @@ func TestRetrieveDaBatchesFailed(t *testing.T) {
 	assert.ErrorContains(t, err, batchNotFoundErrorMessage)
 }
 
+func TestRetrieveDaBatchesInvalidProof(t *testing.T) {
+	require := require.New(t)
+	dalc := &DALayerClientTamperBatches{}
+	manager, err := getManager(nil, dalc, 1, 1, 0, nil)
+	require.NoError(err)
+	for i := 0; i < defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	daResult := manager.dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, daResult.Code)
+	require.Eventually(func() bool {
+		return manager.dalc.CheckBatchAvailability(context.Background(), daResult.DAHeight).DataAvailable
+	}, 5*time.Second, 50*time.Millisecond)
+
+	_, err = manager.fetchBatch(context.Background(), daResult.DAHeight)
+	require.NoError(err)
+
+	dalc.tamper = true
+	_, err = manager.fetchBatch(context.Background(), daResult.DAHeight)
+	assert.ErrorIs(t, err, da.ErrInvalidInclusionProof)
+}
+
+func TestRetrieveDaBatchesUnproven(t *testing.T) {
+	require := require.New(t)
+	inner := &mockda.DataAvailabilityLayerClient{}
+	manager, err := getManager(nil, &DALayerClientNoProofs{DataAvailabilityLayerClient: inner, BatchRetriever: inner}, 1, 1, 0, nil)
+	require.NoError(err)
+	for i := 0; i < defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	daResult := manager.dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, daResult.Code)
+	require.Eventually(func() bool {
+		return manager.dalc.CheckBatchAvailability(context.Background(), daResult.DAHeight).DataAvailable
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// nothing proves the inclusion of the batch, unless it's trusted
+	_, err = manager.fetchBatch(context.Background(), daResult.DAHeight)
+	assert.ErrorIs(t, err, da.ErrInvalidInclusionProof)
+	manager.conf.TrustDAInclusion = true
+	batchRes, err := manager.fetchBatch(context.Background(), daResult.DAHeight)
+	require.NoError(err)
+	assert.Len(t, batchRes.Batches, 1)
+}
+
+func TestRetrieveDaBatchesCorrupted(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+
+	// the DA layer corrupts every retrieved batch
+	dalc := faulty.NewDataAvailabilityLayerClient(&mockda.DataAvailabilityLayerClient{})
+	conf, err := json.Marshal(faulty.Config{Config: faults.Config{CorruptRate: 1}, InnerConfig: manager.conf.DABlockTime.String()})
+	require.NoError(err)
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), log.TestingLogger()))
+	require.NoError(dalc.Start())
+	manager.SetDALC(dalc)
+
+	for i := 0; i < defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	daResult := manager.dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, daResult.Code)
+	time.Sleep(2 * manager.conf.DABlockTime)
+
+	_, err = manager.fetchBatch(context.Background(), daResult.DAHeight)
+	assert.ErrorIs(t, err, da.ErrInvalidInclusionProof)
+	assert.Equal(t, uint64(defaultBatchSize), manager.store.Height())
+}
+
+func TestDAMetrics(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	daMetrics := da.NopMetrics()
+	submittedBytes := generic.NewCounter("submitted_bytes")
+	blobsPerBatch := generic.NewHistogram("blobs_per_batch", 10)
+	lastSubmittedHeight := generic.NewGauge("last_submitted_height")
+	lastRetrievedHeight := generic.NewGauge("last_retrieved_height")
+	retrieveFailures := generic.NewCounter("retrieve_failures")
+	daMetrics.SubmittedBytes = submittedBytes
+	daMetrics.BlobsPerBatch = blobsPerBatch
+	daMetrics.LastSubmittedHeight = lastSubmittedHeight
+	daMetrics.LastRetrievedHeight = lastRetrievedHeight
+	daMetrics.RetrieveFailures = retrieveFailures
+	manager.SetMetrics(NopMetrics(), daMetrics)
+	// Disable the batch submission so only the batch below is submitted
+	manager.conf.BlockBatchSize = 1000
+
+	for i := 0; i < defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	daResult, err := manager.submitBatchToDA(context.Background(), batch)
+	require.NoError(err)
+	blob, err := types.EncodeBatch(batch, types.DefaultBatchCodec)
+	require.NoError(err)
+	assert.Equal(float64(len(blob)), submittedBytes.Value())
+	assert.Equal(float64(1), blobsPerBatch.Quantile(0.5))
+	assert.Equal(float64(daResult.DAHeight), lastSubmittedHeight.Value())
+
+	time.Sleep(2 * manager.conf.DABlockTime)
+	_, err = manager.fetchBatch(context.Background(), daResult.DAHeight)
+	require.NoError(err)
+	assert.Equal(float64(daResult.DAHeight), lastRetrievedHeight.Value())
+	_, err = manager.fetchBatch(context.Background(), daResult.DAHeight+1000)
+	require.Error(err)
+	assert.Equal(float64(1), retrieveFailures.Value())
+}
+
+// TestDASubmitRetriesMetrics tests that the retries of the node and the retries reported by the DA client are counted
+// separately.
+func TestDASubmitRetriesMetrics(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	dalc := &DALayerClientSubmitBatchFlaky{failures: 1, retries: 2}
+	manager, err := getManager(nil, dalc, 1, 1, 0, nil)
+	require.NoError(err)
+	daMetrics := da.NopMetrics()
+	submitRetries := generic.NewCounter("submit_retries")
+	submitClientRetries := generic.NewCounter("submit_client_retries")
+	daMetrics.SubmitRetries = submitRetries
+	daMetrics.SubmitClientRetries = submitClientRetries
+	manager.SetMetrics(NopMetrics(), daMetrics)
+	manager.conf.BlockBatchSize = 1000
+
+	for i := 0; i < defaultBatchSize; i++ {
+		require.NoError(manager.produceBlock(context.Background()))
+	}
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	_, err = manager.submitBatchToDA(context.Background(), batch)
+	require.NoError(err)
+	assert.Equal(float64(1), submitRetries.Value())
+	assert.Equal(float64(2), submitClientRetries.Value())
+}
+
 func TestProduceNewBlock(t *testing.T) {
 	// Init app
 	app := &mocks.Application{}
@@ -356,6 +822,289 @@ func TestProduceBlockIfFailAfterCommit(t *testing.T) {
 	assert.Equal(t, infoHash, manager.lastState.AppHash)
 }
 
+func TestCreateNextDABatchWithBytesLimit(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	// Disable the batch submission so we can create the batch manually
+	manager.conf.BlockBatchSize = 1000
+
+	// Produce blocks
+	for i := 0; i < defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	fullBatch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	assert.Len(fullBatch.Blocks, defaultBatchSize)
+	assert.Equal(batchEncodedSize(fullBatch), atomic.LoadUint64(&manager.pendingBatchBytes))
+
+	// Limit the batch size so only the first two blocks fit
+	emptyBatch := &types.Batch{StartHeight: 1, EndHeight: defaultBatchSize}
+	manager.conf.BlockBatchMaxSizeBytes = uint64(emptyBatch.ToProto().Size()) +
+		blockEncodedSize(fullBatch.Blocks[0], fullBatch.Commits[0]) +
+		blockEncodedSize(fullBatch.Blocks[1], fullBatch.Commits[1])
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	assert.Len(batch.Blocks, 2)
+	assert.Len(batch.Commits, 2)
+	assert.Equal(uint64(2), batch.EndHeight)
+	assert.LessOrEqual(uint64(batch.ToProto().Size()), manager.conf.BlockBatchMaxSizeBytes)
+
+	// A single block which exceeds the limit is still added
+	manager.conf.BlockBatchMaxSizeBytes = 1
+	batch, err = manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	assert.Len(batch.Blocks, 1)
+	assert.Equal(uint64(1), batch.EndHeight)
+}
+
+func TestShouldSubmitBatch(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	manager.conf.BlockBatchSize = 100
+	manager.conf.BatchSubmitMaxTime = 0
+	manager.conf.BlockBatchMaxSizeBytes = 0
+
+	assert.False(manager.shouldSubmitBatch(0, 0))
+	assert.False(manager.shouldSubmitBatch(0, 1))
+	assert.True(manager.shouldSubmitBatch(0, 100))
+	assert.False(manager.shouldSubmitBatch(100, 199))
+	assert.True(manager.shouldSubmitBatch(100, 200))
+
+	// Size limit
+	manager.conf.BlockBatchMaxSizeBytes = 1000
+	atomic.StoreUint64(&manager.pendingBatchBytes, 1000)
+	assert.True(manager.shouldSubmitBatch(0, 1))
+	manager.releasePendingBatchBytes(2000)
+	assert.Equal(uint64(0), atomic.LoadUint64(&manager.pendingBatchBytes))
+	assert.False(manager.shouldSubmitBatch(0, 1))
+
+	// Time limit
+	manager.conf.BatchSubmitMaxTime = time.Minute
+	assert.False(manager.shouldSubmitBatch(0, 1))
+	atomic.StoreInt64(&manager.lastSubmissionTime, time.Now().Add(-time.Hour).UnixNano())
+	assert.True(manager.shouldSubmitBatch(0, 1))
+}
+
+func TestPipelinedBatchSubmission(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	manager.conf.MaxBatchesInFlight = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.SyncTargetLoop(ctx)
+	go manager.SubmitLoop(ctx)
+
+	numBatches := 4
+	for i := 0; i < numBatches*defaultBatchSize; i++ {
+		err = manager.produceBlock(ctx)
+		require.NoError(err)
+	}
+	manager.batchesInFlightMu.Lock()
+	assert.LessOrEqual(len(manager.batchesInFlight), 3)
+	manager.batchesInFlightMu.Unlock()
+
+	// Wait until all the batches are accepted by the SL
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&manager.syncTarget) == uint64(numBatches*defaultBatchSize)
+	}, 10*time.Second, 50*time.Millisecond)
+	resultRetrieveBatch, err := manager.settlementClient.RetrieveBatch()
+	require.NoError(err)
+	assert.Equal(uint64(numBatches*defaultBatchSize), resultRetrieveBatch.EndHeight)
+	manager.batchesInFlightMu.Lock()
+	assert.Empty(manager.batchesInFlight)
+	manager.batchesInFlightMu.Unlock()
+}
+
+func TestPipelinedBatchSubmissionWithRejectedBatch(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	retry.DefaultAttempts = 2
+	// Reject the second batch for the first round of retries
+	settlementlc := &SettlementLayerClientRejectBatch{rejectStartHeight: defaultBatchSize + 1, rejectCount: uint32(retry.DefaultAttempts)}
+	manager, err := getManager(settlementlc, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	manager.conf.MaxBatchesInFlight = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.SyncTargetLoop(ctx)
+	go manager.SubmitLoop(ctx)
+
+	numBatches := 3
+	for i := 0; i < numBatches*defaultBatchSize; i++ {
+		err = manager.produceBlock(ctx)
+		require.NoError(err)
+	}
+
+	// All the batches are eventually accepted by the SL in order
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&manager.syncTarget) == uint64(numBatches*defaultBatchSize)
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(uint32(0), atomic.LoadUint32(&settlementlc.rejectCount))
+	for i := 1; i <= numBatches; i++ {
+		resultRetrieveBatch, err := manager.settlementClient.RetrieveBatch(uint64(i))
+		require.NoError(err)
+		assert.Equal(uint64((i-1)*defaultBatchSize+1), resultRetrieveBatch.StartHeight)
+		assert.Equal(uint64(i*defaultBatchSize), resultRetrieveBatch.EndHeight)
+	}
+}
+
+// TestDroppedBatchNotPersisted tests that a DA submission finishing after its batch was dropped from the pipeline
+// doesn't persist the batch again.
+func TestDroppedBatchNotPersisted(t *testing.T) {
+	require := require.New(t)
+	dalc := &DALayerClientSubmitBatchBlocked{release: make(chan struct{})}
+	manager, err := getManager(nil, dalc, 1, 1, 0, nil)
+	require.NoError(err)
+	for i := 0; i < defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	manager.batchesInFlightMu.Lock()
+	require.Len(manager.batchesInFlight, 1)
+	manager.dropBatchesLocked(0)
+	manager.batchesInFlightMu.Unlock()
+
+	close(dalc.release)
+	manager.daPosts.Wait()
+	submissions, err := manager.store.LoadBatchSubmissions()
+	require.NoError(err)
+	require.Empty(submissions)
+}
+
+// TestEnqueueAfterRollback tests that the batches created after dropping batches which don't continue the SL batches
+// start right after the height settled on the SL.
+func TestEnqueueAfterRollback(t *testing.T) {
+	require := require.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	// The sync target is ahead of the SL, so the first batch doesn't continue the SL batches
+	atomic.StoreUint64(&manager.syncTarget, 2)
+	for i := 0; i < defaultBatchSize+2; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+	manager.batchesInFlightMu.Lock()
+	require.Len(manager.batchesInFlight, 1)
+	require.Equal(uint64(3), manager.batchesInFlight[0].batch.StartHeight)
+	manager.batchesInFlightMu.Unlock()
+
+	manager.rollbackBatches(context.Background())
+	manager.batchesInFlightMu.Lock()
+	require.Empty(manager.batchesInFlight)
+	manager.batchesInFlightMu.Unlock()
+	require.Zero(atomic.LoadUint64(&manager.syncTarget))
+
+	manager.enqueueNextBatch(context.Background())
+	manager.batchesInFlightMu.Lock()
+	require.Len(manager.batchesInFlight, 1)
+	require.Equal(uint64(1), manager.batchesInFlight[0].batch.StartHeight)
+	require.Equal(uint64(defaultBatchSize), manager.batchesInFlight[0].batch.EndHeight)
+	manager.batchesInFlightMu.Unlock()
+	manager.daPosts.Wait()
+}
+
+func TestResumeBatchSubmissions(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	manager, err := getManager(nil, nil, 1, 1, 0, nil)
+	require.NoError(err)
+	// Disable the batch submission so only the persisted batches are submitted
+	manager.conf.BlockBatchSize = 1000
+	for i := 0; i < 2*defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+
+	// Persist the submission state as if the node was stopped in the middle of the submission.
+	// The last batch is beyond the store height so it can't be loaded.
+	persisted := []*types.BatchSubmission{
+		{StartHeight: 1, EndHeight: defaultBatchSize, Status: types.BatchSubmissionSubmittedToSL},
+		{StartHeight: defaultBatchSize + 1, EndHeight: 2 * defaultBatchSize, Status: types.BatchSubmissionPostedToDA},
+		{StartHeight: 2*defaultBatchSize + 1, EndHeight: 3 * defaultBatchSize, Status: types.BatchSubmissionCreated},
+	}
+	for _, submission := range persisted {
+		_, err = manager.store.SaveBatchSubmission(submission, nil)
+		require.NoError(err)
+	}
+	require.NoError(manager.loadBatchSubmissions())
+	require.Len(manager.batchesInFlight, 2)
+	submissions, err := manager.store.LoadBatchSubmissions()
+	require.NoError(err)
+	assert.Len(submissions, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.SyncTargetLoop(ctx)
+	go manager.SubmitLoop(ctx)
+
+	// The persisted batches are submitted and removed from the store once accepted
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&manager.syncTarget) == uint64(2*defaultBatchSize)
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Eventually(func() bool {
+		submissions, err := manager.store.LoadBatchSubmissions()
+		return err == nil && len(submissions) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestResumeBatchSubmissionsWithDAResult(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	dalc := &DALayerClientSubmitBatchCounter{}
+	manager, err := getManager(nil, dalc, 1, 1, 0, nil)
+	require.NoError(err)
+	// Disable the batch submission so only the persisted batches are submitted
+	manager.conf.BlockBatchSize = 1000
+	for i := 0; i < 2*defaultBatchSize; i++ {
+		err = manager.produceBlock(context.Background())
+		require.NoError(err)
+	}
+
+	// Post the first batch to the DA layer as if the node was stopped before submitting it to the SL
+	batch, err := manager.createNextDABatch(1, defaultBatchSize)
+	require.NoError(err)
+	daResult := manager.dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, daResult.Code)
+	require.Eventually(func() bool {
+		return manager.dalc.CheckBatchAvailability(context.Background(), daResult.DAHeight).DataAvailable
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// The second batch points to a DA height which doesn't contain it, so it has to be posted again
+	persisted := []*types.BatchSubmission{
+		{StartHeight: 1, EndHeight: defaultBatchSize, Status: types.BatchSubmissionPostedToDA, DAHeight: daResult.DAHeight, DAMessage: daResult.Message},
+		{StartHeight: defaultBatchSize + 1, EndHeight: 2 * defaultBatchSize, Status: types.BatchSubmissionPostedToDA, DAHeight: daResult.DAHeight + 1000},
+	}
+	for _, submission := range persisted {
+		_, err = manager.store.SaveBatchSubmission(submission, nil)
+		require.NoError(err)
+	}
+	require.NoError(manager.loadBatchSubmissions())
+	require.Len(manager.batchesInFlight, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.SyncTargetLoop(ctx)
+	go manager.SubmitLoop(ctx)
+
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&manager.syncTarget) == uint64(2*defaultBatchSize)
+	}, 10*time.Second, 50*time.Millisecond)
+	// Only the second batch was posted again
+	assert.Equal(uint32(2), atomic.LoadUint32(&dalc.submitCount))
+	resultRetrieveBatch, err := manager.settlementClient.RetrieveBatch(1)
+	require.NoError(err)
+	assert.Equal(daResult.DAHeight, resultRetrieveBatch.MetaData.DA.Height)
+}
+
 /* -------------------------------------------------------------------------- */
 /*                                    utils                                   */
 /* -------------------------------------------------------------------------- */
@@ -489,18 +1238,108 @@ func (s *SettlementLayerClientSubmitBatchError) SubmitBatch(_ *types.Batch, _ da
 	}
 }
 
+type SettlementLayerClientRejectBatch struct {
+	slmock.SettlementLayerClient
+	rejectStartHeight uint64
+	rejectCount       uint32
+}
+
+func (s *SettlementLayerClientRejectBatch) SubmitBatch(batch *types.Batch, daClient da.Client, daResult *da.ResultSubmitBatch) *settlement.ResultSubmitBatch {
+	if batch.StartHeight == s.rejectStartHeight && atomic.LoadUint32(&s.rejectCount) > 0 {
+		atomic.AddUint32(&s.rejectCount, ^uint32(0))
+		return &settlement.ResultSubmitBatch{
+			BaseResult: settlement.BaseResult{Code: settlement.StatusError, Message: connectionRefusedErrorMessage},
+		}
+	}
+	return s.SettlementLayerClient.SubmitBatch(batch, daClient, daResult)
+}
+
+// SettlementLayerClientDropEvents publishes its events to a pubsub server nobody listens to.
+type SettlementLayerClientDropEvents struct {
+	slmock.SettlementLayerClient
+}
+
+func (s *SettlementLayerClientDropEvents) Init(config []byte, _ *pubsub.Server, logger dymintlog.Logger, options ...settlement.Option) error {
+	pubsubServer := pubsub.NewServer()
+	if err := pubsubServer.Start(); err != nil {
+		return err
+	}
+	return s.SettlementLayerClient.Init(config, pubsubServer, logger, options...)
+}
+
 type DALayerClientSubmitBatchError struct {
 	mockda.DataAvailabilityLayerClient
 }
 
-func (s *DALayerClientSubmitBatchError) SubmitBatch(_ *types.Batch) da.ResultSubmitBatch {
+func (s *DALayerClientSubmitBatchError) SubmitBatch(_ context.Context, _ *types.Batch) da.ResultSubmitBatch {
 	return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: connectionRefusedErrorMessage}}
 }
 
+type DALayerClientSubmitBatchCounter struct {
+	mockda.DataAvailabilityLayerClient
+	submitCount uint32
+}
+
+func (s *DALayerClientSubmitBatchCounter) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	atomic.AddUint32(&s.submitCount, 1)
+	return s.DataAvailabilityLayerClient.SubmitBatch(ctx, batch)
+}
+
+// DALayerClientSubmitBatchFlaky fails the first submissions, then reports retries of its own on the next ones.
+type DALayerClientSubmitBatchFlaky struct {
+	mockda.DataAvailabilityLayerClient
+	failures uint32
+	retries  int
+}
+
+func (s *DALayerClientSubmitBatchFlaky) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	if atomic.LoadUint32(&s.failures) > 0 {
+		atomic.AddUint32(&s.failures, ^uint32(0))
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: connectionRefusedErrorMessage}}
+	}
+	res := s.DataAvailabilityLayerClient.SubmitBatch(ctx, batch)
+	res.Retries = s.retries
+	return res
+}
+
+// DALayerClientSubmitBatchBlocked blocks the batch submissions until release is closed.
+type DALayerClientSubmitBatchBlocked struct {
+	mockda.DataAvailabilityLayerClient
+	release chan struct{}
+}
+
+func (s *DALayerClientSubmitBatchBlocked) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	<-s.release
+	return s.DataAvailabilityLayerClient.SubmitBatch(ctx, batch)
+}
+
 type DALayerClientRetrieveBatchesError struct {
 	mockda.DataAvailabilityLayerClient
 }
 
-func (m *DALayerClientRetrieveBatchesError) RetrieveBatches(_ uint64) da.ResultRetrieveBatch {
+func (m *DALayerClientRetrieveBatchesError) RetrieveBatches(_ context.Context, _ uint64) da.ResultRetrieveBatch {
 	return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: batchNotFoundErrorMessage}}
 }
+
+// DALayerClientNoProofs hides the DA headers of the wrapped client, as the DA clients which can't prove the inclusion
+// of the batches.
+type DALayerClientNoProofs struct {
+	da.DataAvailabilityLayerClient
+	da.BatchRetriever
+}
+
+// DALayerClientTamperBatches drops the last block of the retrieved batches when tamper is set.
+type DALayerClientTamperBatches struct {
+	mockda.DataAvailabilityLayerClient
+	tamper bool
+}
+
+func (m *DALayerClientTamperBatches) RetrieveBatches(ctx context.Context, daHeight uint64) da.ResultRetrieveBatch {
+	res := m.DataAvailabilityLayerClient.RetrieveBatches(ctx, daHeight)
+	if m.tamper {
+		for _, batch := range res.Batches {
+			batch.Blocks = batch.Blocks[:len(batch.Blocks)-1]
+		}
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+package block
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "block_manager"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Height of the last block saved in the store.
+	Height metrics.Gauge
+
+	// Height the node is syncing to, as read from the settlement layer.
+	SyncTarget metrics.Gauge
+
+	// Number of settlement batches which were fetched ahead during sync and are waiting to be applied.
+	PrefetchedBatches metrics.Gauge
+
+	// Size in bytes of the prefetched batches which are waiting to be applied.
+	PrefetchedBytes metrics.Gauge
+
+	// Histogram of the time it takes to fetch a settlement batch and its blocks from the DA layer, in seconds.
+	BatchFetchDuration metrics.Histogram
+
+	// Number of settlement batches which failed to be fetched during sync.
+	BatchFetchFailures metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Height: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "height",
+			Help:      "Height of the last block saved in the store.",
+		}, labels).With(labelsAndValues...),
+
+		SyncTarget: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sync_target",
+			Help:      "Height the node is syncing to, as read from the settlement layer.",
+		}, labels).With(labelsAndValues...),
+
+		PrefetchedBatches: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "prefetched_batches",
+			Help:      "Number of settlement batches fetched ahead during sync and waiting to be applied.",
+		}, labels).With(labelsAndValues...),
+
+		PrefetchedBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "prefetched_bytes",
+			Help:      "Size in bytes of the prefetched batches waiting to be applied.",
+		}, labels).With(labelsAndValues...),
+
+		BatchFetchDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "batch_fetch_duration_seconds",
+			Help:      "Time it takes to fetch a settlement batch and its blocks from the DA layer, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 12),
+		}, labels).With(labelsAndValues...),
+
+		BatchFetchFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "batch_fetch_failures",
+			Help:      "Number of settlement batches which failed to be fetched during sync.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Height:             discard.NewGauge(),
+		SyncTarget:         discard.NewGauge(),
+		PrefetchedBatches:  discard.NewGauge(),
+		PrefetchedBytes:    discard.NewGauge(),
+		BatchFetchDuration: discard.NewHistogram(),
+		BatchFetchFailures: discard.NewCounter(),
+	}
+}
@@ -0,0 +1,432 @@
+package block
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+const (
+	// initialSubmitBackoff is the delay before retrying a batch submission which ran out of retries
+	initialSubmitBackoff = time.Second
+	// maxSubmitBackoff is the max delay between batch submission retries
+	maxSubmitBackoff = 5 * time.Minute
+)
+
+// batchSubmission tracks a single batch through the submission pipeline.
+// Batches are posted to the DA layer as soon as they are created, while the submission
+// to the SL happens strictly in order by the SubmitLoop.
+// The status of the submission is persisted in the store, so it can be resumed after a restart.
+type batchSubmission struct {
+	batch *types.Batch
+	// daDone is closed once the DA submission finished (either successfully or not)
+	daDone   chan struct{}
+	daResult *da.ResultSubmitBatch
+	daErr    error
+	status   types.BatchSubmissionStatus
+	// dropped is set once the batch was removed from the pipeline before being accepted, so a DA submission
+	// which is still running doesn't persist it again
+	dropped bool
+}
+
+// SubmitLoop is responsible for submitting the batches in the pipeline to the SL.
+// Batches are submitted in order, each one once its DA submission is done.
+func (m *Manager) SubmitLoop(ctx context.Context) {
+	m.resumeBatchSubmissions(ctx)
+	backoff := initialSubmitBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.submitBatchCh:
+			m.submitPendingBatches(ctx, &backoff)
+		}
+	}
+}
+
+// submitPendingBatches submits all the batches which weren't submitted to the SL yet.
+// Failed submissions are retried with an exponential backoff.
+func (m *Manager) submitPendingBatches(ctx context.Context, backoff *time.Duration) {
+	for ctx.Err() == nil {
+		submission := m.nextBatchForSL()
+		if submission == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-submission.daDone:
+		}
+		batch := submission.batch
+		if submission.daErr != nil {
+			m.logger.Error("Failed to submit batch to DA Layer, retrying", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "backoff", *backoff, "error", submission.daErr)
+			if !m.waitSubmitBackoff(ctx, backoff) {
+				return
+			}
+			m.daMetrics.SubmitRetries.Add(1)
+			submission.daDone = make(chan struct{})
+			m.startPostBatchToDA(ctx, submission)
+			continue
+		}
+		m.logger.Info("Submitting batch to SL", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "daHeight", submission.daResult.DAHeight)
+		if _, err := m.submitBatchToSL(ctx, batch, submission.daResult); err != nil {
+			m.logger.Error("Batch wasn't submitted to SL, retrying", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "backoff", *backoff)
+			if !m.waitSubmitBackoff(ctx, backoff) {
+				return
+			}
+			m.rollbackBatches(ctx)
+			continue
+		}
+		*backoff = initialSubmitBackoff
+		m.batchesInFlightMu.Lock()
+		m.setSubmissionStatus(submission, types.BatchSubmissionSubmittedToSL)
+		m.batchesInFlightMu.Unlock()
+	}
+}
+
+// waitSubmitBackoff waits for the given backoff and doubles it for the next time, up to maxSubmitBackoff.
+// It returns false in case the context was canceled while waiting.
+func (m *Manager) waitSubmitBackoff(ctx context.Context, backoff *time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(*backoff):
+	}
+	*backoff *= 2
+	if *backoff > maxSubmitBackoff {
+		*backoff = maxSubmitBackoff
+	}
+	return true
+}
+
+// enqueueNextBatch creates the next batch in case one of the batch limits was reached and there is room
+// in the pipeline. The batch is posted to the DA layer right away.
+func (m *Manager) enqueueNextBatch(ctx context.Context) {
+	m.batchesInFlightMu.Lock()
+	defer m.batchesInFlightMu.Unlock()
+
+	if uint64(len(m.batchesInFlight)) >= m.conf.MaxBatchesInFlight {
+		return
+	}
+	lastQueuedHeight := atomic.LoadUint64(&m.syncTarget)
+	if len(m.batchesInFlight) > 0 {
+		lastQueuedHeight = m.batchesInFlight[len(m.batchesInFlight)-1].batch.EndHeight
+	}
+	storeHeight := m.store.Height()
+	if !m.shouldSubmitBatch(lastQueuedHeight, storeHeight) {
+		return
+	}
+
+	atomic.StoreInt64(&m.lastSubmissionTime, time.Now().UnixNano())
+	// Get the batch start and end height. The batch might be cut before reaching the batch size
+	// in case the time or size limits were reached.
+	startHeight := lastQueuedHeight + 1
+	endHeight := startHeight + m.conf.BlockBatchSize - 1
+	if storeHeight < endHeight {
+		endHeight = storeHeight
+	}
+	m.logger.Info("Creating next batch", "startHeight", startHeight, "endHeight", endHeight, "batchesInFlight", len(m.batchesInFlight))
+	batch, err := m.createNextDABatch(startHeight, endHeight)
+	if err != nil {
+		m.logger.Error("Failed to create next batch", "startHeight", startHeight, "endHeight", endHeight, "error", err)
+		return
+	}
+	submission := &batchSubmission{batch: batch, daDone: make(chan struct{}), status: types.BatchSubmissionCreated}
+	if err := m.saveBatchSubmission(submission); err != nil {
+		return
+	}
+	m.releasePendingBatchBytes(batchEncodedSize(batch))
+
+	m.batchesInFlight = append(m.batchesInFlight, submission)
+	m.startPostBatchToDA(ctx, submission)
+	m.notifySubmitLoop()
+}
+
+// shouldSubmitBatch checks whether any of the batch limits was reached since lastHeight: the number of blocks,
+// the accumulated size of the blocks or the time passed since the last submission.
+func (m *Manager) shouldSubmitBatch(lastHeight uint64, height uint64) bool {
+	if height <= lastHeight {
+		return false
+	}
+	if height-lastHeight >= m.conf.BlockBatchSize {
+		return true
+	}
+	if m.conf.BlockBatchMaxSizeBytes > 0 && atomic.LoadUint64(&m.pendingBatchBytes) >= m.conf.BlockBatchMaxSizeBytes {
+		m.logger.Debug("Batch max size reached", "pendingBatchBytes", atomic.LoadUint64(&m.pendingBatchBytes))
+		return true
+	}
+	lastSubmissionTime := time.Unix(0, atomic.LoadInt64(&m.lastSubmissionTime))
+	if m.conf.BatchSubmitMaxTime > 0 && time.Since(lastSubmissionTime) >= m.conf.BatchSubmitMaxTime {
+		m.logger.Debug("Batch max time reached", "lastSubmissionTime", lastSubmissionTime)
+		return true
+	}
+	return false
+}
+
+// startPostBatchToDA posts the batch to the DA layer in the background. The running posts are tracked by daPosts.
+func (m *Manager) startPostBatchToDA(ctx context.Context, submission *batchSubmission) {
+	m.daPosts.Add(1)
+	go func() {
+		defer m.daPosts.Done()
+		m.postBatchToDA(ctx, submission)
+	}()
+}
+
+// postBatchToDA submits the batch to the DA layer and signals the SubmitLoop once done.
+func (m *Manager) postBatchToDA(ctx context.Context, submission *batchSubmission) {
+	defer close(submission.daDone)
+	daResult, err := m.submitBatchToDA(ctx, submission.batch)
+
+	m.batchesInFlightMu.Lock()
+	defer m.batchesInFlightMu.Unlock()
+	submission.daResult, submission.daErr = daResult, err
+	if err == nil {
+		m.setSubmissionStatus(submission, types.BatchSubmissionPostedToDA)
+	}
+}
+
+// nextBatchForSL returns the first batch in the pipeline which wasn't submitted to the SL yet.
+func (m *Manager) nextBatchForSL() *batchSubmission {
+	m.batchesInFlightMu.Lock()
+	defer m.batchesInFlightMu.Unlock()
+	for _, submission := range m.batchesInFlight {
+		if submission.status < types.BatchSubmissionSubmittedToSL {
+			return submission
+		}
+	}
+	return nil
+}
+
+// releaseAcceptedBatches removes the batches which were accepted by the SL from the pipeline.
+func (m *Manager) releaseAcceptedBatches(endHeight uint64) {
+	m.batchesInFlightMu.Lock()
+	defer m.batchesInFlightMu.Unlock()
+	m.releaseAcceptedBatchesLocked(endHeight)
+	m.notifySubmitLoop()
+}
+
+func (m *Manager) releaseAcceptedBatchesLocked(endHeight uint64) {
+	i := 0
+	for i < len(m.batchesInFlight) && m.batchesInFlight[i].batch.EndHeight <= endHeight {
+		m.batchesInFlight[i].status = types.BatchSubmissionAccepted
+		i++
+	}
+	if i == 0 {
+		return
+	}
+	m.logger.Info("Batches accepted by SL", "startHeight", m.batchesInFlight[0].batch.StartHeight, "endHeight", m.batchesInFlight[i-1].batch.EndHeight)
+	m.deleteBatchSubmissions(m.batchesInFlight[:i])
+	m.batchesInFlight = m.batchesInFlight[i:]
+}
+
+// dropBatchesLocked removes the batches starting from the given height from the pipeline, so
+// they will be created again by the next produced block.
+func (m *Manager) dropBatchesLocked(startHeight uint64) {
+	for i, submission := range m.batchesInFlight {
+		if submission.batch.StartHeight < startHeight {
+			continue
+		}
+		for _, dropped := range m.batchesInFlight[i:] {
+			dropped.dropped = true
+			atomic.AddUint64(&m.pendingBatchBytes, batchEncodedSize(dropped.batch))
+		}
+		m.deleteBatchSubmissions(m.batchesInFlight[i:])
+		m.batchesInFlight = m.batchesInFlight[:i]
+		return
+	}
+}
+
+// rollbackBatches is called once the SL rejected a batch. It gets the latest batch accepted by the SL,
+// releases the batches which were already accepted and marks the rest for resubmission. The DA results
+// are reused in case the remaining batches continue the SL batches, otherwise they are dropped.
+func (m *Manager) rollbackBatches(ctx context.Context) {
+	var settledHeight uint64
+	resultRetrieveBatch, err := m.getLatestBatchFromSL(ctx)
+	if err == settlement.ErrBatchNotFound {
+		settledHeight = uint64(m.genesis.InitialHeight - 1)
+	} else if err != nil {
+		m.logger.Error("Failed to get latest batch from SL, keeping batches for resubmission", "error", err)
+		return
+	} else {
+		settledHeight = resultRetrieveBatch.EndHeight
+	}
+
+	m.batchesInFlightMu.Lock()
+	defer m.batchesInFlightMu.Unlock()
+	m.releaseAcceptedBatchesLocked(settledHeight)
+	// The next batches are created from the sync target, so it must follow the SL for the dropped batches not
+	// to be created again with the same heights.
+	atomic.StoreUint64(&m.syncTarget, settledHeight)
+	if len(m.batchesInFlight) == 0 {
+		return
+	}
+	if m.batchesInFlight[0].batch.StartHeight != settledHeight+1 {
+		m.logger.Info("Batches in pipeline don't continue the SL batches, dropping them", "settledHeight", settledHeight, "startHeight", m.batchesInFlight[0].batch.StartHeight)
+		m.dropBatchesLocked(m.batchesInFlight[0].batch.StartHeight)
+		return
+	}
+	m.logger.Info("Rolling back batches for resubmission", "settledHeight", settledHeight, "batchesInFlight", len(m.batchesInFlight))
+	for _, submission := range m.batchesInFlight {
+		if submission.status == types.BatchSubmissionSubmittedToSL {
+			m.setSubmissionStatus(submission, types.BatchSubmissionPostedToDA)
+		}
+	}
+}
+
+// loadBatchSubmissions loads the batch submissions persisted in the store into the pipeline.
+// The DA results of the batches which were posted to the DA layer are loaded as well, so they are
+// submitted only to the SL in case the batch is still available on the DA layer.
+func (m *Manager) loadBatchSubmissions() error {
+	submissions, err := m.store.LoadBatchSubmissions()
+	if err != nil {
+		return err
+	}
+	for i, persisted := range submissions {
+		batch, err := m.createNextDABatch(persisted.StartHeight, persisted.EndHeight)
+		if err == nil && batch.EndHeight != persisted.EndHeight {
+			err = fmt.Errorf("batch was cut at height %d", batch.EndHeight)
+		}
+		if err != nil {
+			// The rest of the batches will be created again by the next produced block
+			m.logger.Error("Failed to load batch for resubmission, dropping persisted batches", "startHeight", persisted.StartHeight, "endHeight", persisted.EndHeight, "error", err)
+			for _, dropped := range submissions[i:] {
+				if _, err := m.store.DeleteBatchSubmission(dropped.StartHeight, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		m.logger.Info("Loaded batch for resubmission", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "status", persisted.Status)
+		submission := &batchSubmission{
+			batch:  batch,
+			daDone: make(chan struct{}),
+			status: persisted.Status,
+		}
+		// There is no way to tell whether a batch submitted to the SL before the restart will be accepted,
+		// so it is submitted again. In case it was accepted the resubmission fails and the batch is released.
+		if submission.status == types.BatchSubmissionSubmittedToSL {
+			submission.status = types.BatchSubmissionPostedToDA
+		}
+		if submission.status == types.BatchSubmissionPostedToDA {
+			submission.daResult = &da.ResultSubmitBatch{
+				BaseResult: da.BaseResult{Code: da.StatusSuccess, Message: persisted.DAMessage, DAHeight: persisted.DAHeight},
+				Chunked:    persisted.DAChunked,
+			}
+		}
+		m.batchesInFlight = append(m.batchesInFlight, submission)
+	}
+	return nil
+}
+
+// resumeBatchSubmissions resumes the submission of the batches loaded from the store after a restart.
+// The batches which were already accepted by the SL are released. The batches which were posted to the DA layer
+// and are still available there are submitted only to the SL, while the rest are posted to the DA layer again.
+func (m *Manager) resumeBatchSubmissions(ctx context.Context) {
+	m.batchesInFlightMu.Lock()
+	resumed := len(m.batchesInFlight)
+	m.batchesInFlightMu.Unlock()
+	if resumed == 0 {
+		return
+	}
+	m.logger.Info("Resuming batch submissions", "batches", resumed)
+	m.rollbackBatches(ctx)
+
+	m.batchesInFlightMu.Lock()
+	submissions := make([]*batchSubmission, len(m.batchesInFlight))
+	copy(submissions, m.batchesInFlight)
+	m.batchesInFlightMu.Unlock()
+
+	for _, submission := range submissions {
+		if submission.status == types.BatchSubmissionPostedToDA {
+			daHeight := submission.daResult.DAHeight
+			resultCheck := m.dalc.CheckBatchAvailability(ctx, daHeight)
+			if resultCheck.Code == da.StatusSuccess && resultCheck.DataAvailable {
+				m.logger.Info("Batch is available on DA layer, submitting only to SL", "startHeight", submission.batch.StartHeight, "endHeight", submission.batch.EndHeight, "daHeight", daHeight)
+				close(submission.daDone)
+				continue
+			}
+			m.logger.Info("Batch is not available on DA layer, posting it again", "startHeight", submission.batch.StartHeight, "endHeight", submission.batch.EndHeight, "daHeight", daHeight, "error", resultCheck.Message)
+			m.batchesInFlightMu.Lock()
+			submission.daResult = nil
+			m.setSubmissionStatus(submission, types.BatchSubmissionCreated)
+			m.batchesInFlightMu.Unlock()
+		}
+		m.startPostBatchToDA(ctx, submission)
+	}
+	m.notifySubmitLoop()
+}
+
+// setSubmissionStatus updates the status of the submission and persists it.
+// It must be called while holding batchesInFlightMu.
+// Accepted and dropped submissions were already removed from the store, so their status is left as is.
+func (m *Manager) setSubmissionStatus(submission *batchSubmission, status types.BatchSubmissionStatus) {
+	if submission.status == types.BatchSubmissionAccepted || submission.dropped {
+		return
+	}
+	submission.status = status
+	_ = m.saveBatchSubmission(submission)
+}
+
+func (m *Manager) saveBatchSubmission(submission *batchSubmission) error {
+	persisted := &types.BatchSubmission{
+		StartHeight: submission.batch.StartHeight,
+		EndHeight:   submission.batch.EndHeight,
+		Status:      submission.status,
+	}
+	if submission.daResult != nil {
+		persisted.DAHeight = submission.daResult.DAHeight
+		persisted.DAMessage = submission.daResult.Message
+		persisted.DAChunked = submission.daResult.Chunked
+	}
+	_, err := m.store.SaveBatchSubmission(persisted, nil)
+	if err != nil {
+		m.logger.Error("Failed to save batch submission", "startHeight", submission.batch.StartHeight, "endHeight", submission.batch.EndHeight, "status", submission.status, "error", err)
+	}
+	return err
+}
+
+func (m *Manager) deleteBatchSubmissions(submissions []*batchSubmission) {
+	batch := m.store.NewBatch()
+	var err error
+	for _, submission := range submissions {
+		batch, err = m.store.DeleteBatchSubmission(submission.batch.StartHeight, batch)
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = batch.Commit()
+	} else {
+		batch.Discard()
+	}
+	if err != nil {
+		m.logger.Error("Failed to delete batch submissions", "error", err)
+	}
+}
+
+func (m *Manager) notifySubmitLoop() {
+	select {
+	case m.submitBatchCh <- struct{}{}:
+	default:
+	}
+}
+
+// releasePendingBatchBytes subtracts the size of a newly created batch from the pending bytes.
+// The pending bytes are not persisted, so after a restart a batch might be bigger than the pending bytes.
+func (m *Manager) releasePendingBatchBytes(size uint64) {
+	for {
+		pending := atomic.LoadUint64(&m.pendingBatchBytes)
+		newPending := uint64(0)
+		if pending > size {
+			newPending = pending - size
+		}
+		if atomic.CompareAndSwapUint64(&m.pendingBatchBytes, pending, newPending) {
+			return
+		}
+	}
+}
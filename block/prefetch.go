@@ -0,0 +1,150 @@
+package block
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go"
+
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// prefetchedBatch is a settlement batch together with the DA batch it points to, fetched ahead of time.
+type prefetchedBatch struct {
+	stateIndex uint64
+	slBatch    *settlement.ResultRetrieveBatch
+	daBatch    *types.Batch
+	size       uint64
+	err        error
+	// done is closed once the fetch finished (either successfully or not)
+	done chan struct{}
+}
+
+// batchPrefetcher fetches the upcoming settlement batches and their blocks from the DA layer concurrently,
+// while the blocks are applied strictly in order. The number of batches fetched ahead is bounded by the
+// window and the size of the fetched batches waiting to be applied is bounded by maxBytes.
+type batchPrefetcher struct {
+	m        *Manager
+	maxBytes uint64
+	// slots bounds the number of batches which are fetched or waiting to be applied
+	slots chan struct{}
+
+	mu       sync.Mutex
+	bytes    uint64
+	batches  int
+	released chan struct{}
+}
+
+func newBatchPrefetcher(m *Manager, window uint64, maxBytes uint64) *batchPrefetcher {
+	if window == 0 {
+		window = 1
+	}
+	return &batchPrefetcher{
+		m:        m,
+		maxBytes: maxBytes,
+		slots:    make(chan struct{}, window),
+		released: make(chan struct{}, 1),
+	}
+}
+
+// start fetches the batches at the state indexes [startIndex, endIndex] in the background.
+// The batches are sent in order on the returned channel, which is closed once all the fetches were started.
+// Each batch must be released once applied.
+func (p *batchPrefetcher) start(ctx context.Context, startIndex uint64, endIndex uint64) <-chan *prefetchedBatch {
+	out := make(chan *prefetchedBatch, cap(p.slots))
+	go func() {
+		defer close(out)
+		for index := startIndex; index <= endIndex; index++ {
+			select {
+			case <-ctx.Done():
+				return
+			case p.slots <- struct{}{}:
+			}
+			if !p.waitForMemory(ctx) {
+				return
+			}
+			fetched := &prefetchedBatch{stateIndex: index, done: make(chan struct{})}
+			go p.fetch(ctx, fetched)
+			out <- fetched
+		}
+	}()
+	return out
+}
+
+// waitForMemory blocks while the size of the fetched batches waiting to be applied exceeds maxBytes.
+func (p *batchPrefetcher) waitForMemory(ctx context.Context) bool {
+	for {
+		p.mu.Lock()
+		full := p.maxBytes > 0 && p.bytes >= p.maxBytes
+		p.mu.Unlock()
+		if !full {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-p.released:
+		}
+	}
+}
+
+// fetch gets the settlement batch at the state index and then the blocks it points to from the DA layer.
+func (p *batchPrefetcher) fetch(ctx context.Context, fetched *prefetchedBatch) {
+	defer close(fetched.done)
+	start := time.Now()
+	fetched.err = retry.Do(func() error {
+		slBatch, err := p.m.settlementClient.RetrieveBatch(fetched.stateIndex)
+		if err != nil {
+			return err
+		}
+		daBatches, err := p.m.fetchBatch(ctx, slBatch.MetaData.DA.Height)
+		if err != nil {
+			return err
+		}
+		daBatch, err := p.m.selectDABatch(slBatch.Batch, daBatches.Batches)
+		if err != nil {
+			return err
+		}
+		fetched.slBatch, fetched.daBatch = slBatch, daBatch
+		return nil
+	}, retry.Context(ctx), retry.LastErrorOnly(true))
+	if fetched.err != nil {
+		p.m.metrics.BatchFetchFailures.Add(1)
+		return
+	}
+	p.m.metrics.BatchFetchDuration.Observe(time.Since(start).Seconds())
+
+	fetched.size = batchEncodedSize(fetched.daBatch)
+	p.mu.Lock()
+	p.bytes += fetched.size
+	p.batches++
+	p.m.metrics.PrefetchedBytes.Set(float64(p.bytes))
+	p.m.metrics.PrefetchedBatches.Set(float64(p.batches))
+	p.mu.Unlock()
+}
+
+// release frees the memory and the window slot of an applied batch.
+func (p *batchPrefetcher) release(fetched *prefetchedBatch) {
+	if fetched.err == nil {
+		p.mu.Lock()
+		p.bytes -= fetched.size
+		p.batches--
+		p.m.metrics.PrefetchedBytes.Set(float64(p.bytes))
+		p.m.metrics.PrefetchedBatches.Set(float64(p.batches))
+		p.mu.Unlock()
+	}
+	<-p.slots
+	select {
+	case p.released <- struct{}{}:
+	default:
+	}
+}
+
+// stats returns the number and the size of the fetched batches waiting to be applied.
+func (p *batchPrefetcher) stats() (int, uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.batches, p.bytes
+}
@@ -0,0 +1,175 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+var errNoProposer = errors.New("no proposer in the sequencers list")
+
+// ErrNotProposer is returned when the node doesn't sign the blocks with the key of the proposer on the SL.
+var ErrNotProposer = errors.New("signing key is not the key of the proposer on the settlement layer")
+
+// blockProducer runs the loops producing blocks and submitting batches while the node is the proposer.
+type blockProducer struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// ProposerLoop runs the block production and the batch submission as long as the node is the proposer. It must be
+// run only by the nodes allowed to produce blocks, i.e. aggregators, and starts the production right away.
+// It follows the updates of the sequencers list on the SL: the production is stopped once another sequencer
+// becomes the proposer, and started again, after syncing to the last settled height, once the node becomes
+// the proposer.
+func (m *Manager) ProposerLoop(ctx context.Context) {
+	subscription, err := m.pubsub.Subscribe(ctx, "proposerLoop", settlement.EventQuerySequencersListUpdated, 100)
+	if err != nil {
+		m.logger.Error("failed to subscribe to sequencers list events")
+		panic(err)
+	}
+	producer := m.startProducing(ctx)
+	defer func() {
+		if producer != nil {
+			m.stopProducing(producer)
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-subscription.Cancelled():
+			m.logger.Info("Subscription canceled")
+			return
+		case event := <-subscription.Out():
+			eventData := event.Data().(*settlement.EventDataSequencersListUpdated)
+			isProposer := m.isProposer(eventData.Sequencers)
+			if isProposer && producer == nil {
+				m.logger.Info("Became the proposer, starting block production")
+				producer = m.startProducing(ctx)
+			} else if !isProposer && producer != nil {
+				m.logger.Info("No longer the proposer, stopping block production")
+				m.stopProducing(producer)
+				producer = nil
+			}
+		}
+	}
+}
+
+// startProducing starts the block production and the batch submission. The block production
+// starts only once the node is synced to the last height settled on the SL.
+func (m *Manager) startProducing(ctx context.Context) *blockProducer {
+	ctx, cancel := context.WithCancel(ctx)
+	producer := &blockProducer{cancel: cancel}
+	producer.wg.Add(2)
+	go func() {
+		defer producer.wg.Done()
+		m.ProduceBlockLoop(ctx)
+	}()
+	go func() {
+		defer producer.wg.Done()
+		m.SubmitLoop(ctx)
+	}()
+	return producer
+}
+
+// stopProducing stops the block production and the batch submission and waits for them to return.
+// The batches in the pipeline are dropped, as the SL accepts batches only from the proposer.
+func (m *Manager) stopProducing(producer *blockProducer) {
+	producer.cancel()
+	// Wake up the block production in case it is still waiting for the sync
+	m.isSyncedCond.L.Lock()
+	m.isSyncedCond.Broadcast()
+	m.isSyncedCond.L.Unlock()
+	producer.wg.Wait()
+	// The DA submissions are canceled with the production, wait for them so none of them updates a dropped batch
+	m.daPosts.Wait()
+
+	m.batchesInFlightMu.Lock()
+	defer m.batchesInFlightMu.Unlock()
+	m.dropBatchesLocked(0)
+}
+
+// CheckProposer checks that the node signs the blocks with the key of the current proposer on the SL, as
+// otherwise the blocks it produces are rejected by the rest of the nodes.
+func (m *Manager) CheckProposer() error {
+	proposer := m.settlementClient.GetProposer()
+	if proposer == nil {
+		return errNoProposer
+	}
+	rawKey, err := m.proposerKey.GetPublic().Raw()
+	if err != nil {
+		return err
+	}
+	if !m.isSequencerKey(proposer) {
+		var proposerKey []byte
+		if proposer.PublicKey != nil {
+			proposerKey = proposer.PublicKey.Bytes()
+		}
+		return fmt.Errorf("%w: signing key %X, proposer key %X", ErrNotProposer, rawKey, proposerKey)
+	}
+	return nil
+}
+
+// isProposer checks whether the proposer in the sequencers list is signing with the key of the node.
+func (m *Manager) isProposer(sequencers []types.Sequencer) bool {
+	for i := range sequencers {
+		if sequencers[i].Status == types.Proposer {
+			return m.isSequencerKey(&sequencers[i])
+		}
+	}
+	return false
+}
+
+// isSequencerKey checks whether the key of the sequencer is the signing key of the node.
+func (m *Manager) isSequencerKey(sequencer *types.Sequencer) bool {
+	rawKey, err := m.proposerKey.GetPublic().Raw()
+	if err != nil {
+		m.logger.Error("failed to get the raw public key", "error", err)
+		return false
+	}
+	return sequencer.PublicKey != nil && bytes.Equal(sequencer.PublicKey.Bytes(), rawKey)
+}
+
+// isSequencerPubKey checks whether the raw public key is the key of a sequencer in the sequencers list.
+func (m *Manager) isSequencerPubKey(pubKey []byte) bool {
+	for _, sequencer := range m.settlementClient.GetSequencersList() {
+		if sequencer.PublicKey != nil && bytes.Equal(sequencer.PublicKey.Bytes(), pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockProposer returns the sequencer the commit of the block should be signed by. It is the current proposer,
+// unless the block was already settled on the SL by another sequencer, i.e. before the proposer was rotated.
+func (m *Manager) blockProposer(header *types.Header) (*types.Sequencer, error) {
+	proposer := m.settlementClient.GetProposer()
+	if header.Height <= atomic.LoadUint64(&m.syncTarget) && (proposer == nil || !bytes.Equal(sequencerAddress(proposer), header.ProposerAddress)) {
+		for _, sequencer := range m.settlementClient.GetSequencersList() {
+			if bytes.Equal(sequencerAddress(sequencer), header.ProposerAddress) {
+				return sequencer, nil
+			}
+		}
+	}
+	if proposer == nil {
+		return nil, errNoProposer
+	}
+	return proposer, nil
+}
+
+// sequencerAddress returns the address the blocks proposed by the sequencer are stamped with.
+func sequencerAddress(sequencer *types.Sequencer) []byte {
+	if sequencer.PublicKey == nil {
+		return nil
+	}
+	return tmcrypto.AddressHash(sequencer.PublicKey.Bytes())
+}
@@ -9,17 +9,25 @@ import (
 )
 
 const (
-	flagAggregator        = "dymint.aggregator"
-	flagDALayer           = "dymint.da_layer"
-	flagDAConfig          = "dymint.da_config"
-	flagSettlementLayer   = "dymint.settlement_layer"
-	flagSettlementConfig  = "dymint.settlement_config"
-	flagBlockTime         = "dymint.block_time"
-	flagDABlockTime       = "dymint.da_block_time"
-	flagBatchSyncInterval = "dymint.batch_sync_interval"
-	flagDAStartHeight     = "dymint.da_start_height"
-	flagNamespaceID       = "dymint.namespace_id"
-	flagBlockBatchSize    = "dymint.block_batch_size"
+	flagAggregator          = "dymint.aggregator"
+	flagReadOnly            = "dymint.read_only"
+	flagDALayer             = "dymint.da_layer"
+	flagDAConfig            = "dymint.da_config"
+	flagSettlementLayer     = "dymint.settlement_layer"
+	flagSettlementConfig    = "dymint.settlement_config"
+	flagBlockTime           = "dymint.block_time"
+	flagDABlockTime         = "dymint.da_block_time"
+	flagBatchSyncInterval   = "dymint.batch_sync_interval"
+	flagDAStartHeight       = "dymint.da_start_height"
+	flagNamespaceID         = "dymint.namespace_id"
+	flagBlockBatchSize      = "dymint.block_batch_size"
+	flagBlockBatchMaxSize   = "dymint.block_batch_max_size_bytes"
+	flagBatchSubmitMaxTime  = "dymint.batch_submit_max_time"
+	flagMaxBatchesInFlight  = "dymint.max_batches_in_flight"
+	flagSyncPrefetchWindow  = "dymint.sync_prefetch_window"
+	flagSyncPrefetchMaxSize = "dymint.sync_prefetch_max_size_bytes"
+	flagFraudProofs         = "dymint.fraud_proofs"
+	flagTrustDAInclusion    = "dymint.trust_da_inclusion"
 )
 
 var (
@@ -30,12 +38,14 @@ var (
 // NodeConfig stores Dymint node configuration.
 type NodeConfig struct {
 	// parameters below are translated from existing config
-	RootDir string
-	DBPath  string
-	P2P     P2PConfig
-	RPC     RPCConfig
+	RootDir         string
+	DBPath          string
+	P2P             P2PConfig
+	RPC             RPCConfig
+	Instrumentation InstrumentationConfig
 	// parameters below are dymint specific and read from config
 	Aggregator         bool `mapstructure:"aggregator"`
+	ReadOnly           bool `mapstructure:"read_only"`
 	BlockManagerConfig `mapstructure:",squash"`
 	DALayer            string `mapstructure:"da_layer"`
 	DAConfig           string `mapstructure:"da_config"`
@@ -56,6 +66,26 @@ type BlockManagerConfig struct {
 	NamespaceID   [8]byte `mapstructure:"namespace_id"`
 	// The size of the batch in blocks. Every batch we'll write to the DA and the settlement layer.
 	BlockBatchSize uint64 `mapstructure:"block_batch_size"`
+	// The max size of the encoded batch in bytes. A batch is submitted once it reaches this size even if
+	// BlockBatchSize wasn't reached yet. Zero or values above types.MaxBatchSizeBytes mean types.MaxBatchSizeBytes.
+	BlockBatchMaxSizeBytes uint64 `mapstructure:"block_batch_max_size_bytes"`
+	// The max time to wait between batch submissions. A batch is submitted once this time passed since
+	// the last submission even if it's not full. Zero means no limit.
+	BatchSubmitMaxTime time.Duration `mapstructure:"batch_submit_max_time"`
+	// The max number of batches which were created but not yet accepted by the settlement layer.
+	// Batches are posted to the DA layer ahead of time while the previous ones wait for the settlement layer.
+	MaxBatchesInFlight uint64 `mapstructure:"max_batches_in_flight"`
+	// The max number of settlement batches fetched ahead of the applied height while syncing.
+	SyncPrefetchWindow uint64 `mapstructure:"sync_prefetch_window"`
+	// The max size in bytes of the fetched batches waiting to be applied while syncing. Zero means no limit.
+	SyncPrefetchMaxBytes uint64 `mapstructure:"sync_prefetch_max_size_bytes"`
+	// FraudProofs enables the generation of intermediate state roots while executing blocks.
+	// It requires the ABCI app to support the intermediate state root query.
+	FraudProofs bool `mapstructure:"fraud_proofs"`
+	// TrustDAInclusion allows applying the batches retrieved by DA clients which can't prove their inclusion in
+	// the DA layer, e.g. when the node is trusted to verify the inclusion itself. By default such batches are
+	// refused.
+	TrustDAInclusion bool `mapstructure:"trust_da_inclusion"`
 }
 
 // GetViperConfig reads configuration parameters from Viper instance.
@@ -63,6 +93,7 @@ type BlockManagerConfig struct {
 // This method is called in cosmos-sdk.
 func (nc *NodeConfig) GetViperConfig(v *viper.Viper) error {
 	nc.Aggregator = v.GetBool(flagAggregator)
+	nc.ReadOnly = v.GetBool(flagReadOnly)
 	nc.DALayer = v.GetString(flagDALayer)
 	nc.DAConfig = v.GetString(flagDAConfig)
 	nc.SettlementLayer = v.GetString(flagSettlementLayer)
@@ -72,6 +103,13 @@ func (nc *NodeConfig) GetViperConfig(v *viper.Viper) error {
 	nc.BatchSyncInterval = v.GetDuration(flagBatchSyncInterval)
 	nc.BlockTime = v.GetDuration(flagBlockTime)
 	nc.BlockBatchSize = v.GetUint64(flagBlockBatchSize)
+	nc.BlockBatchMaxSizeBytes = v.GetUint64(flagBlockBatchMaxSize)
+	nc.BatchSubmitMaxTime = v.GetDuration(flagBatchSubmitMaxTime)
+	nc.MaxBatchesInFlight = v.GetUint64(flagMaxBatchesInFlight)
+	nc.SyncPrefetchWindow = v.GetUint64(flagSyncPrefetchWindow)
+	nc.SyncPrefetchMaxBytes = v.GetUint64(flagSyncPrefetchMaxSize)
+	nc.FraudProofs = v.GetBool(flagFraudProofs)
+	nc.TrustDAInclusion = v.GetBool(flagTrustDAInclusion)
 	nsID := v.GetString(flagNamespaceID)
 	bytes, err := hex.DecodeString(nsID)
 	if err != nil {
@@ -88,9 +126,10 @@ func AddFlags(cmd *cobra.Command) {
 	def := DefaultNodeConfig
 
 	cmd.Flags().Bool(flagAggregator, false, "run node in aggregator mode")
-	cmd.Flags().String(flagDALayer, def.DALayer, "Data Availability Layer Client name (mock or grpc")
+	cmd.Flags().Bool(flagReadOnly, def.ReadOnly, "run as a full node in case the signing key isn't the proposer key (for aggregator mode)")
+	cmd.Flags().String(flagDALayer, def.DALayer, "Data Availability Layer Client name (mock, faulty-mock, grpc, celestia or local)")
 	cmd.Flags().String(flagDAConfig, def.DAConfig, "Data Availability Layer Client config")
-	cmd.Flags().String(flagSettlementLayer, def.SettlementLayer, "Settlement Layer Client name (currently only mock)")
+	cmd.Flags().String(flagSettlementLayer, def.SettlementLayer, "Settlement Layer Client name (mock, faulty-mock, dymension or evm)")
 	cmd.Flags().String(flagSettlementConfig, def.SettlementConfig, "Settlement Layer Client config")
 	cmd.Flags().Duration(flagBlockTime, def.BlockTime, "block time (for aggregator mode)")
 	cmd.Flags().Duration(flagDABlockTime, def.DABlockTime, "DA chain block time (for syncing)")
@@ -98,4 +137,11 @@ func AddFlags(cmd *cobra.Command) {
 	cmd.Flags().Uint64(flagDAStartHeight, def.DAStartHeight, "starting DA block height (for syncing)")
 	cmd.Flags().BytesHex(flagNamespaceID, def.NamespaceID[:], "namespace identifies (8 bytes in hex)")
 	cmd.Flags().Uint64(flagBlockBatchSize, def.BlockBatchSize, "block batch size")
+	cmd.Flags().Uint64(flagBlockBatchMaxSize, def.BlockBatchMaxSizeBytes, "max size of a batch in bytes (0 for the max decodable size)")
+	cmd.Flags().Duration(flagBatchSubmitMaxTime, def.BatchSubmitMaxTime, "max time between batch submissions (0 for no limit)")
+	cmd.Flags().Uint64(flagMaxBatchesInFlight, def.MaxBatchesInFlight, "max number of batches submitted but not yet accepted by the settlement layer")
+	cmd.Flags().Uint64(flagSyncPrefetchWindow, def.SyncPrefetchWindow, "max number of settlement batches fetched ahead while syncing")
+	cmd.Flags().Uint64(flagSyncPrefetchMaxSize, def.SyncPrefetchMaxBytes, "max size in bytes of the fetched batches waiting to be applied while syncing (0 for no limit)")
+	cmd.Flags().Bool(flagFraudProofs, def.FraudProofs, "generate intermediate state roots for fraud proofs (requires app support)")
+	cmd.Flags().Bool(flagTrustDAInclusion, def.TrustDAInclusion, "apply batches from DA clients which can't prove their inclusion (grpc or celestia)")
 }
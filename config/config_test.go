@@ -20,19 +20,35 @@ func TestViperAndCobra(t *testing.T) {
 	assert.NoError(v.BindPFlags(cmd.Flags()))
 
 	assert.NoError(cmd.Flags().Set(flagAggregator, "true"))
+	assert.NoError(cmd.Flags().Set(flagReadOnly, "true"))
 	assert.NoError(cmd.Flags().Set(flagDALayer, "foobar"))
 	assert.NoError(cmd.Flags().Set(flagDAConfig, `{"json":true}`))
 	assert.NoError(cmd.Flags().Set(flagBlockTime, "1234s"))
 	assert.NoError(cmd.Flags().Set(flagNamespaceID, "0102030405060708"))
 	assert.NoError(cmd.Flags().Set(flagBlockBatchSize, "10"))
+	assert.NoError(cmd.Flags().Set(flagBlockBatchMaxSize, "1000"))
+	assert.NoError(cmd.Flags().Set(flagBatchSubmitMaxTime, "10m"))
+	assert.NoError(cmd.Flags().Set(flagMaxBatchesInFlight, "3"))
+	assert.NoError(cmd.Flags().Set(flagSyncPrefetchWindow, "4"))
+	assert.NoError(cmd.Flags().Set(flagSyncPrefetchMaxSize, "2048"))
+	assert.NoError(cmd.Flags().Set(flagFraudProofs, "true"))
+	assert.NoError(cmd.Flags().Set(flagTrustDAInclusion, "true"))
 
 	nc := DefaultNodeConfig
 	assert.NoError(nc.GetViperConfig(v))
 
 	assert.Equal(true, nc.Aggregator)
+	assert.Equal(true, nc.ReadOnly)
 	assert.Equal("foobar", nc.DALayer)
 	assert.Equal(`{"json":true}`, nc.DAConfig)
 	assert.Equal(1234*time.Second, nc.BlockTime)
 	assert.Equal([8]byte{1, 2, 3, 4, 5, 6, 7, 8}, nc.NamespaceID)
 	assert.Equal(uint64(10), nc.BlockBatchSize)
+	assert.Equal(uint64(1000), nc.BlockBatchMaxSizeBytes)
+	assert.Equal(10*time.Minute, nc.BatchSubmitMaxTime)
+	assert.Equal(uint64(3), nc.MaxBatchesInFlight)
+	assert.Equal(uint64(4), nc.SyncPrefetchWindow)
+	assert.Equal(uint64(2048), nc.SyncPrefetchMaxBytes)
+	assert.Equal(true, nc.FraudProofs)
+	assert.Equal(true, nc.TrustDAInclusion)
 }
@@ -19,6 +19,15 @@ var DefaultNodeConfig = NodeConfig{
 		NamespaceID:       [8]byte{},
 		BatchSyncInterval: time.Second * 30,
 		BlockBatchSize:    500,
+		// Keep a safe margin below the max blob size of the DA layer
+		BlockBatchMaxSizeBytes: 1500000,
+		BatchSubmitMaxTime:     time.Hour,
+		MaxBatchesInFlight:     1,
+		SyncPrefetchWindow:     8,
+		SyncPrefetchMaxBytes:   64 * 1024 * 1024,
+	},
+	Instrumentation: InstrumentationConfig{
+		Namespace: "dymint",
 	},
 	DALayer:         "mock",
 	SettlementLayer: "mock",
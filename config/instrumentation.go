@@ -0,0 +1,9 @@
+package config
+
+// InstrumentationConfig stores the configuration of the metrics exported by the node.
+type InstrumentationConfig struct {
+	// Prometheus enables the Prometheus metrics, served under /metrics by the RPC server.
+	Prometheus bool
+	// Namespace is the namespace of the metrics.
+	Namespace string
+}
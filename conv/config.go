@@ -27,5 +27,9 @@ func GetNodeConfig(nodeConf *config.NodeConfig, tmConf *tmcfg.Config) {
 			nodeConf.RPC.TLSCertFile = tmConf.RPC.TLSCertFile
 			nodeConf.RPC.TLSKeyFile = tmConf.RPC.TLSKeyFile
 		}
+		if tmConf.Instrumentation != nil {
+			nodeConf.Instrumentation.Prometheus = tmConf.Instrumentation.Prometheus
+			nodeConf.Instrumentation.Namespace = tmConf.Instrumentation.Namespace
+		}
 	}
 }
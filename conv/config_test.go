@@ -22,6 +22,7 @@ func TestGetNodeConfig(t *testing.T) {
 		{"ListenAddress", &tmcfg.Config{P2P: &tmcfg.P2PConfig{ListenAddress: "127.0.0.1:7676"}}, config.NodeConfig{P2P: config.P2PConfig{ListenAddress: "127.0.0.1:7676"}}},
 		{"RootDir", &tmcfg.Config{BaseConfig: tmcfg.BaseConfig{RootDir: "~/root"}}, config.NodeConfig{RootDir: "~/root"}},
 		{"DBPath", &tmcfg.Config{BaseConfig: tmcfg.BaseConfig{DBPath: "./database"}}, config.NodeConfig{DBPath: "./database"}},
+		{"Instrumentation", &tmcfg.Config{Instrumentation: &tmcfg.InstrumentationConfig{Prometheus: true, Namespace: "dymint"}}, config.NodeConfig{Instrumentation: config.InstrumentationConfig{Prometheus: true, Namespace: "dymint"}}},
 	}
 
 	for _, c := range cases {
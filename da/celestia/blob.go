@@ -0,0 +1,177 @@
+package celestia
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Methods of the celestia-node JSON-RPC API used by the client.
+const (
+	MethodSubmitPayForBlob = "state.SubmitPayForBlob"
+	MethodGetAll           = "blob.GetAll"
+	MethodNetworkHead      = "header.NetworkHead"
+)
+
+// Error messages of celestia-node the client reacts to.
+const (
+	// BlobNotFoundMessage is returned by celestia-node when there are no blobs at the requested height.
+	BlobNotFoundMessage = "blob: not found"
+	// MempoolTimeoutMessage is returned by celestia-node when a transaction wasn't included before its timeout.
+	MempoolTimeoutMessage = "timed out waiting for tx to be included in a block"
+)
+
+// Blob is a blob as exchanged with the celestia-node blob API.
+type Blob struct {
+	Namespace    []byte `json:"namespace"`
+	Data         []byte `json:"data"`
+	ShareVersion uint32 `json:"share_version"`
+	Commitment   []byte `json:"commitment,omitempty"`
+}
+
+// TxResponse is the part of the response to a pay for blobs transaction used by the client.
+type TxResponse struct {
+	Height    int64  `json:"height"`
+	TxHash    string `json:"txhash"`
+	Codespace string `json:"codespace"`
+	Code      uint32 `json:"code"`
+	RawLog    string `json:"raw_log"`
+}
+
+// ExtendedHeader is the part of a celestia-node header used by the client.
+type ExtendedHeader struct {
+	Header RawHeader `json:"header"`
+}
+
+// RawHeader is the part of a Celestia block header used by the client.
+type RawHeader struct {
+	// Height is encoded either as a number or as a string, depending on the API.
+	Height json.Number `json:"height"`
+}
+
+// RPCRequest is a JSON-RPC 2.0 request.
+type RPCRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      uint64            `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// RPCResponse is a JSON-RPC 2.0 response.
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC 2.0 error.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// isMempoolTimeout returns true if the error means the transaction timed out in the mempool, in which case it may
+// be resubmitted with a higher fee.
+func isMempoolTimeout(err error) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && strings.Contains(rpcErr.Message, MempoolTimeoutMessage)
+}
+
+// blobClient calls the blob API of celestia-node over JSON-RPC.
+type blobClient struct {
+	url       string
+	authToken string
+	http      *http.Client
+	nextID    uint64
+}
+
+func newBlobClient(url string, authToken string, timeout time.Duration) *blobClient {
+	return &blobClient{url: url, authToken: authToken, http: &http.Client{Timeout: timeout}}
+}
+
+// SubmitPayForBlob submits the blobs in a single transaction, paying the given fee in utia.
+func (b *blobClient) SubmitPayForBlob(ctx context.Context, fee uint64, gasLimit uint64, blobs []*Blob) (*TxResponse, error) {
+	var res TxResponse
+	// the fee is an sdk.Int, which is encoded as a string
+	err := b.call(ctx, MethodSubmitPayForBlob, &res, strconv.FormatUint(fee, 10), gasLimit, blobs)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetAll returns the blobs of the namespace included at the given height.
+func (b *blobClient) GetAll(ctx context.Context, height uint64, namespace Namespace) ([]*Blob, error) {
+	var res []*Blob
+	err := b.call(ctx, MethodGetAll, &res, height, [][]byte{namespace[:]})
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && strings.Contains(rpcErr.Message, BlobNotFoundMessage) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// NetworkHead returns the height of the latest block known to the node.
+func (b *blobClient) NetworkHead(ctx context.Context) (uint64, error) {
+	var res ExtendedHeader
+	if err := b.call(ctx, MethodNetworkHead, &res); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(res.Header.Height.String(), 10, 64)
+}
+
+func (b *blobClient) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	req := RPCRequest{JSONRPC: "2.0", ID: atomic.AddUint64(&b.nextID, 1), Method: method, Params: []json.RawMessage{}}
+	for _, param := range params {
+		p, err := json.Marshal(param)
+		if err != nil {
+			return err
+		}
+		req.Params = append(req.Params, p)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if b.authToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+b.authToken)
+	}
+	httpRes, err := b.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close() //nolint:errcheck
+
+	var res RPCResponse
+	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
+		return fmt.Errorf("%s: decode response (status %s): %w", method, httpRes.Status, err)
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	if len(res.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Result, result)
+}
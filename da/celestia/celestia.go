@@ -1,31 +1,37 @@
 package celestia
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
+	"github.com/libp2p/go-libp2p-core/crypto"
 
-	"github.com/celestiaorg/go-cnc"
 	"github.com/dymensionxyz/dymint/da"
 	"github.com/dymensionxyz/dymint/log"
 	"github.com/dymensionxyz/dymint/store"
 	"github.com/dymensionxyz/dymint/types"
-	pb "github.com/dymensionxyz/dymint/types/pb/dymint"
 )
 
 // DataAvailabilityLayerClient use celestia-node public API.
 type DataAvailabilityLayerClient struct {
-	client *cnc.Client
+	endpoints []*endpoint
+	pool      *da.EndpointPool
+	cancel    context.CancelFunc
 
 	config Config
 	logger log.Logger
+
+	manifestSigner crypto.PrivKey
+	isSequencerKey func(pubKey []byte) bool
 }
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BatchRetriever = &DataAvailabilityLayerClient{}
+var _ da.ManifestSigner = &DataAvailabilityLayerClient{}
 
 // Config stores Celestia DALC configuration parameters.
 type Config struct {
@@ -33,30 +39,158 @@ type Config struct {
 	Timeout     time.Duration `json:"timeout"`
 	GasLimit    uint64        `json:"gas_limit"`
 	NamespaceID [8]byte       `json:"namespace_id"`
+	// Codec is the compression codec of submitted batches.
+	Codec types.BatchCodec `json:"codec"`
+	// MaxBlobSize is the max size of a single blob. Bigger batches are split across several blobs.
+	MaxBlobSize int `json:"max_blob_size"`
+	// MaxChunkSpan is the max number of DA blocks between the first chunk of a batch and its manifest. Manifests
+	// spanning more blocks are dropped without fetching their chunks.
+	MaxChunkSpan uint64 `json:"max_chunk_span"`
+	// SubmitTimeout bounds a single SubmitBatch call. Zero means no timeout.
+	SubmitTimeout time.Duration `json:"submit_timeout"`
+	// RetrieveTimeout bounds a single CheckBatchAvailability or RetrieveBatches call. Zero means no timeout.
+	RetrieveTimeout time.Duration `json:"retrieve_timeout"`
+	// API is the celestia-node API used by the client, APIPFD or APIBlob.
+	API string `json:"api"`
+	// Namespace is the namespace of the blobs submitted with the blob API. Defaults to the version zero namespace
+	// with NamespaceID as ID.
+	Namespace Namespace `json:"namespace"`
+	// AuthToken authorizes the blob API calls.
+	AuthToken string `json:"auth_token"`
+	// GasPrice is the initial gas price, in utia, of blob API submissions. The gas is estimated from the blob size.
+	// With the blob API, a non zero GasLimit bounds the estimated gas.
+	GasPrice float64 `json:"gas_price"`
+	// GasPriceMultiplier raises the gas price each time a submission times out in the mempool and is resubmitted.
+	GasPriceMultiplier float64 `json:"gas_price_multiplier"`
+	// MaxGasPrice bounds the gas price raised by resubmissions.
+	MaxGasPrice float64 `json:"max_gas_price"`
+	// MaxFee bounds the fee, in utia, of a single submission. Zero means no limit.
+	MaxFee uint64 `json:"max_fee"`
+	// Endpoints are the URLs of the celestia-nodes used by the client. Calls fail over to the next endpoint on
+	// errors. Defaults to BaseURL.
+	Endpoints []string `json:"endpoints"`
+	// EndpointSelection is the order in which the endpoints are tried, priority or round-robin.
+	EndpointSelection da.EndpointSelection `json:"endpoint_selection"`
+	// HealthCheckInterval is the interval between health checks of the endpoints, if there are several of them.
+	HealthCheckInterval time.Duration `json:"health_check_interval"`
 }
 
+const (
+	// APIPFD is the legacy celestia-node gateway API, using the submit_pfd and namespaced_data endpoints.
+	APIPFD = "pfd"
+	// APIBlob is the celestia-node JSON-RPC blob API.
+	APIBlob = "blob"
+)
+
+const (
+	// DefaultMaxBlobSize is the default max size of a single blob submitted to Celestia.
+	DefaultMaxBlobSize = 1 << 20
+	// DefaultMaxChunkSpan is the default max number of DA blocks between the first chunk of a batch and its manifest.
+	DefaultMaxChunkSpan = 1000
+	// DefaultGasPrice is the default initial gas price in utia.
+	DefaultGasPrice = 0.002
+	// DefaultGasPriceMultiplier is the default gas price raise on resubmission.
+	DefaultGasPriceMultiplier = 1.5
+	// DefaultMaxGasPrice is the default max gas price in utia.
+	DefaultMaxGasPrice = 0.2
+)
+
+// ErrFeeLimit is returned when a submission would exceed the configured fee or gas limits.
+var ErrFeeLimit = errors.New("fee limit exceeded")
+
 // Init initializes DataAvailabilityLayerClient instance.
 func (c *DataAvailabilityLayerClient) Init(config []byte, kvStore store.KVStore, logger log.Logger) error {
 	c.logger = logger
+	c.config.Codec = types.DefaultBatchCodec
 
 	if len(config) > 0 {
-		return json.Unmarshal(config, &c.config)
+		if err := json.Unmarshal(config, &c.config); err != nil {
+			return err
+		}
+	}
+	if c.config.MaxBlobSize == 0 {
+		c.config.MaxBlobSize = DefaultMaxBlobSize
+	}
+	if c.config.MaxChunkSpan == 0 {
+		c.config.MaxChunkSpan = DefaultMaxChunkSpan
+	}
+	if c.config.GasPrice == 0 {
+		c.config.GasPrice = DefaultGasPrice
+	}
+	if c.config.GasPriceMultiplier == 0 {
+		c.config.GasPriceMultiplier = DefaultGasPriceMultiplier
+	}
+	if c.config.MaxGasPrice == 0 {
+		c.config.MaxGasPrice = DefaultMaxGasPrice
+	}
+	if len(c.config.Endpoints) == 0 {
+		c.config.Endpoints = []string{c.config.BaseURL}
+	}
+	if c.config.HealthCheckInterval == 0 {
+		c.config.HealthCheckInterval = DefaultHealthCheckInterval
 	}
 
-	return nil
+	switch c.config.API {
+	case "", APIPFD:
+		c.config.API = APIPFD
+	case APIBlob:
+		if c.config.Namespace.IsZero() {
+			var err error
+			c.config.Namespace, err = NewNamespaceV0(c.config.NamespaceID[:])
+			if err != nil {
+				return err
+			}
+		}
+		if err := c.config.Namespace.Validate(); err != nil {
+			return err
+		}
+		if c.config.GasPriceMultiplier <= 1 {
+			return errors.New("gas price multiplier must be greater than 1")
+		}
+		if c.config.GasPrice > c.config.MaxGasPrice {
+			return errors.New("gas price must not be greater than max gas price")
+		}
+	default:
+		return fmt.Errorf("unknown celestia API: %s", c.config.API)
+	}
+
+	var err error
+	c.pool, err = da.NewEndpointPool(len(c.config.Endpoints), c.config.EndpointSelection)
+	return err
+}
+
+// SetManifestKeys sets the key signing the manifests of chunked batches and the check of the manifest signers. It
+// must be called before Start: until then, batches too big for a single blob can be neither submitted nor retrieved.
+func (c *DataAvailabilityLayerClient) SetManifestKeys(signer crypto.PrivKey, isSequencerKey func(pubKey []byte) bool) {
+	c.manifestSigner = signer
+	c.isSequencerKey = isSequencerKey
 }
 
 // Start prepares DataAvailabilityLayerClient to work.
 func (c *DataAvailabilityLayerClient) Start() error {
-	c.logger.Info("starting Celestia Data Availability Layer Client", "baseURL", c.config.BaseURL)
-	var err error
-	c.client, err = cnc.NewClient(c.config.BaseURL, cnc.WithTimeout(c.config.Timeout))
-	return err
+	c.logger.Info("starting Celestia Data Availability Layer Client", "endpoints", c.config.Endpoints, "api", c.config.API)
+	c.endpoints = make([]*endpoint, len(c.config.Endpoints))
+	for i, url := range c.config.Endpoints {
+		var err error
+		c.endpoints[i], err = newEndpoint(url, c.config)
+		if err != nil {
+			return err
+		}
+	}
+	if len(c.endpoints) > 1 {
+		var ctx context.Context
+		ctx, c.cancel = context.WithCancel(context.Background())
+		go c.pool.RunHealthCheck(ctx, c.config.HealthCheckInterval, c.checkHealth)
+	}
+	return nil
 }
 
 // Stop stops DataAvailabilityLayerClient.
 func (c *DataAvailabilityLayerClient) Stop() error {
 	c.logger.Info("stopping Celestia Data Availability Layer Client")
+	if c.cancel != nil {
+		c.cancel()
+	}
 	return nil
 }
 
@@ -66,8 +200,13 @@ func (c *DataAvailabilityLayerClient) GetClientType() da.Client {
 }
 
 // SubmitBatch submits a block to DA layer.
-func (c *DataAvailabilityLayerClient) SubmitBatch(batch *types.Batch) da.ResultSubmitBatch {
-	blob, err := batch.MarshalBinary()
+// Batches bigger than the max blob size are split into several chunk blobs, followed by a manifest blob which
+// references them. In this case the returned DA height is the height of the manifest.
+func (c *DataAvailabilityLayerClient) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	ctx, cancel := da.WithTimeout(ctx, c.config.SubmitTimeout)
+	defer cancel()
+
+	blob, err := types.EncodeBatch(batch, c.config.Codec)
 	if err != nil {
 		return da.ResultSubmitBatch{
 			BaseResult: da.BaseResult{
@@ -77,22 +216,28 @@ func (c *DataAvailabilityLayerClient) SubmitBatch(batch *types.Batch) da.ResultS
 		}
 	}
 
-	txResponse, err := c.client.SubmitPFD(context.TODO(), c.config.NamespaceID, blob, c.config.GasLimit)
-
-	if err != nil {
-		return da.ResultSubmitBatch{
-			BaseResult: da.BaseResult{
-				Code:    da.StatusError,
-				Message: err.Error(),
-			},
+	var stats submitStats
+	chunked := false
+	if len(blob) > c.config.MaxBlobSize {
+		c.logger.Debug("splitting batch into chunks", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "size", len(blob))
+		blob, err = c.submitChunks(ctx, blob, &stats)
+		if err != nil {
+			return da.ResultSubmitBatch{
+				BaseResult: da.BaseResult{
+					Code:    da.ErrorStatus(err),
+					Message: err.Error(),
+				},
+			}
 		}
+		chunked = true
 	}
 
-	if txResponse.Code != 0 {
+	txResponse, err := c.submitBlob(ctx, blob, &stats)
+	if err != nil {
 		return da.ResultSubmitBatch{
 			BaseResult: da.BaseResult{
-				Code:    da.StatusError,
-				Message: fmt.Sprintf("Codespace: '%s', Code: %d, Message: %s", txResponse.Codespace, txResponse.Code, txResponse.RawLog),
+				Code:    da.ErrorStatus(err),
+				Message: err.Error(),
 			},
 		}
 	}
@@ -103,16 +248,218 @@ func (c *DataAvailabilityLayerClient) SubmitBatch(batch *types.Batch) da.ResultS
 			Message:  "tx hash: " + txResponse.TxHash,
 			DAHeight: uint64(txResponse.Height),
 		},
+		Chunked: chunked,
+		Size:    stats.size,
+		Blobs:   stats.blobs,
+		Fee:     stats.fee,
+		Retries: stats.retries,
 	}
 }
 
-// CheckBatchAvailability queries DA layer to check data availability of block at given height.
-func (c *DataAvailabilityLayerClient) CheckBatchAvailability(dataLayerHeight uint64) da.ResultCheckBatch {
-	shares, err := c.client.NamespacedShares(context.TODO(), c.config.NamespaceID, dataLayerHeight)
+// submitStats accounts for the blobs submitted for a batch.
+type submitStats struct {
+	size    int
+	blobs   int
+	fee     uint64
+	retries int
+}
+
+// submitChunks splits the blob into chunks, submits them and returns the manifest blob referencing them.
+func (c *DataAvailabilityLayerClient) submitChunks(ctx context.Context, blob []byte, stats *submitStats) ([]byte, error) {
+	if c.manifestSigner == nil {
+		return nil, errors.New("no key to sign the manifest of the chunked batch")
+	}
+	chunks, err := da.SplitBlob(blob, c.config.MaxBlobSize)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &da.BlobManifest{Chunks: make([]da.BlobChunk, len(chunks))}
+	for i, chunk := range chunks {
+		txResponse, err := c.submitBlob(ctx, chunk, stats)
+		if err != nil {
+			return nil, fmt.Errorf("failed to submit chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		manifest.Chunks[i] = da.BlobChunk{DAHeight: uint64(txResponse.Height), Hash: da.ChunkHash(chunk)}
+	}
+	if err := manifest.Sign(c.manifestSigner); err != nil {
+		return nil, err
+	}
+	return manifest.MarshalBinary()
+}
+
+// submitBlob submits the blob, failing over to the next endpoint on errors. Before resubmitting to another endpoint,
+// the blocks included since the first attempt are searched for the blob, so that a submission which went through
+// despite the error isn't included twice.
+func (c *DataAvailabilityLayerClient) submitBlob(ctx context.Context, blob []byte, stats *submitStats) (*TxResponse, error) {
+	var txResponse *TxResponse
+	var err error
+	var fromHeight uint64
+	attempted := false
+	for _, i := range c.pool.Candidates() {
+		e := c.endpoints[i]
+		if len(c.endpoints) > 1 {
+			var head uint64
+			head, err = e.head(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, err
+				}
+				c.endpointFailed(i, err)
+				continue
+			}
+			if attempted {
+				txResponse, err = c.findBlob(ctx, e, blob, fromHeight, head)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil, err
+					}
+					c.endpointFailed(i, err)
+					continue
+				}
+				if txResponse != nil {
+					c.logger.Info("blob already included, skipping resubmission", "daHeight", txResponse.Height)
+					c.pool.MarkHealthy(i)
+					stats.size += len(blob)
+					stats.blobs++
+					return txResponse, nil
+				}
+			} else {
+				fromHeight = head
+			}
+		}
+
+		if attempted {
+			stats.retries++
+		}
+		attempted = true
+		txResponse, err = c.submitBlobTo(ctx, e, blob, stats)
+		if err == nil {
+			c.pool.MarkHealthy(i)
+			break
+		}
+		if errors.Is(err, ErrFeeLimit) || ctx.Err() != nil {
+			return nil, err
+		}
+		c.endpointFailed(i, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if txResponse.Code != 0 {
+		return nil, fmt.Errorf("Codespace: '%s', Code: %d, Message: %s", txResponse.Codespace, txResponse.Code, txResponse.RawLog)
+	}
+	stats.size += len(blob)
+	stats.blobs++
+	return txResponse, nil
+}
+
+// findBlob searches the blocks in (fromHeight, toHeight] for the blob. It returns nil if the blob isn't found.
+func (c *DataAvailabilityLayerClient) findBlob(ctx context.Context, e *endpoint, blob []byte, fromHeight, toHeight uint64) (*TxResponse, error) {
+	for h := fromHeight + 1; h <= toHeight; h++ {
+		data, err := c.namespacedDataFrom(ctx, e, h)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range data {
+			if bytes.Equal(msg, blob) {
+				return &TxResponse{Height: int64(h)}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// submitBlobTo submits the blob to a single endpoint.
+func (c *DataAvailabilityLayerClient) submitBlobTo(ctx context.Context, e *endpoint, blob []byte, stats *submitStats) (*TxResponse, error) {
+	if c.config.API == APIBlob {
+		return c.submitPayForBlob(ctx, e, blob, stats)
+	}
+	pfdResponse, err := e.client.SubmitPFD(ctx, c.config.NamespaceID, blob, c.config.GasLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &TxResponse{
+		Height:    pfdResponse.Height,
+		TxHash:    pfdResponse.TxHash,
+		Codespace: pfdResponse.Codespace,
+		Code:      pfdResponse.Code,
+		RawLog:    pfdResponse.RawLog,
+	}, nil
+}
+
+// submitPayForBlob submits the blob with the blob API, paying for the gas estimated from its size. Submissions
+// which time out in the mempool are retried with a raised gas price, up to the configured limits.
+// The fee of the included transaction is added to the stats.
+func (c *DataAvailabilityLayerClient) submitPayForBlob(ctx context.Context, e *endpoint, blob []byte, stats *submitStats) (*TxResponse, error) {
+	gas := EstimateGas(len(blob))
+	if c.config.GasLimit > 0 && gas > c.config.GasLimit {
+		return nil, fmt.Errorf("%w: estimated gas %d above gas limit %d", ErrFeeLimit, gas, c.config.GasLimit)
+	}
+	blobs := []*Blob{{Namespace: c.config.Namespace[:], Data: blob}}
+
+	gasPrice := c.config.GasPrice
+	for {
+		fee := Fee(gas, gasPrice)
+		if c.config.MaxFee > 0 && fee > c.config.MaxFee {
+			return nil, fmt.Errorf("%w: fee %dutia above max fee %dutia", ErrFeeLimit, fee, c.config.MaxFee)
+		}
+		txResponse, err := e.blobClient.SubmitPayForBlob(ctx, fee, gas, blobs)
+		if err == nil {
+			stats.fee += fee
+			return txResponse, nil
+		}
+		if !isMempoolTimeout(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		stats.retries++
+
+		gasPrice *= c.config.GasPriceMultiplier
+		if gasPrice > c.config.MaxGasPrice {
+			return nil, fmt.Errorf("%w: gas price %g above max gas price %g: %s", ErrFeeLimit, gasPrice, c.config.MaxGasPrice, err)
+		}
+		c.logger.Info("blob submission timed out in mempool, resubmitting with higher gas price", "gas", gas, "gasPrice", gasPrice)
+	}
+}
+
+// namespacedData returns the blobs of the namespace included at the given height.
+func (c *DataAvailabilityLayerClient) namespacedData(ctx context.Context, dataLayerHeight uint64) ([][]byte, error) {
+	var data [][]byte
+	err := c.call(ctx, func(e *endpoint) error {
+		var err error
+		data, err = c.namespacedDataFrom(ctx, e, dataLayerHeight)
+		return err
+	})
+	return data, err
+}
+
+// namespacedDataFrom returns the blobs of the namespace included at the given height, as known to a single endpoint.
+func (c *DataAvailabilityLayerClient) namespacedDataFrom(ctx context.Context, e *endpoint, dataLayerHeight uint64) ([][]byte, error) {
+	if c.config.API != APIBlob {
+		return e.client.NamespacedData(ctx, c.config.NamespaceID, dataLayerHeight)
+	}
+	blobs, err := e.blobClient.GetAll(ctx, dataLayerHeight, c.config.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	data := make([][]byte, len(blobs))
+	for i, blob := range blobs {
+		data[i] = blob.Data
+	}
+	return data, nil
+}
+
+// CheckBatchAvailability queries DA layer to check data availability of block at given height. The data is
+// available only if the namespace holds a batch which can be retrieved and decoded at that height, chunked batches
+// included.
+func (c *DataAvailabilityLayerClient) CheckBatchAvailability(ctx context.Context, dataLayerHeight uint64) da.ResultCheckBatch {
+	ctx, cancel := da.WithTimeout(ctx, c.config.RetrieveTimeout)
+	defer cancel()
+
+	batches, err := c.retrieveBatches(ctx, dataLayerHeight)
 	if err != nil {
 		return da.ResultCheckBatch{
 			BaseResult: da.BaseResult{
-				Code:    da.StatusError,
+				Code:    da.ErrorStatus(err),
 				Message: err.Error(),
 			},
 		}
@@ -123,47 +470,100 @@ func (c *DataAvailabilityLayerClient) CheckBatchAvailability(dataLayerHeight uin
 			Code:     da.StatusSuccess,
 			DAHeight: dataLayerHeight,
 		},
-		DataAvailable: len(shares) > 0,
+		DataAvailable: len(batches) > 0,
 	}
 }
 
 // RetrieveBatches gets a batch of blocks from DA layer.
-func (c *DataAvailabilityLayerClient) RetrieveBatches(dataLayerHeight uint64) da.ResultRetrieveBatch {
-	data, err := c.client.NamespacedData(context.TODO(), c.config.NamespaceID, dataLayerHeight)
+// Chunk blobs are skipped, chunked batches are put back together from the manifest blob.
+func (c *DataAvailabilityLayerClient) RetrieveBatches(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBatch {
+	ctx, cancel := da.WithTimeout(ctx, c.config.RetrieveTimeout)
+	defer cancel()
+
+	batches, err := c.retrieveBatches(ctx, dataLayerHeight)
 	if err != nil {
 		return da.ResultRetrieveBatch{
 			BaseResult: da.BaseResult{
-				Code:    da.StatusError,
+				Code:    da.ErrorStatus(err),
 				Message: err.Error(),
 			},
 		}
 	}
 
-	batches := make([]*types.Batch, len(data))
+	return da.ResultRetrieveBatch{
+		BaseResult: da.BaseResult{
+			Code:     da.StatusSuccess,
+			DAHeight: dataLayerHeight,
+		},
+		Batches: batches,
+	}
+}
+
+// retrieveBatches returns the batches decoded from the blobs of the namespace at the given height. Blobs which
+// can't be decoded are logged and skipped.
+func (c *DataAvailabilityLayerClient) retrieveBatches(ctx context.Context, dataLayerHeight uint64) ([]*types.Batch, error) {
+	data, err := c.namespacedData(ctx, dataLayerHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []*types.Batch
 	for i, msg := range data {
-		var batch pb.Batch
-		err = proto.Unmarshal(msg, &batch)
-		if err != nil {
-			c.logger.Error("failed to unmarshal batch", "daHeight", dataLayerHeight, "position", i, "error", err)
+		if da.IsChunkBlob(msg) {
 			continue
 		}
-		batches[i] = new(types.Batch)
-		err := batches[i].FromProto(&batch)
-		if err != nil {
-			return da.ResultRetrieveBatch{
-				BaseResult: da.BaseResult{
-					Code:    da.StatusError,
-					Message: err.Error(),
-				},
+		if da.IsManifestBlob(msg) {
+			msg, err = c.retrieveChunked(ctx, dataLayerHeight, msg)
+			if err != nil && ctx.Err() != nil {
+				return nil, err
+			}
+			if err != nil {
+				c.logger.Error("failed to retrieve chunked batch", "daHeight", dataLayerHeight, "position", i, "error", err)
+				continue
 			}
 		}
+		batch, err := types.DecodeBatch(msg)
+		if err != nil {
+			c.logger.Error("failed to decode batch", "daHeight", dataLayerHeight, "position", i, "error", err)
+			continue
+		}
+		batches = append(batches, batch)
 	}
+	return batches, nil
+}
 
-	return da.ResultRetrieveBatch{
-		BaseResult: da.BaseResult{
-			Code:     da.StatusSuccess,
-			DAHeight: dataLayerHeight,
-		},
-		Batches: batches,
+// retrieveChunked fetches the chunks referenced by the manifest blob included at the given height and puts the batch
+// blob back together. The manifest is verified first, so a junk manifest can't trigger any fetch.
+func (c *DataAvailabilityLayerClient) retrieveChunked(ctx context.Context, dataLayerHeight uint64, manifestBlob []byte) ([]byte, error) {
+	var manifest da.BlobManifest
+	if err := manifest.UnmarshalBinary(manifestBlob); err != nil {
+		return nil, err
+	}
+	if err := manifest.Verify(dataLayerHeight, c.config.MaxBlobSize, c.config.MaxChunkSpan, c.isSequencerKey); err != nil {
+		return nil, err
+	}
+
+	dataAtHeight := make(map[uint64][][]byte)
+	chunks := make([][]byte, len(manifest.Chunks))
+	for i, ref := range manifest.Chunks {
+		data, ok := dataAtHeight[ref.DAHeight]
+		if !ok {
+			var err error
+			data, err = c.namespacedData(ctx, ref.DAHeight)
+			if err != nil {
+				return nil, err
+			}
+			dataAtHeight[ref.DAHeight] = data
+		}
+		for _, msg := range data {
+			if da.IsChunkBlob(msg) && bytes.Equal(da.ChunkHash(msg), ref.Hash) {
+				chunks[i] = msg
+				break
+			}
+		}
+		if chunks[i] == nil {
+			return nil, fmt.Errorf("%w: chunk %d not found at DA height %d", da.ErrChunkMismatch, i, ref.DAHeight)
+		}
 	}
+	return manifest.Assemble(chunks)
 }
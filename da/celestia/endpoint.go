@@ -0,0 +1,99 @@
+package celestia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/celestiaorg/go-cnc"
+)
+
+// headEndpoint is the gateway endpoint returning the latest header known to the node.
+const headEndpoint = "/head"
+
+// endpoint is a single celestia-node the client talks to.
+type endpoint struct {
+	url        string
+	api        string
+	client     *cnc.Client
+	blobClient *blobClient
+	http       *http.Client
+}
+
+func newEndpoint(url string, config Config) (*endpoint, error) {
+	e := &endpoint{url: url, api: config.API}
+	if config.API == APIBlob {
+		e.blobClient = newBlobClient(url, config.AuthToken, config.Timeout)
+		return e, nil
+	}
+	var err error
+	e.client, err = cnc.NewClient(url, cnc.WithTimeout(config.Timeout))
+	if err != nil {
+		return nil, err
+	}
+	e.http = &http.Client{Timeout: config.Timeout}
+	return e, nil
+}
+
+// head returns the height of the latest block known to the node.
+func (e *endpoint) head(ctx context.Context) (uint64, error) {
+	if e.api == APIBlob {
+		return e.blobClient.NetworkHead(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(e.url, "/")+headEndpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := e.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("get head: %s", res.Status)
+	}
+	var header ExtendedHeader
+	if err := json.NewDecoder(res.Body).Decode(&header); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(header.Header.Height.String(), 10, 64)
+}
+
+// DefaultHealthCheckInterval is the default interval between health checks of the endpoints.
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// call runs fn against the endpoints in the order picked by the pool, failing over to the next endpoint on errors.
+// The error of the last endpoint is returned if all of them fail.
+func (c *DataAvailabilityLayerClient) call(ctx context.Context, fn func(e *endpoint) error) error {
+	var err error
+	for _, i := range c.pool.Candidates() {
+		err = fn(c.endpoints[i])
+		if err == nil {
+			c.pool.MarkHealthy(i)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		c.endpointFailed(i, err)
+	}
+	return err
+}
+
+func (c *DataAvailabilityLayerClient) endpointFailed(i int, err error) {
+	if len(c.endpoints) > 1 {
+		c.logger.Info("celestia endpoint failed, failing over", "endpoint", c.endpoints[i].url, "error", err)
+	}
+	c.pool.MarkFailed(i)
+}
+
+// checkHealth is the health check of the endpoints.
+func (c *DataAvailabilityLayerClient) checkHealth(ctx context.Context, i int) error {
+	_, err := c.endpoints[i].head(ctx)
+	return err
+}
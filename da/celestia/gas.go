@@ -0,0 +1,44 @@
+package celestia
+
+import "math"
+
+// The constants below follow the share layout and the gas schedule of celestia-app.
+const (
+	shareSize        = 512
+	shareInfoBytes   = 1
+	sequenceLenBytes = 4
+
+	firstSparseShareContentSize        = shareSize - NamespaceSize - shareInfoBytes - sequenceLenBytes
+	continuationSparseShareContentSize = shareSize - NamespaceSize - shareInfoBytes
+
+	gasPerBlobByte    = 8
+	txSizeCostPerByte = 10
+	bytesPerBlobInfo  = 70
+	pfbGasFixedCost   = 75000
+)
+
+// SharesNeeded returns the number of shares a blob of the given size takes.
+func SharesNeeded(blobSize int) int {
+	if blobSize <= 0 {
+		return 0
+	}
+	if blobSize <= firstSparseShareContentSize {
+		return 1
+	}
+	rest := blobSize - firstSparseShareContentSize
+	return 1 + (rest+continuationSparseShareContentSize-1)/continuationSparseShareContentSize
+}
+
+// EstimateGas returns the gas needed by a pay for blobs transaction including blobs of the given sizes.
+func EstimateGas(blobSizes ...int) uint64 {
+	var shares uint64
+	for _, size := range blobSizes {
+		shares += uint64(SharesNeeded(size))
+	}
+	return shares*shareSize*gasPerBlobByte + txSizeCostPerByte*bytesPerBlobInfo*uint64(len(blobSizes)) + pfbGasFixedCost
+}
+
+// Fee returns the fee, in utia, paid for the given gas at the given gas price.
+func Fee(gas uint64, gasPrice float64) uint64 {
+	return uint64(math.Ceil(float64(gas) * gasPrice))
+}
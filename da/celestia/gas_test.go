@@ -0,0 +1,52 @@
+package celestia
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharesNeeded(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0, SharesNeeded(0))
+	assert.Equal(1, SharesNeeded(1))
+	assert.Equal(1, SharesNeeded(firstSparseShareContentSize))
+	assert.Equal(2, SharesNeeded(firstSparseShareContentSize+1))
+	assert.Equal(2, SharesNeeded(firstSparseShareContentSize+continuationSparseShareContentSize))
+	assert.Equal(3, SharesNeeded(firstSparseShareContentSize+continuationSparseShareContentSize+1))
+}
+
+func TestEstimateGas(t *testing.T) {
+	assert := assert.New(t)
+
+	// a single share blob
+	assert.Equal(uint64(shareSize*gasPerBlobByte+txSizeCostPerByte*bytesPerBlobInfo+pfbGasFixedCost), EstimateGas(100))
+	assert.Less(EstimateGas(100), EstimateGas(1000))
+	assert.Equal(EstimateGas(100)+shareSize*gasPerBlobByte+txSizeCostPerByte*bytesPerBlobInfo, EstimateGas(100, 100))
+
+	assert.Equal(uint64(3), Fee(1000, 0.0025))
+	assert.Equal(uint64(0), Fee(1000, 0))
+}
+
+func TestNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	ns, err := NewNamespaceV0([]byte{1, 2, 3})
+	assert.NoError(err)
+	assert.NoError(ns.Validate())
+	assert.Equal(NamespaceVersionZero, ns.Version())
+	assert.Equal([]byte{1, 2, 3}, ns.ID()[NamespaceIDSize-3:])
+
+	text, err := ns.MarshalText()
+	assert.NoError(err)
+	var decoded Namespace
+	assert.NoError(decoded.UnmarshalText(text))
+	assert.Equal(ns, decoded)
+
+	_, err = NewNamespaceV0(make([]byte, NamespaceVersionZeroIDSize+1))
+	assert.ErrorIs(err, ErrInvalidNamespace)
+	ns[1] = 1
+	assert.ErrorIs(ns.Validate(), ErrInvalidNamespace)
+	assert.ErrorIs(decoded.UnmarshalText([]byte("0102")), ErrInvalidNamespace)
+}
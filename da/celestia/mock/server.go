@@ -1,52 +1,97 @@
 package mock
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	mux2 "github.com/gorilla/mux"
 
 	"github.com/celestiaorg/go-cnc"
-	"github.com/dymensionxyz/dymint/da"
-	mockda "github.com/dymensionxyz/dymint/da/mock"
+	"github.com/dymensionxyz/dymint/da/celestia"
 	"github.com/dymensionxyz/dymint/log"
-	"github.com/dymensionxyz/dymint/store"
-	"github.com/dymensionxyz/dymint/types"
 )
 
-// Server mocks celestia-node HTTP API.
+// Server mocks celestia-node HTTP API, both the legacy gateway endpoints and the JSON-RPC blob API.
+// It stores submitted blobs as is, so any blob (batch, chunk or manifest) can be retrieved.
 type Server struct {
-	mock      *mockda.DataAvailabilityLayerClient
-	blockTime time.Duration
-	server    *http.Server
-	logger    log.Logger
+	blockTime   time.Duration
+	minGasPrice float64
+	server      *http.Server
+	logger      log.Logger
+
+	mu       sync.Mutex
+	daHeight uint64
+	blobs    map[uint64][]storedBlob
+	fees     []uint64
+	stop     chan struct{}
+}
+
+// storedBlob is a blob together with the namespace it was submitted to.
+type storedBlob struct {
+	namespace celestia.Namespace
+	data      []byte
+}
+
+// Option configures the Server.
+type Option func(*Server)
+
+// WithMinGasPrice sets the min gas price, in utia, of blob API submissions. Submissions paying less time out in
+// the mempool.
+func WithMinGasPrice(minGasPrice float64) Option {
+	return func(s *Server) {
+		s.minGasPrice = minGasPrice
+	}
 }
 
 // NewServer creates new instance of Server.
-func NewServer(blockTime time.Duration, logger log.Logger) *Server {
-	return &Server{
-		mock:      new(mockda.DataAvailabilityLayerClient),
+func NewServer(blockTime time.Duration, logger log.Logger, options ...Option) *Server {
+	s := &Server{
 		blockTime: blockTime,
 		logger:    logger,
+		daHeight:  1,
+		blobs:     make(map[uint64][]storedBlob),
+		stop:      make(chan struct{}),
 	}
+	for _, apply := range options {
+		apply(s)
+	}
+	return s
+}
+
+// Fees returns the fees paid by the accepted blob API submissions.
+func (s *Server) Fees() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint64(nil), s.fees...)
 }
 
 // Start starts HTTP server with given listener.
 func (s *Server) Start(listener net.Listener) error {
-	err := s.mock.Init([]byte(s.blockTime.String()), store.NewDefaultInMemoryKVStore(), s.logger)
-	if err != nil {
-		return err
-	}
-	err = s.mock.Start()
-	if err != nil {
-		return err
-	}
+	go func() {
+		ticker := time.NewTicker(s.blockTime)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.mu.Lock()
+				s.daHeight += rand.Uint64()%10 + 1 //#nosec
+				s.mu.Unlock()
+			}
+		}
+	}()
 	go func() {
 		s.server = new(http.Server)
 		s.server.Handler = s.getHandler()
@@ -58,6 +103,7 @@ func (s *Server) Start(listener net.Listener) error {
 
 // Stop shuts down the Server.
 func (s *Server) Stop() {
+	close(s.stop)
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 	_ = s.server.Shutdown(ctx)
@@ -65,9 +111,11 @@ func (s *Server) Stop() {
 
 func (s *Server) getHandler() http.Handler {
 	mux := mux2.NewRouter()
+	mux.HandleFunc("/", s.rpc).Methods(http.MethodPost)
 	mux.HandleFunc("/submit_pfd", s.submit).Methods(http.MethodPost)
 	mux.HandleFunc("/namespaced_shares/{namespace}/height/{height}", s.shares).Methods(http.MethodGet)
 	mux.HandleFunc("/namespaced_data/{namespace}/height/{height}", s.data).Methods(http.MethodGet)
+	mux.HandleFunc("/head", s.head).Methods(http.MethodGet)
 
 	return mux
 }
@@ -80,28 +128,22 @@ func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	batch := types.Batch{}
-	batchData, err := hex.DecodeString(req.Data)
+	blob, err := hex.DecodeString(req.Data)
 	if err != nil {
 		s.writeError(w, err)
 		return
 	}
-	err = batch.UnmarshalBinary(batchData)
+	namespace, err := legacyNamespace(req.NamespaceID)
 	if err != nil {
 		s.writeError(w, err)
 		return
 	}
 
-	res := s.mock.SubmitBatch(&batch)
-	code := 0
-	if res.Code != da.StatusSuccess {
-		code = 3
-	}
+	height := s.store(storedBlob{namespace: namespace, data: blob})
 
 	resp, err := json.Marshal(cnc.TxResponse{
-		Height: int64(res.DAHeight),
-		Code:   uint32(code),
-		RawLog: res.Message,
+		Height: int64(height),
+		RawLog: "OK",
 	})
 	if err != nil {
 		s.writeError(w, err)
@@ -111,26 +153,55 @@ func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
 	s.writeResponse(w, resp)
 }
 
+// store includes the blobs at the current height, which is returned.
+func (s *Server) store(blobs ...storedBlob) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[s.daHeight] = append(s.blobs[s.daHeight], blobs...)
+	return s.daHeight
+}
+
+// getBlobs returns the blobs of the namespace included at the given height, which must be lower than the current
+// height.
+func (s *Server) getBlobs(height uint64, namespace celestia.Namespace) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if height >= s.daHeight {
+		return nil, errors.New("batch not found")
+	}
+	var blobs [][]byte
+	for _, blob := range s.blobs[height] {
+		if blob.namespace == namespace {
+			blobs = append(blobs, blob.data)
+		}
+	}
+	return blobs, nil
+}
+
+// legacyNamespace returns the version zero namespace of a hex encoded 8 bytes namespace ID of the gateway API.
+func legacyNamespace(namespaceID string) (celestia.Namespace, error) {
+	id, err := hex.DecodeString(namespaceID)
+	if err != nil {
+		return celestia.Namespace{}, err
+	}
+	return celestia.NewNamespaceV0(id)
+}
+
 func (s *Server) shares(w http.ResponseWriter, r *http.Request) {
-	height, err := parseHeight(r)
+	height, namespace, err := parseNamespacedRequest(r)
 	if err != nil {
 		s.writeError(w, err)
 		return
 	}
 
-	res := s.mock.RetrieveBatches(height)
-	if res.Code != da.StatusSuccess {
-		s.writeError(w, errors.New(res.Message))
+	blobs, err := s.getBlobs(height, namespace)
+	if err != nil {
+		s.writeError(w, err)
 		return
 	}
 
 	var nShares []NamespacedShare
-	for _, batch := range res.Batches {
-		blob, err := json.Marshal(batch)
-		if err != nil {
-			s.writeError(w, err)
-			return
-		}
+	for _, blob := range blobs {
 		delimited, err := marshalDelimited(blob)
 		if err != nil {
 			s.writeError(w, err)
@@ -144,7 +215,7 @@ func (s *Server) shares(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := json.Marshal(namespacedSharesResponse{
 		Shares: shares,
-		Height: res.DAHeight,
+		Height: height,
 	})
 	if err != nil {
 		s.writeError(w, err)
@@ -155,30 +226,21 @@ func (s *Server) shares(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) data(w http.ResponseWriter, r *http.Request) {
-	height, err := parseHeight(r)
+	height, namespace, err := parseNamespacedRequest(r)
 	if err != nil {
 		s.writeError(w, err)
 		return
 	}
 
-	res := s.mock.RetrieveBatches(height)
-	if res.Code != da.StatusSuccess {
-		s.writeError(w, errors.New(res.Message))
+	blobs, err := s.getBlobs(height, namespace)
+	if err != nil {
+		s.writeError(w, err)
 		return
 	}
 
-	data := make([][]byte, len(res.Batches))
-	for i := range res.Batches {
-		data[i], err = res.Batches[i].MarshalBinary()
-		if err != nil {
-			s.writeError(w, err)
-			return
-		}
-	}
-
 	resp, err := json.Marshal(namespacedDataResponse{
-		Data:   data,
-		Height: res.DAHeight,
+		Data:   blobs,
+		Height: height,
 	})
 	if err != nil {
 		s.writeError(w, err)
@@ -188,14 +250,176 @@ func (s *Server) data(w http.ResponseWriter, r *http.Request) {
 	s.writeResponse(w, resp)
 }
 
-func parseHeight(r *http.Request) (uint64, error) {
+// networkHead returns the header of the latest height whose blobs can be retrieved.
+func (s *Server) networkHead() *celestia.ExtendedHeader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &celestia.ExtendedHeader{Header: celestia.RawHeader{Height: json.Number(strconv.FormatUint(s.daHeight-1, 10))}}
+}
+
+func (s *Server) head(w http.ResponseWriter, r *http.Request) {
+	resp, err := json.Marshal(s.networkHead())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeResponse(w, resp)
+}
+
+func parseNamespacedRequest(r *http.Request) (uint64, celestia.Namespace, error) {
 	vars := mux2.Vars(r)
 
 	height, err := strconv.ParseUint(vars["height"], 10, 64)
 	if err != nil {
-		return 0, err
+		return 0, celestia.Namespace{}, err
+	}
+	namespace, err := legacyNamespace(vars["namespace"])
+	if err != nil {
+		return 0, celestia.Namespace{}, err
+	}
+	return height, namespace, nil
+}
+
+// rpc serves the JSON-RPC blob API.
+func (s *Server) rpc(w http.ResponseWriter, r *http.Request) {
+	var req celestia.RPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeRPCResponse(w, celestia.RPCResponse{JSONRPC: "2.0", Error: &celestia.RPCError{Code: -32700, Message: err.Error()}})
+		return
+	}
+
+	var result interface{}
+	var err error
+	switch req.Method {
+	case celestia.MethodSubmitPayForBlob:
+		result, err = s.submitPayForBlob(req.Params)
+	case celestia.MethodGetAll:
+		result, err = s.getAll(req.Params)
+	case celestia.MethodNetworkHead:
+		result = s.networkHead()
+	default:
+		err = &celestia.RPCError{Code: -32601, Message: "method not found: " + req.Method}
+	}
+
+	res := celestia.RPCResponse{JSONRPC: "2.0", ID: req.ID}
+	if err != nil {
+		var rpcErr *celestia.RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &celestia.RPCError{Code: 1, Message: err.Error()}
+		}
+		res.Error = rpcErr
+	} else {
+		res.Result, err = json.Marshal(result)
+		if err != nil {
+			res.Error = &celestia.RPCError{Code: -32603, Message: err.Error()}
+		}
+	}
+	s.writeRPCResponse(w, res)
+}
+
+// submitPayForBlob includes the blobs if the gas limit covers them and the fee meets the min gas price.
+func (s *Server) submitPayForBlob(params []json.RawMessage) (*celestia.TxResponse, error) {
+	if len(params) != 3 {
+		return nil, fmt.Errorf("expected 3 params, got %d", len(params))
+	}
+	var feeStr string
+	var gasLimit uint64
+	var blobs []*celestia.Blob
+	if err := json.Unmarshal(params[0], &feeStr); err != nil {
+		return nil, err
+	}
+	fee, err := strconv.ParseUint(feeStr, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params[1], &gasLimit); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params[2], &blobs); err != nil {
+		return nil, err
+	}
+
+	stored := make([]storedBlob, len(blobs))
+	sizes := make([]int, len(blobs))
+	txData := new(bytes.Buffer)
+	for i, blob := range blobs {
+		if len(blob.Namespace) != celestia.NamespaceSize {
+			return nil, celestia.ErrInvalidNamespace
+		}
+		copy(stored[i].namespace[:], blob.Namespace)
+		if err := stored[i].namespace.Validate(); err != nil {
+			return nil, err
+		}
+		stored[i].data = blob.Data
+		sizes[i] = len(blob.Data)
+		txData.Write(blob.Data)
+	}
+	txHash := sha256.Sum256(txData.Bytes())
+
+	if gas := celestia.EstimateGas(sizes...); gasLimit < gas {
+		// the transaction is included, but fails
+		return &celestia.TxResponse{
+			Height:    int64(s.store()),
+			TxHash:    hex.EncodeToString(txHash[:]),
+			Codespace: "sdk",
+			Code:      11,
+			RawLog:    fmt.Sprintf("out of gas: limit %d, used %d", gasLimit, gas),
+		}, nil
+	}
+	if fee < celestia.Fee(gasLimit, s.minGasPrice) {
+		return nil, &celestia.RPCError{Code: 1, Message: celestia.MempoolTimeoutMessage}
+	}
+
+	height := s.store(stored...)
+	s.mu.Lock()
+	s.fees = append(s.fees, fee)
+	s.mu.Unlock()
+	return &celestia.TxResponse{Height: int64(height), TxHash: hex.EncodeToString(txHash[:])}, nil
+}
+
+// getAll returns the blobs of the namespaces included at the given height.
+func (s *Server) getAll(params []json.RawMessage) ([]*celestia.Blob, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("expected 2 params, got %d", len(params))
+	}
+	var height uint64
+	var namespaces [][]byte
+	if err := json.Unmarshal(params[0], &height); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params[1], &namespaces); err != nil {
+		return nil, err
+	}
+
+	var blobs []*celestia.Blob
+	for _, ns := range namespaces {
+		var namespace celestia.Namespace
+		if len(ns) != celestia.NamespaceSize {
+			return nil, celestia.ErrInvalidNamespace
+		}
+		copy(namespace[:], ns)
+		data, err := s.getBlobs(height, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range data {
+			blobs = append(blobs, &celestia.Blob{Namespace: ns, Data: d})
+		}
+	}
+	if len(blobs) == 0 {
+		return nil, errors.New(celestia.BlobNotFoundMessage)
+	}
+	return blobs, nil
+}
+
+func (s *Server) writeRPCResponse(w http.ResponseWriter, res celestia.RPCResponse) {
+	payload, err := json.Marshal(res)
+	if err != nil {
+		s.writeError(w, err)
+		return
 	}
-	return height, nil
+	s.writeResponse(w, payload)
 }
 
 func (s *Server) writeResponse(w http.ResponseWriter, payload []byte) {
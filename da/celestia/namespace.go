@@ -0,0 +1,89 @@
+package celestia
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	// NamespaceVersionSize is the size of the namespace version.
+	NamespaceVersionSize = 1
+	// NamespaceIDSize is the size of the namespace ID.
+	NamespaceIDSize = 28
+	// NamespaceSize is the size of a namespace used by the blob API.
+	NamespaceSize = NamespaceVersionSize + NamespaceIDSize
+	// NamespaceVersionZero is the only namespace version available to users.
+	NamespaceVersionZero = uint8(0)
+	// NamespaceVersionZeroIDSize is the number of user specified bytes of a version zero namespace ID.
+	NamespaceVersionZeroIDSize = 10
+)
+
+// namespaceVersionZeroPrefix are the leading zero bytes of a version zero namespace ID.
+var namespaceVersionZeroPrefix = make([]byte, NamespaceIDSize-NamespaceVersionZeroIDSize)
+
+// ErrInvalidNamespace is returned for namespaces which can't be used to submit blobs.
+var ErrInvalidNamespace = errors.New("invalid namespace")
+
+// Namespace identifies the blobs of a rollapp in the blob API. It's a version byte followed by the namespace ID.
+type Namespace [NamespaceSize]byte
+
+// NewNamespaceV0 returns the version zero namespace with the given ID, left padded with zeros.
+func NewNamespaceV0(id []byte) (Namespace, error) {
+	var ns Namespace
+	if len(id) > NamespaceVersionZeroIDSize {
+		return ns, fmt.Errorf("%w: version zero ID must be at most %d bytes, got %d", ErrInvalidNamespace, NamespaceVersionZeroIDSize, len(id))
+	}
+	ns[0] = NamespaceVersionZero
+	copy(ns[NamespaceSize-len(id):], id)
+	return ns, nil
+}
+
+// Version returns the namespace version.
+func (n Namespace) Version() uint8 {
+	return n[0]
+}
+
+// ID returns the namespace ID.
+func (n Namespace) ID() []byte {
+	return n[NamespaceVersionSize:]
+}
+
+// IsZero returns true if the namespace isn't set.
+func (n Namespace) IsZero() bool {
+	return n == Namespace{}
+}
+
+// Validate checks that the namespace is a version zero namespace.
+func (n Namespace) Validate() error {
+	if n.Version() != NamespaceVersionZero {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidNamespace, n.Version())
+	}
+	if !bytes.HasPrefix(n.ID(), namespaceVersionZeroPrefix) {
+		return fmt.Errorf("%w: version zero ID must start with %d zero bytes", ErrInvalidNamespace, len(namespaceVersionZeroPrefix))
+	}
+	return nil
+}
+
+func (n Namespace) String() string {
+	return hex.EncodeToString(n[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (n Namespace) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts a hex encoded namespace.
+func (n *Namespace) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidNamespace, err)
+	}
+	if len(b) != NamespaceSize {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidNamespace, NamespaceSize, len(b))
+	}
+	copy(n[:], b)
+	return nil
+}
@@ -0,0 +1,181 @@
+package da
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// Blobs of a batch which is too big for a single DA blob are prefixed with these magic bytes. As with the batch
+// envelope, the first byte can never start a serialized batch.
+var (
+	chunkBlobMagic    = []byte{'D', 'Y', 'C'}
+	manifestBlobMagic = []byte{'D', 'Y', 'F'}
+)
+
+var (
+	// ErrBlobTooSmall is returned when the max blob size can't fit any chunk data.
+	ErrBlobTooSmall = errors.New("max blob size too small")
+	// ErrChunkMismatch is returned when the chunks of a batch don't match its manifest.
+	ErrChunkMismatch = errors.New("chunks don't match manifest")
+	// ErrInvalidManifest is returned when a manifest is rejected before any of its chunks is fetched.
+	ErrInvalidManifest = errors.New("invalid manifest")
+)
+
+// BlobChunk references a single chunk of a batch blob.
+type BlobChunk struct {
+	// DAHeight is the height the chunk was included at.
+	DAHeight uint64 `json:"da_height"`
+	// Hash is the sha256 hash of the chunk blob.
+	Hash []byte `json:"hash"`
+}
+
+// BlobManifest lists, in order, the chunks a batch blob was split into. Anyone can post blobs to the namespace, so the
+// manifest is signed by the sequencer which submitted the batch.
+type BlobManifest struct {
+	Chunks []BlobChunk `json:"chunks"`
+	// PubKey is the raw public key of the sequencer which signed the manifest.
+	PubKey []byte `json:"pub_key"`
+	// Signature is the signature of the chunks by PubKey.
+	Signature []byte `json:"signature"`
+}
+
+// MaxChunks returns the number of chunk blobs, of at most maxBlobSize bytes, a batch of the max decodable size is
+// split into.
+func MaxChunks(maxBlobSize int) int {
+	chunkSize := maxBlobSize - len(chunkBlobMagic)
+	if chunkSize <= 0 {
+		return 0
+	}
+	return (types.MaxBatchSizeBytes + chunkSize - 1) / chunkSize
+}
+
+// SplitBlob splits the blob into chunk blobs, each of at most maxBlobSize bytes.
+func SplitBlob(blob []byte, maxBlobSize int) ([][]byte, error) {
+	chunkSize := maxBlobSize - len(chunkBlobMagic)
+	if chunkSize <= 0 {
+		return nil, ErrBlobTooSmall
+	}
+	var chunks [][]byte
+	for len(blob) > 0 {
+		n := chunkSize
+		if n > len(blob) {
+			n = len(blob)
+		}
+		chunk := make([]byte, 0, len(chunkBlobMagic)+n)
+		chunk = append(chunk, chunkBlobMagic...)
+		chunk = append(chunk, blob[:n]...)
+		chunks = append(chunks, chunk)
+		blob = blob[n:]
+	}
+	return chunks, nil
+}
+
+// IsChunkBlob returns true if the blob is a chunk of a batch blob.
+func IsChunkBlob(blob []byte) bool {
+	return bytes.HasPrefix(blob, chunkBlobMagic)
+}
+
+// IsManifestBlob returns true if the blob is a manifest of a chunked batch blob.
+func IsManifestBlob(blob []byte) bool {
+	return bytes.HasPrefix(blob, manifestBlobMagic)
+}
+
+// ChunkHash returns the hash of the chunk blob, as referenced by the manifest.
+func ChunkHash(chunk []byte) []byte {
+	hash := sha256.Sum256(chunk)
+	return hash[:]
+}
+
+// MarshalBinary encodes BlobManifest into a manifest blob.
+func (m *BlobManifest) MarshalBinary() ([]byte, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, manifestBlobMagic...), data...), nil
+}
+
+// UnmarshalBinary decodes a manifest blob into BlobManifest.
+func (m *BlobManifest) UnmarshalBinary(blob []byte) error {
+	if !IsManifestBlob(blob) {
+		return errors.New("not a manifest blob")
+	}
+	return json.Unmarshal(blob[len(manifestBlobMagic):], m)
+}
+
+// Sign signs the chunks of the manifest with the key.
+func (m *BlobManifest) Sign(key crypto.PrivKey) error {
+	pubKey, err := key.GetPublic().Raw()
+	if err != nil {
+		return err
+	}
+	signBytes, err := json.Marshal(m.Chunks)
+	if err != nil {
+		return err
+	}
+	m.Signature, err = key.Sign(signBytes)
+	if err != nil {
+		return err
+	}
+	m.PubKey = pubKey
+	return nil
+}
+
+// Verify checks the manifest included at daHeight before any of its chunks is fetched. The manifest must be signed
+// by a key for which isSequencerKey returns true, and reference at most MaxChunks(maxBlobSize) chunks, included in
+// order during the maxSpan DA blocks up to the manifest.
+func (m *BlobManifest) Verify(daHeight uint64, maxBlobSize int, maxSpan uint64, isSequencerKey func(pubKey []byte) bool) error {
+	if len(m.Chunks) == 0 || len(m.Chunks) > MaxChunks(maxBlobSize) {
+		return fmt.Errorf("%w: %d chunks", ErrInvalidManifest, len(m.Chunks))
+	}
+	var minHeight uint64
+	if daHeight > maxSpan {
+		minHeight = daHeight - maxSpan
+	}
+	prevHeight := minHeight
+	for i, chunk := range m.Chunks {
+		if chunk.DAHeight < prevHeight || chunk.DAHeight > daHeight {
+			return fmt.Errorf("%w: chunk %d at DA height %d", ErrInvalidManifest, i, chunk.DAHeight)
+		}
+		prevHeight = chunk.DAHeight
+	}
+
+	if isSequencerKey == nil || !isSequencerKey(m.PubKey) {
+		return fmt.Errorf("%w: not signed by a sequencer", ErrInvalidManifest)
+	}
+	pubKey, err := crypto.UnmarshalEd25519PublicKey(m.PubKey)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidManifest, err)
+	}
+	signBytes, err := json.Marshal(m.Chunks)
+	if err != nil {
+		return err
+	}
+	ok, err := pubKey.Verify(signBytes, m.Signature)
+	if err != nil || !ok {
+		return fmt.Errorf("%w: bad signature", ErrInvalidManifest)
+	}
+	return nil
+}
+
+// Assemble checks the chunk blobs against the manifest and puts the original blob back together.
+func (m *BlobManifest) Assemble(chunks [][]byte) ([]byte, error) {
+	if len(chunks) != len(m.Chunks) {
+		return nil, fmt.Errorf("%w: expected %d chunks, got %d", ErrChunkMismatch, len(m.Chunks), len(chunks))
+	}
+	var blob []byte
+	for i, chunk := range chunks {
+		if !IsChunkBlob(chunk) || !bytes.Equal(ChunkHash(chunk), m.Chunks[i].Hash) {
+			return nil, fmt.Errorf("%w: chunk %d", ErrChunkMismatch, i)
+		}
+		blob = append(blob, chunk[len(chunkBlobMagic):]...)
+	}
+	return blob, nil
+}
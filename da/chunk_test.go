@@ -0,0 +1,87 @@
+package da_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dymensionxyz/dymint/da"
+)
+
+func TestSplitBlob(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	blob := getRandomBytes(2500)
+	chunks, err := da.SplitBlob(blob, 1000)
+	require.NoError(err)
+	require.Len(chunks, 3)
+
+	manifest := &da.BlobManifest{}
+	for i, chunk := range chunks {
+		assert.LessOrEqual(len(chunk), 1000)
+		assert.True(da.IsChunkBlob(chunk))
+		manifest.Chunks = append(manifest.Chunks, da.BlobChunk{DAHeight: uint64(i), Hash: da.ChunkHash(chunk)})
+	}
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	require.NoError(manifest.Sign(key))
+	manifestBlob, err := manifest.MarshalBinary()
+	require.NoError(err)
+	assert.True(da.IsManifestBlob(manifestBlob))
+	decoded := &da.BlobManifest{}
+	require.NoError(decoded.UnmarshalBinary(manifestBlob))
+	assert.Equal(manifest, decoded)
+
+	assembled, err := decoded.Assemble(chunks)
+	require.NoError(err)
+	assert.Equal(blob, assembled)
+
+	_, err = decoded.Assemble([][]byte{chunks[1], chunks[0], chunks[2]})
+	assert.ErrorIs(err, da.ErrChunkMismatch)
+	_, err = decoded.Assemble(chunks[:2])
+	assert.ErrorIs(err, da.ErrChunkMismatch)
+
+	_, err = da.SplitBlob(blob, 3)
+	assert.ErrorIs(err, da.ErrBlobTooSmall)
+}
+
+func TestVerifyManifest(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	rawKey, err := key.GetPublic().Raw()
+	require.NoError(err)
+	isSequencerKey := func(pubKey []byte) bool { return string(pubKey) == string(rawKey) }
+
+	newManifest := func(heights ...uint64) *da.BlobManifest {
+		manifest := &da.BlobManifest{}
+		for _, h := range heights {
+			manifest.Chunks = append(manifest.Chunks, da.BlobChunk{DAHeight: h, Hash: da.ChunkHash([]byte{byte(h)})})
+		}
+		require.NoError(manifest.Sign(key))
+		return manifest
+	}
+
+	assert.NoError(newManifest(95, 95, 99, 100).Verify(100, 1000, 10, isSequencerKey))
+	assert.ErrorIs(newManifest().Verify(100, 1000, 10, isSequencerKey), da.ErrInvalidManifest)
+	// chunks out of order, after the manifest, or before the span
+	assert.ErrorIs(newManifest(99, 95).Verify(100, 1000, 10, isSequencerKey), da.ErrInvalidManifest)
+	assert.ErrorIs(newManifest(99, 101).Verify(100, 1000, 10, isSequencerKey), da.ErrInvalidManifest)
+	assert.ErrorIs(newManifest(89, 99).Verify(100, 1000, 10, isSequencerKey), da.ErrInvalidManifest)
+	// more chunks than a batch of max size is split into
+	tooMany := make([]uint64, da.MaxChunks(1<<20)+1)
+	assert.ErrorIs(newManifest(tooMany...).Verify(100, 1<<20, 10, isSequencerKey), da.ErrInvalidManifest)
+
+	// not signed by a sequencer, or tampered with
+	assert.ErrorIs(newManifest(99).Verify(100, 1000, 10, func([]byte) bool { return false }), da.ErrInvalidManifest)
+	tampered := newManifest(98, 99)
+	tampered.Chunks[0].DAHeight = 97
+	assert.ErrorIs(tampered.Verify(100, 1000, 10, isSequencerKey), da.ErrInvalidManifest)
+}
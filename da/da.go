@@ -1,6 +1,12 @@
 package da
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+
 	"github.com/dymensionxyz/dymint/log"
 	"github.com/dymensionxyz/dymint/store"
 	"github.com/dymensionxyz/dymint/types"
@@ -27,6 +33,7 @@ type Client string
 const (
 	Mock     Client = "mock"
 	Celestia Client = "celestia"
+	Local    Client = "local"
 )
 
 // BaseResult contains basic information returned by DA layer.
@@ -45,6 +52,19 @@ type ResultSubmitBatch struct {
 	// Not sure if this needs to be bubbled up to other
 	// parts of Dymint.
 	// Hash hash.Hash
+
+	// Chunked is true if the batch was split across several blobs. DAHeight is then the height of the manifest blob.
+	Chunked bool
+
+	// Size is the total size in bytes of the blobs submitted for the batch.
+	Size int
+	// Blobs is the number of blobs the batch was submitted in.
+	Blobs int
+	// Fee is the fee paid for the submission, in the smallest denomination of the DA layer. It is zero if the DA
+	// client doesn't report fees.
+	Fee uint64
+	// Retries is the number of times the DA client retried the submission, e.g. on another endpoint.
+	Retries int
 }
 
 // ResultCheckBatch contains information about block availability, returned from DA layer client.
@@ -61,6 +81,9 @@ type ResultRetrieveBatch struct {
 	// Block is the full block retrieved from Data Availability Layer.
 	// If Code is not equal to StatusSuccess, it has to be nil.
 	Batches []*types.Batch
+	// Proofs are the inclusion proofs of the batches, Proofs[i] proving Batches[i]. They are set only by clients
+	// implementing HeaderRetriever.
+	Proofs []*InclusionProof
 }
 
 // DataAvailabilityLayerClient defines generic interface for DA layer block submission.
@@ -78,10 +101,10 @@ type DataAvailabilityLayerClient interface {
 	// SubmitBatch submits the passed in block to the DA layer.
 	// This should create a transaction which (potentially)
 	// triggers a state transition in the DA layer.
-	SubmitBatch(batch *types.Batch) ResultSubmitBatch
+	SubmitBatch(ctx context.Context, batch *types.Batch) ResultSubmitBatch
 
 	// CheckBatchAvailability queries DA layer to check data availability of block corresponding at given height.
-	CheckBatchAvailability(dataLayerHeight uint64) ResultCheckBatch
+	CheckBatchAvailability(ctx context.Context, dataLayerHeight uint64) ResultCheckBatch
 
 	GetClientType() Client
 }
@@ -90,5 +113,29 @@ type DataAvailabilityLayerClient interface {
 // block data from DA layer. This gives the ability to use it for block synchronization.
 type BatchRetriever interface {
 	// RetrieveBatches returns blocks at given data layer height from data availability layer.
-	RetrieveBatches(dataLayerHeight uint64) ResultRetrieveBatch
+	RetrieveBatches(ctx context.Context, dataLayerHeight uint64) ResultRetrieveBatch
+}
+
+// ManifestSigner is additional interface that can be implemented by Data Availability Layer Client that splits big
+// batches across several blobs. The manifests it submits are signed with the signer key, and the manifests it
+// retrieves are dropped unless they are signed by a key for which isSequencerKey returns true.
+type ManifestSigner interface {
+	SetManifestKeys(signer crypto.PrivKey, isSequencerKey func(pubKey []byte) bool)
+}
+
+// WithTimeout returns a copy of the context bounded by the given per-operation timeout.
+// Zero timeout leaves the context untouched.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// ErrorStatus returns the status code matching the error returned by a DA call.
+func ErrorStatus(err error) StatusCode {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return StatusTimeout
+	}
+	return StatusError
 }
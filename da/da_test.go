@@ -1,13 +1,26 @@
 package da_test
 
 import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"io"
 	"math/rand"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/celestiaorg/go-cnc"
+	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -15,13 +28,17 @@ import (
 	"github.com/dymensionxyz/dymint/da"
 	"github.com/dymensionxyz/dymint/da/celestia"
 	cmock "github.com/dymensionxyz/dymint/da/celestia/mock"
+	"github.com/dymensionxyz/dymint/da/faulty"
 	grpcda "github.com/dymensionxyz/dymint/da/grpc"
 	"github.com/dymensionxyz/dymint/da/grpc/mockserv"
+	"github.com/dymensionxyz/dymint/da/local"
 	"github.com/dymensionxyz/dymint/da/mock"
 	"github.com/dymensionxyz/dymint/da/registry"
+	"github.com/dymensionxyz/dymint/faults"
 	"github.com/dymensionxyz/dymint/log/test"
 	"github.com/dymensionxyz/dymint/store"
 	"github.com/dymensionxyz/dymint/types"
+	"github.com/dymensionxyz/dymint/types/pb/dalc"
 )
 
 const mockDaBlockTime = 100 * time.Millisecond
@@ -73,6 +90,13 @@ func doTestDALC(t *testing.T, dalc da.DataAvailabilityLayerClient) {
 	if _, ok := dalc.(*mock.DataAvailabilityLayerClient); ok {
 		conf = []byte(mockDaBlockTime.String())
 	}
+	switch dalc.(type) {
+	case *faulty.DataAvailabilityLayerClient, *faulty.HeaderRetrieverClient:
+		conf, _ = json.Marshal(faulty.Config{InnerConfig: mockDaBlockTime.String()})
+	}
+	if _, ok := dalc.(*local.DataAvailabilityLayerClient); ok {
+		conf, _ = json.Marshal(local.Config{RootDir: t.TempDir(), BlockTime: mockDaBlockTime})
+	}
 	if _, ok := dalc.(*celestia.DataAvailabilityLayerClient); ok {
 		config := celestia.Config{
 			BaseURL:     "http://localhost:26658",
@@ -105,29 +129,29 @@ func doTestDALC(t *testing.T, dalc da.DataAvailabilityLayerClient) {
 		Blocks:      []*types.Block{block2},
 	}
 
-	resp := dalc.SubmitBatch(batch1)
+	resp := dalc.SubmitBatch(context.Background(), batch1)
 	h1 := resp.DAHeight
 	assert.Equal(da.StatusSuccess, resp.Code)
 
-	resp = dalc.SubmitBatch(batch2)
+	resp = dalc.SubmitBatch(context.Background(), batch2)
 	h2 := resp.DAHeight
 	assert.Equal(da.StatusSuccess, resp.Code)
 
 	// wait a bit more than mockDaBlockTime, so dymint blocks can be "included" in mock block
 	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
 
-	check := dalc.CheckBatchAvailability(h1)
+	check := dalc.CheckBatchAvailability(context.Background(), h1)
 	// print the check result
 	t.Logf("CheckBatchAvailability result: %+v", check)
 	assert.Equal(da.StatusSuccess, check.Code)
 	assert.True(check.DataAvailable)
 
-	check = dalc.CheckBatchAvailability(h2)
+	check = dalc.CheckBatchAvailability(context.Background(), h2)
 	assert.Equal(da.StatusSuccess, check.Code)
 	assert.True(check.DataAvailable)
 
 	// this height should not be used by DALC
-	check = dalc.CheckBatchAvailability(h1 - 1)
+	check = dalc.CheckBatchAvailability(context.Background(), h1-1)
 	assert.Equal(da.StatusSuccess, check.Code)
 	assert.False(check.DataAvailable)
 }
@@ -158,6 +182,8 @@ func startMockGRPCServ(t *testing.T) *grpc.Server {
 	if err != nil {
 		t.Fatal(err)
 	}
+	// the server may be stopped before it starts serving, in which case the listener is left open
+	t.Cleanup(func() { _ = lis.Close() })
 	go func() {
 		_ = srv.Serve(lis)
 	}()
@@ -187,6 +213,13 @@ func doTestRetrieve(t *testing.T, dalc da.DataAvailabilityLayerClient) {
 	if _, ok := dalc.(*mock.DataAvailabilityLayerClient); ok {
 		conf = []byte(mockDaBlockTime.String())
 	}
+	switch dalc.(type) {
+	case *faulty.DataAvailabilityLayerClient, *faulty.HeaderRetrieverClient:
+		conf, _ = json.Marshal(faulty.Config{InnerConfig: mockDaBlockTime.String()})
+	}
+	if _, ok := dalc.(*local.DataAvailabilityLayerClient); ok {
+		conf, _ = json.Marshal(local.Config{RootDir: t.TempDir(), BlockTime: mockDaBlockTime})
+	}
 	if _, ok := dalc.(*celestia.DataAvailabilityLayerClient); ok {
 		config := celestia.Config{
 			BaseURL:     "http://localhost:26658",
@@ -221,7 +254,7 @@ func doTestRetrieve(t *testing.T, dalc da.DataAvailabilityLayerClient) {
 			},
 			},
 		}
-		resp := dalc.SubmitBatch(batch)
+		resp := dalc.SubmitBatch(context.Background(), batch)
 		assert.Equal(da.StatusSuccess, resp.Code, resp.Message)
 		time.Sleep(time.Duration(rand.Int63() % mockDaBlockTime.Milliseconds()))
 
@@ -234,20 +267,667 @@ func doTestRetrieve(t *testing.T, dalc da.DataAvailabilityLayerClient) {
 
 	for h, cnt := range countAtHeight {
 		t.Log("Retrieving block, DA Height", h)
-		ret := retriever.RetrieveBatches(h)
+		ret := retriever.RetrieveBatches(context.Background(), h)
 		assert.Equal(da.StatusSuccess, ret.Code, ret.Message)
 		require.NotEmpty(ret.Batches, h)
 		assert.Len(ret.Batches, cnt, h)
 	}
 
 	for b, h := range batches {
-		ret := retriever.RetrieveBatches(h)
+		ret := retriever.RetrieveBatches(context.Background(), h)
 		assert.Equal(da.StatusSuccess, ret.Code, h)
 		require.NotEmpty(ret.Batches, h)
 		assert.Contains(ret.Batches, b, h)
 	}
 }
 
+func TestCancellation(t *testing.T) {
+	grpcServer := startMockGRPCServ(t)
+	defer grpcServer.GracefulStop()
+
+	httpServer := startMockCelestiaNodeServer(t)
+	defer httpServer.Stop()
+
+	for _, client := range registry.RegisteredClients() {
+		t.Run(client, func(t *testing.T) {
+			doTestCancellation(t, registry.GetClient(client))
+		})
+	}
+}
+
+func doTestCancellation(t *testing.T, dalc da.DataAvailabilityLayerClient) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	conf := []byte{}
+	if _, ok := dalc.(*mock.DataAvailabilityLayerClient); ok {
+		conf = []byte(mockDaBlockTime.String())
+	}
+	switch dalc.(type) {
+	case *faulty.DataAvailabilityLayerClient, *faulty.HeaderRetrieverClient:
+		conf, _ = json.Marshal(faulty.Config{InnerConfig: mockDaBlockTime.String()})
+	}
+	if _, ok := dalc.(*local.DataAvailabilityLayerClient); ok {
+		conf, _ = json.Marshal(local.Config{RootDir: t.TempDir(), BlockTime: mockDaBlockTime})
+	}
+	if _, ok := dalc.(*celestia.DataAvailabilityLayerClient); ok {
+		config := celestia.Config{
+			BaseURL:     "http://localhost:26658",
+			Timeout:     30 * time.Second,
+			GasLimit:    3000000,
+			NamespaceID: [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+		}
+		conf, _ = json.Marshal(config)
+	}
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(dalc.Start())
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{StartHeight: 1, EndHeight: 1, Blocks: []*types.Block{b}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp := dalc.SubmitBatch(ctx, batch)
+	assert.Equal(da.StatusError, resp.Code, resp.Message)
+	check := dalc.CheckBatchAvailability(ctx, 1)
+	assert.Equal(da.StatusError, check.Code, check.Message)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	resp = dalc.SubmitBatch(ctx, batch)
+	assert.Equal(da.StatusTimeout, resp.Code, resp.Message)
+	if retriever, ok := dalc.(da.BatchRetriever); ok {
+		ret := retriever.RetrieveBatches(ctx, 1)
+		assert.Equal(da.StatusTimeout, ret.Code, ret.Message)
+	}
+}
+
+func TestLocalSharedDir(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	// the sequencer and the full node run as separate clients sharing the directory
+	conf, _ := json.Marshal(local.Config{RootDir: t.TempDir(), BlockTime: mockDaBlockTime})
+	sequencer := &local.DataAvailabilityLayerClient{}
+	require.NoError(sequencer.Init(conf, nil, test.NewLogger(t)))
+	require.NoError(sequencer.Start())
+	fullNode := &local.DataAvailabilityLayerClient{}
+	require.NoError(fullNode.Init(conf, nil, test.NewLogger(t)))
+	require.NoError(fullNode.Start())
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	resp := sequencer.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+
+	// the batch is not available before the DA block is done
+	ret := fullNode.RetrieveBatches(context.Background(), resp.DAHeight)
+	assert.Equal(da.StatusError, ret.Code)
+
+	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+	ret = fullNode.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Len(ret.Batches, 1)
+	assert.Equal(batch, ret.Batches[0])
+	header, err := fullNode.RetrieveHeader(context.Background(), resp.DAHeight)
+	require.NoError(err)
+	assert.NoError(ret.Proofs[0].Verify(header, ret.Batches[0]))
+}
+
+// TestMockUndecodableBlob tests that a blob which isn't a batch doesn't prevent retrieving the other batches at the
+// same height.
+func TestMockUndecodableBlob(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	kvStore := store.NewDefaultInMemoryKVStore()
+	dalc := &mock.DataAvailabilityLayerClient{}
+	require.NoError(dalc.Init([]byte(mockDaBlockTime.String()), kvStore, test.NewLogger(t)))
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	resp := dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+
+	// store a blob which isn't a batch before the batch, at the same DA height
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, resp.DAHeight)
+	hash := []byte("not a batch hash")
+	require.NoError(kvStore.Set(key, hash))
+	require.NoError(kvStore.Set(hash, []byte("not a batch")))
+	// the DA height advances only once the client is started, so both blobs are at the same height
+	require.NoError(dalc.Start())
+	require.Eventually(func() bool {
+		return dalc.RetrieveBatches(context.Background(), resp.DAHeight).Code == da.StatusSuccess
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ret := dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Len(ret.Batches, 1)
+	assert.Equal(batch, ret.Batches[0])
+	header, err := dalc.RetrieveHeader(context.Background(), resp.DAHeight)
+	require.NoError(err)
+	assert.NoError(ret.Proofs[0].Verify(header, ret.Batches[0]))
+}
+
+// TestFaultyReorder tests that the batches reordered by the faulty client keep matching their inclusion proofs.
+func TestFaultyReorder(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	_, ok := faulty.NewDataAvailabilityLayerClient(&grpcda.DataAvailabilityLayerClient{}).(da.HeaderRetriever)
+	assert.False(ok)
+
+	dalc := faulty.NewDataAvailabilityLayerClient(&mock.DataAvailabilityLayerClient{})
+	headerRetriever, ok := dalc.(da.HeaderRetriever)
+	require.True(ok)
+	conf, err := json.Marshal(faulty.Config{Config: faults.Config{ReorderRate: 1, Seed: 1}, InnerConfig: "500ms"})
+	require.NoError(err)
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(dalc.Start())
+
+	// both batches are included at the same DA height
+	var daHeight uint64
+	var submitted []*types.Batch
+	for daHeight == 0 {
+		submitted = nil
+		var heights []uint64
+		for i := uint64(1); i <= 2; i++ {
+			b := getRandomBlock(i, 10)
+			batch := &types.Batch{
+				StartHeight: i,
+				EndHeight:   i,
+				Blocks:      []*types.Block{b},
+				Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+			}
+			resp := dalc.SubmitBatch(context.Background(), batch)
+			require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+			submitted = append(submitted, batch)
+			heights = append(heights, resp.DAHeight)
+		}
+		if heights[0] == heights[1] {
+			daHeight = heights[0]
+		}
+	}
+	time.Sleep(time.Second)
+
+	header, err := headerRetriever.RetrieveHeader(context.Background(), daHeight)
+	require.NoError(err)
+	reordered := false
+	for !reordered {
+		ret := dalc.(da.BatchRetriever).RetrieveBatches(context.Background(), daHeight)
+		require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+		require.Len(ret.Batches, 2)
+		require.Len(ret.Proofs, 2)
+		for i, batch := range ret.Batches {
+			assert.NoError(ret.Proofs[i].Verify(header, batch))
+		}
+		reordered = ret.Batches[0].StartHeight == submitted[1].StartHeight
+	}
+}
+
+func TestRetrieveChunked(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	httpServer := startMockCelestiaNodeServer(t)
+	defer httpServer.Stop()
+
+	dalc := &celestia.DataAvailabilityLayerClient{}
+	conf, _ := json.Marshal(celestia.Config{
+		BaseURL:     "http://localhost:26658",
+		Timeout:     30 * time.Second,
+		GasLimit:    3000000,
+		NamespaceID: [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+		Codec:       types.BatchCodecNone,
+		MaxBlobSize: 1024,
+	})
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	key, _, err := crypto.GenerateEd25519Key(crand.Reader)
+	require.NoError(err)
+	rawKey, err := key.GetPublic().Raw()
+	require.NoError(err)
+	dalc.SetManifestKeys(key, func(pubKey []byte) bool { return bytes.Equal(pubKey, rawKey) })
+	require.NoError(dalc.Start())
+	defer func() {
+		require.NoError(dalc.Stop())
+	}()
+
+	b := getRandomBlock(1, 50)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	resp := dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	assert.True(resp.Chunked)
+	// the chunks and the manifest
+	blob, err := types.EncodeBatch(batch, types.BatchCodecNone)
+	require.NoError(err)
+	chunks, err := da.SplitBlob(blob, 1024)
+	require.NoError(err)
+	assert.Equal(len(chunks)+1, resp.Blobs)
+	assert.Greater(resp.Size, len(blob))
+
+	// wait a bit more than mockDaBlockTime, so the manifest can be "included" in mock block
+	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+
+	ret := dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Len(ret.Batches, 1)
+	assert.Equal(batch, ret.Batches[0])
+
+	// the manifests signed by other keys are dropped
+	otherKey, _, err := crypto.GenerateEd25519Key(crand.Reader)
+	require.NoError(err)
+	dalc.SetManifestKeys(otherKey, func(pubKey []byte) bool { return bytes.Equal(pubKey, rawKey) })
+	resp = dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+	ret = dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	assert.Empty(ret.Batches)
+}
+
+func TestCelestiaBlobAPI(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	minGasPrice := 0.005
+	httpServer := cmock.NewServer(mockDaBlockTime, test.NewLogger(t), cmock.WithMinGasPrice(minGasPrice))
+	l, err := net.Listen("tcp4", ":26659")
+	require.NoError(err)
+	require.NoError(httpServer.Start(l))
+	defer httpServer.Stop()
+
+	newClient := func(conf celestia.Config) *celestia.DataAvailabilityLayerClient {
+		dalc := &celestia.DataAvailabilityLayerClient{}
+		b, _ := json.Marshal(conf)
+		require.NoError(dalc.Init(b, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+		require.NoError(dalc.Start())
+		return dalc
+	}
+	config := celestia.Config{
+		BaseURL:     "http://localhost:26659",
+		Timeout:     30 * time.Second,
+		NamespaceID: [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+		API:         celestia.APIBlob,
+	}
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	blob, err := types.EncodeBatch(batch, config.Codec)
+	require.NoError(err)
+
+	// the submission times out in the mempool until the gas price reaches the min gas price
+	dalc := newClient(config)
+	resp := dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	gasPrice := celestia.DefaultGasPrice
+	retries := 0
+	for gasPrice < minGasPrice {
+		gasPrice *= celestia.DefaultGasPriceMultiplier
+		retries++
+	}
+	fee := celestia.Fee(celestia.EstimateGas(len(blob)), gasPrice)
+	assert.Equal([]uint64{fee}, httpServer.Fees())
+	assert.Equal(fee, resp.Fee)
+	assert.Equal(retries, resp.Retries)
+	assert.Equal(len(blob), resp.Size)
+	assert.Equal(1, resp.Blobs)
+
+	// wait a bit more than mockDaBlockTime, so the blob can be "included" in mock block
+	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+
+	check := dalc.CheckBatchAvailability(context.Background(), resp.DAHeight)
+	assert.Equal(da.StatusSuccess, check.Code, check.Message)
+	assert.True(check.DataAvailable)
+	ret := dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Len(ret.Batches, 1)
+	assert.Equal(batch, ret.Batches[0])
+
+	// blobs of other namespaces are not retrieved
+	otherConfig := config
+	otherConfig.Namespace, err = celestia.NewNamespaceV0([]byte("other"))
+	require.NoError(err)
+	ret = newClient(otherConfig).RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	assert.Empty(ret.Batches)
+
+	// the submission fails once the limits are reached
+	limitedConfigs := map[string]func(*celestia.Config){
+		"max gas price": func(c *celestia.Config) { c.MaxGasPrice = 0.004 },
+		"max fee":       func(c *celestia.Config) { c.MaxFee = 1 },
+		"gas limit":     func(c *celestia.Config) { c.GasLimit = 1000 },
+	}
+	for name, limit := range limitedConfigs {
+		limitedConfig := config
+		limit(&limitedConfig)
+		resp := newClient(limitedConfig).SubmitBatch(context.Background(), batch)
+		assert.Equal(da.StatusError, resp.Code, name)
+		assert.Contains(resp.Message, celestia.ErrFeeLimit.Error(), name)
+	}
+	assert.Len(httpServer.Fees(), 1)
+}
+
+// TestCelestiaUndecodableBlob tests that a height holding only blobs which aren't batches has no batch available.
+func TestCelestiaUndecodableBlob(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	httpServer := startMockCelestiaNodeServer(t)
+	defer httpServer.Stop()
+
+	config := celestia.Config{
+		BaseURL:     "http://localhost:26658",
+		Timeout:     30 * time.Second,
+		GasLimit:    3000000,
+		NamespaceID: [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+	}
+	dalc := &celestia.DataAvailabilityLayerClient{}
+	conf, err := json.Marshal(config)
+	require.NoError(err)
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(dalc.Start())
+	defer func() {
+		require.NoError(dalc.Stop())
+	}()
+
+	client, err := cnc.NewClient(config.BaseURL, cnc.WithTimeout(config.Timeout))
+	require.NoError(err)
+	res, err := client.SubmitPFD(context.Background(), config.NamespaceID, []byte("not a batch"), config.GasLimit)
+	require.NoError(err)
+
+	// wait a bit more than mockDaBlockTime, so the blob can be "included" in mock block
+	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+
+	check := dalc.CheckBatchAvailability(context.Background(), uint64(res.Height))
+	assert.Equal(da.StatusSuccess, check.Code, check.Message)
+	assert.False(check.DataAvailable)
+	ret := dalc.RetrieveBatches(context.Background(), uint64(res.Height))
+	assert.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	assert.Empty(ret.Batches)
+}
+
+func TestCelestiaFailover(t *testing.T) {
+	for _, api := range []string{celestia.APIPFD, celestia.APIBlob} {
+		t.Run(api, func(t *testing.T) {
+			doTestCelestiaFailover(t, api)
+		})
+	}
+}
+
+func doTestCelestiaFailover(t *testing.T, api string) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	httpServer := cmock.NewServer(mockDaBlockTime, test.NewLogger(t))
+	l, err := net.Listen("tcp4", ":26660")
+	require.NoError(err)
+	require.NoError(httpServer.Start(l))
+	defer httpServer.Stop()
+
+	// the proxy forwards submissions to the mock, but fails them once they are included
+	target, err := url.Parse("http://localhost:26660")
+	require.NoError(err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if r.URL.Path != "/submit_pfd" && !bytes.Contains(body, []byte(celestia.MethodSubmitPayForBlob)) {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+		proxy.ServeHTTP(httptest.NewRecorder(), r)
+		time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`"connection lost"`))
+	}))
+	defer failing.Close()
+
+	dalc := &celestia.DataAvailabilityLayerClient{}
+	conf, _ := json.Marshal(celestia.Config{
+		// nothing listens on the first endpoint
+		Endpoints:   []string{"http://localhost:26661", failing.URL, "http://localhost:26660"},
+		Timeout:     30 * time.Second,
+		GasLimit:    3000000,
+		NamespaceID: [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+		API:         api,
+	})
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(dalc.Start())
+	defer func() {
+		require.NoError(dalc.Stop())
+	}()
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	resp := dalc.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+
+	// the batch included through the failing endpoint isn't resubmitted
+	ret := dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Len(ret.Batches, 1)
+	assert.Equal(batch, ret.Batches[0])
+	if api == celestia.APIBlob {
+		assert.Len(httpServer.Fees(), 1)
+	}
+}
+
+func TestGRPCFailover(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	grpcServer := startMockGRPCServ(t)
+	defer grpcServer.GracefulStop()
+
+	dalc := &grpcda.DataAvailabilityLayerClient{}
+	conf, _ := json.Marshal(grpcda.Config{
+		// nothing listens on the first endpoint
+		Endpoints:         []string{"127.0.0.1:7981", grpcda.DefaultConfig.Host + ":" + strconv.Itoa(grpcda.DefaultConfig.Port)},
+		EndpointSelection: da.EndpointRoundRobin,
+	})
+	require.NoError(dalc.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(dalc.Start())
+	defer func() {
+		require.NoError(dalc.Stop())
+	}()
+
+	for i := uint64(1); i <= 3; i++ {
+		b := getRandomBlock(i, 10)
+		batch := &types.Batch{
+			StartHeight: i,
+			EndHeight:   i,
+			Blocks:      []*types.Block{b},
+			Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+		}
+		resp := dalc.SubmitBatch(context.Background(), batch)
+		require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+
+		time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+
+		ret := dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+		require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+		require.Len(ret.Batches, 1)
+		assert.Equal(batch, ret.Batches[0])
+	}
+}
+
+// includeAndFailServer is a gRPC DA server which includes the first submitted batch but reports an error, as when
+// the response is lost.
+type includeAndFailServer struct {
+	dalc.UnimplementedDALCServiceServer
+	mock        *mock.DataAvailabilityLayerClient
+	submissions int32
+}
+
+func (s *includeAndFailServer) SubmitBatch(ctx context.Context, request *dalc.SubmitBatchRequest) (*dalc.SubmitBatchResponse, error) {
+	batch, err := types.DecodeBatch(request.Blob)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.mock.SubmitBatch(ctx, batch)
+	if atomic.AddInt32(&s.submissions, 1) > 1 {
+		return &dalc.SubmitBatchResponse{Result: &dalc.DAResponse{Code: dalc.StatusCode(resp.Code), DataLayerHeight: resp.DAHeight}}, nil
+	}
+	for !s.mock.CheckBatchAvailability(ctx, resp.DAHeight).DataAvailable {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil, errors.New("connection lost")
+}
+
+func (s *includeAndFailServer) RetrieveBatches(ctx context.Context, request *dalc.RetrieveBatchesRequest) (*dalc.RetrieveBatchesResponse, error) {
+	resp := s.mock.RetrieveBatches(ctx, request.DataLayerHeight)
+	var blobs [][]byte
+	for _, batch := range resp.Batches {
+		blob, err := types.EncodeBatch(batch, types.DefaultBatchCodec)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+	return &dalc.RetrieveBatchesResponse{Result: &dalc.DAResponse{Code: dalc.StatusCode(resp.Code)}, Blobs: blobs}, nil
+}
+
+// TestGRPCFailoverFirstSubmission tests that a first batch which was included despite the error isn't resubmitted
+// when failing over, although no submission succeeded yet.
+func TestGRPCFailoverFirstSubmission(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	mockDA := &mock.DataAvailabilityLayerClient{}
+	require.NoError(mockDA.Init([]byte(mockDaBlockTime.String()), store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(mockDA.Start())
+	defer func() {
+		require.NoError(mockDA.Stop())
+	}()
+	srv := grpc.NewServer()
+	failSrv := &includeAndFailServer{mock: mockDA}
+	dalc.RegisterDALCServiceServer(srv, failSrv)
+	lis, err := net.Listen("tcp", "127.0.0.1:7983")
+	require.NoError(err)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+
+	client := &grpcda.DataAvailabilityLayerClient{}
+	conf, _ := json.Marshal(grpcda.Config{
+		// both endpoints are the same server, so the failover finds the batch included by the first attempt
+		Endpoints: []string{"127.0.0.1:7983", "127.0.0.1:7983"},
+	})
+	require.NoError(client.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(client.Start())
+	defer func() {
+		require.NoError(client.Stop())
+	}()
+	// the batch isn't included at the first DA heights
+	time.Sleep(2 * mockDaBlockTime)
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	resp := client.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	assert.Equal(1, resp.Retries)
+	assert.Equal(int32(1), atomic.LoadInt32(&failSrv.submissions))
+	assert.Greater(resp.DAHeight, uint64(1))
+
+	ret := client.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Equal([]*types.Batch{batch}, ret.Batches)
+}
+
+// envelopeServer is a gRPC DA server which records the submitted blobs and returns them, along with an
+// undecodable blob, when retrieving.
+type envelopeServer struct {
+	dalc.UnimplementedDALCServiceServer
+	blobs [][]byte
+}
+
+func (s *envelopeServer) SubmitBatch(_ context.Context, request *dalc.SubmitBatchRequest) (*dalc.SubmitBatchResponse, error) {
+	s.blobs = append(s.blobs, request.Blob)
+	return &dalc.SubmitBatchResponse{Result: &dalc.DAResponse{Code: dalc.StatusCode_STATUS_CODE_SUCCESS, DataLayerHeight: 1}}, nil
+}
+
+func (s *envelopeServer) RetrieveBatches(context.Context, *dalc.RetrieveBatchesRequest) (*dalc.RetrieveBatchesResponse, error) {
+	return &dalc.RetrieveBatchesResponse{
+		Result: &dalc.DAResponse{Code: dalc.StatusCode_STATUS_CODE_SUCCESS},
+		Blobs:  append([][]byte{[]byte("garbage")}, s.blobs...),
+	}, nil
+}
+
+// TestGRPCEnvelope tests that the gRPC client submits the batches encoded with its codec and decodes the
+// retrieved blobs.
+func TestGRPCEnvelope(t *testing.T) {
+	require := require.New(t)
+	srv := grpc.NewServer()
+	envelopeSrv := &envelopeServer{}
+	dalc.RegisterDALCServiceServer(srv, envelopeSrv)
+	lis, err := net.Listen("tcp", "127.0.0.1:7982")
+	require.NoError(err)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+
+	client := &grpcda.DataAvailabilityLayerClient{}
+	conf, _ := json.Marshal(grpcda.Config{Host: "127.0.0.1", Port: 7982, Codec: types.BatchCodecSnappy})
+	require.NoError(client.Init(conf, store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(client.Start())
+	defer func() {
+		require.NoError(client.Stop())
+	}()
+
+	b := getRandomBlock(1, 10)
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   1,
+		Blocks:      []*types.Block{b},
+		Commits:     []*types.Commit{{Height: b.Header.Height, HeaderHash: b.Header.Hash()}},
+	}
+	resp := client.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	expected, err := types.EncodeBatch(batch, types.BatchCodecSnappy)
+	require.NoError(err)
+	require.Equal([][]byte{expected}, envelopeSrv.blobs)
+	require.Equal(len(expected), resp.Size)
+
+	ret := client.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Equal([]*types.Batch{batch}, ret.Batches)
+}
+
 // copy-pasted from store/store_test.go
 func getRandomBlock(height uint64, nTxs int) *types.Block {
 	block := &types.Block{
@@ -0,0 +1,118 @@
+package da
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EndpointSelection is the order in which a DA client tries its endpoints.
+type EndpointSelection string
+
+const (
+	// EndpointPriority tries the endpoints in the configured order.
+	EndpointPriority EndpointSelection = "priority"
+	// EndpointRoundRobin spreads the calls across the endpoints.
+	EndpointRoundRobin EndpointSelection = "round-robin"
+)
+
+// EndpointPool tracks the health of the endpoints of a DA client and picks the order in which they are tried.
+// Healthy endpoints are tried first, unhealthy ones are kept as a last resort.
+type EndpointPool struct {
+	selection EndpointSelection
+
+	mu      sync.Mutex
+	healthy []bool
+	next    int
+}
+
+// NewEndpointPool creates a pool of n endpoints, all of them initially healthy. Empty selection means
+// EndpointPriority.
+func NewEndpointPool(n int, selection EndpointSelection) (*EndpointPool, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("no endpoints configured")
+	}
+	switch selection {
+	case "":
+		selection = EndpointPriority
+	case EndpointPriority, EndpointRoundRobin:
+	default:
+		return nil, fmt.Errorf("unknown endpoint selection: %s", selection)
+	}
+	healthy := make([]bool, n)
+	for i := range healthy {
+		healthy[i] = true
+	}
+	return &EndpointPool{selection: selection, healthy: healthy}, nil
+}
+
+// Candidates returns the indexes of the endpoints in the order they should be tried for the next call.
+func (p *EndpointPool) Candidates() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.healthy)
+	start := 0
+	if p.selection == EndpointRoundRobin {
+		start = p.next
+		p.next = (p.next + 1) % n
+	}
+	candidates := make([]int, 0, n)
+	var unhealthy []int
+	for j := 0; j < n; j++ {
+		i := (start + j) % n
+		if p.healthy[i] {
+			candidates = append(candidates, i)
+		} else {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	return append(candidates, unhealthy...)
+}
+
+// MarkFailed marks the endpoint as unhealthy.
+func (p *EndpointPool) MarkFailed(i int) {
+	p.setHealthy(i, false)
+}
+
+// MarkHealthy marks the endpoint as healthy.
+func (p *EndpointPool) MarkHealthy(i int) {
+	p.setHealthy(i, true)
+}
+
+// Healthy returns true if the endpoint is healthy.
+func (p *EndpointPool) Healthy(i int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.healthy[i]
+}
+
+func (p *EndpointPool) setHealthy(i int, healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.healthy[i] = healthy
+}
+
+// RunHealthCheck checks every endpoint at the given interval until the context is done, and updates their health
+// with the result.
+func (p *EndpointPool) RunHealthCheck(ctx context.Context, interval time.Duration, check func(ctx context.Context, i int) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for i := range p.healthy {
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			err := check(checkCtx, i)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			p.setHealthy(i, err == nil)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package da_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dymensionxyz/dymint/da"
+)
+
+func TestEndpointPool(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	_, err := da.NewEndpointPool(0, da.EndpointPriority)
+	assert.Error(err)
+	_, err = da.NewEndpointPool(1, "random")
+	assert.Error(err)
+
+	pool, err := da.NewEndpointPool(3, "")
+	require.NoError(err)
+	assert.Equal([]int{0, 1, 2}, pool.Candidates())
+	assert.Equal([]int{0, 1, 2}, pool.Candidates())
+	pool.MarkFailed(0)
+	assert.False(pool.Healthy(0))
+	assert.Equal([]int{1, 2, 0}, pool.Candidates())
+	pool.MarkHealthy(0)
+	assert.Equal([]int{0, 1, 2}, pool.Candidates())
+
+	pool, err = da.NewEndpointPool(3, da.EndpointRoundRobin)
+	require.NoError(err)
+	assert.Equal([]int{0, 1, 2}, pool.Candidates())
+	assert.Equal([]int{1, 2, 0}, pool.Candidates())
+	pool.MarkFailed(0)
+	assert.Equal([]int{2, 1, 0}, pool.Candidates())
+	assert.Equal([]int{1, 2, 0}, pool.Candidates())
+}
+
+func TestEndpointPoolHealthCheck(t *testing.T) {
+	pool, err := da.NewEndpointPool(2, da.EndpointPriority)
+	require.NoError(t, err)
+	pool.MarkFailed(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pool.RunHealthCheck(ctx, 10*time.Millisecond, func(_ context.Context, i int) error {
+		if i == 1 {
+			return errors.New("down")
+		}
+		return nil
+	})
+
+	assert.Eventually(t, func() bool {
+		return pool.Healthy(0) && !pool.Healthy(1)
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{0, 1}, pool.Candidates())
+}
@@ -0,0 +1,179 @@
+package faulty
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/faults"
+	"github.com/dymensionxyz/dymint/log"
+	"github.com/dymensionxyz/dymint/store"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// DataAvailabilityLayerClient wraps another Data Availability Layer Client and injects faults into its calls. It is
+// intended only for testing how the node behaves with slow, flaky or lying DA layers.
+type DataAvailabilityLayerClient struct {
+	inner  da.DataAvailabilityLayerClient
+	faults *faults.Injector
+	logger log.Logger
+}
+
+// Config contains configuration options for DataAvailabilityLayerClient.
+type Config struct {
+	faults.Config
+	// InnerConfig is passed as is to the wrapped client.
+	InnerConfig string `json:"inner_config"`
+}
+
+// HeaderRetrieverClient is a DataAvailabilityLayerClient wrapping a client which is able to retrieve DA headers.
+type HeaderRetrieverClient struct {
+	*DataAvailabilityLayerClient
+	retriever da.HeaderRetriever
+}
+
+var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
+var _ da.BatchRetriever = &DataAvailabilityLayerClient{}
+var _ da.HeaderRetriever = &HeaderRetrieverClient{}
+var _ da.ManifestSigner = &DataAvailabilityLayerClient{}
+
+// NewDataAvailabilityLayerClient returns a client injecting faults into the calls of inner client. The inner client
+// must implement da.BatchRetriever for the retrieval calls to succeed. The returned client is a HeaderRetrieverClient
+// if the inner client implements da.HeaderRetriever, so the batches it retrieves are verified the same way.
+func NewDataAvailabilityLayerClient(inner da.DataAvailabilityLayerClient) da.DataAvailabilityLayerClient {
+	client := &DataAvailabilityLayerClient{inner: inner}
+	if retriever, ok := inner.(da.HeaderRetriever); ok {
+		return &HeaderRetrieverClient{DataAvailabilityLayerClient: client, retriever: retriever}
+	}
+	return client
+}
+
+// Init parses the faults configuration and initializes the wrapped client.
+func (f *DataAvailabilityLayerClient) Init(config []byte, kvStore store.KVStore, logger log.Logger) error {
+	f.logger = logger
+	var conf Config
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &conf); err != nil {
+			return err
+		}
+	}
+	f.faults = faults.NewInjector(conf.Config)
+	return f.inner.Init([]byte(conf.InnerConfig), kvStore, logger)
+}
+
+// SetManifestKeys sets the manifest keys of the wrapped client, if it splits big batches across several blobs.
+func (f *DataAvailabilityLayerClient) SetManifestKeys(signer crypto.PrivKey, isSequencerKey func(pubKey []byte) bool) {
+	if manifestSigner, ok := f.inner.(da.ManifestSigner); ok {
+		manifestSigner.SetManifestKeys(signer, isSequencerKey)
+	}
+}
+
+// Start starts the wrapped client.
+func (f *DataAvailabilityLayerClient) Start() error {
+	f.logger.Info("starting faulty Data Availability Layer Client", "faults", f.faults.Config())
+	return f.inner.Start()
+}
+
+// Stop stops the wrapped client.
+func (f *DataAvailabilityLayerClient) Stop() error {
+	return f.inner.Stop()
+}
+
+// GetClientType returns the type of the wrapped client.
+func (f *DataAvailabilityLayerClient) GetClientType() da.Client {
+	return f.inner.GetClientType()
+}
+
+// SubmitBatch submits the batch to the wrapped client, unless a failure is injected.
+func (f *DataAvailabilityLayerClient) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	if err := f.faults.Call(ctx); err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
+	return f.inner.SubmitBatch(ctx, batch)
+}
+
+// CheckBatchAvailability queries the wrapped client, unless a failure is injected. The availability may be
+// reported wrongly if corruption is injected.
+func (f *DataAvailabilityLayerClient) CheckBatchAvailability(ctx context.Context, dataLayerHeight uint64) da.ResultCheckBatch {
+	if err := f.faults.Call(ctx); err != nil {
+		return da.ResultCheckBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
+	res := f.inner.CheckBatchAvailability(ctx, dataLayerHeight)
+	if res.Code == da.StatusSuccess && f.faults.Corrupt() {
+		f.logger.Debug("corrupting batch availability", "daHeight", dataLayerHeight)
+		res.DataAvailable = !res.DataAvailable
+	}
+	return res
+}
+
+// RetrieveBatches retrieves the batches from the wrapped client, unless a failure is injected. The batches may be
+// reordered, along with their inclusion proofs, or corrupted, in which case their inclusion proofs are left untouched.
+func (f *DataAvailabilityLayerClient) RetrieveBatches(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBatch {
+	retriever, ok := f.inner.(da.BatchRetriever)
+	if !ok {
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: "wrapped client can't retrieve batches"}}
+	}
+	if err := f.faults.Call(ctx); err != nil {
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
+	res := retriever.RetrieveBatches(ctx, dataLayerHeight)
+	if res.Code != da.StatusSuccess || len(res.Batches) == 0 {
+		return res
+	}
+
+	if len(res.Batches) > 1 && f.faults.Reorder() {
+		f.logger.Debug("reordering batches", "daHeight", dataLayerHeight)
+		i, j := f.faults.Intn(len(res.Batches)), f.faults.Intn(len(res.Batches))
+		res.Batches[i], res.Batches[j] = res.Batches[j], res.Batches[i]
+		if len(res.Proofs) == len(res.Batches) {
+			res.Proofs[i], res.Proofs[j] = res.Proofs[j], res.Proofs[i]
+		}
+	}
+	for i, batch := range res.Batches {
+		if !f.faults.Corrupt() {
+			continue
+		}
+		corrupted, err := corruptBatch(batch, f.faults.Intn(len(batch.Blocks)+1))
+		if err != nil {
+			f.logger.Error("failed to corrupt batch", "daHeight", dataLayerHeight, "error", err)
+			continue
+		}
+		f.logger.Debug("corrupting batch", "daHeight", dataLayerHeight, "startHeight", batch.StartHeight)
+		res.Batches[i] = corrupted
+	}
+	return res
+}
+
+// RetrieveHeader retrieves the DA header from the wrapped client, unless a failure is injected.
+func (f *HeaderRetrieverClient) RetrieveHeader(ctx context.Context, dataLayerHeight uint64) (*da.Header, error) {
+	if err := f.faults.Call(ctx); err != nil {
+		return nil, err
+	}
+	return f.retriever.RetrieveHeader(ctx, dataLayerHeight)
+}
+
+// corruptBatch returns a copy of the batch with the app hash of a block flipped. The block is picked by idx; if idx
+// is out of the blocks range, the last block is dropped instead.
+func corruptBatch(batch *types.Batch, idx int) (*types.Batch, error) {
+	b, err := batch.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	corrupted := new(types.Batch)
+	if err := corrupted.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	if idx < len(corrupted.Blocks) {
+		corrupted.Blocks[idx].Header.AppHash[0] ^= 0xff
+		return corrupted, nil
+	}
+	if len(corrupted.Blocks) > 0 {
+		corrupted.Blocks = corrupted.Blocks[:len(corrupted.Blocks)-1]
+	}
+	if len(corrupted.Commits) > 0 {
+		corrupted.Commits = corrupted.Commits[:len(corrupted.Commits)-1]
+	}
+	return corrupted, nil
+}
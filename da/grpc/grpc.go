@@ -1,11 +1,19 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 
 	"github.com/dymensionxyz/dymint/da"
 	"github.com/dymensionxyz/dymint/log"
@@ -18,8 +26,14 @@ import (
 type DataAvailabilityLayerClient struct {
 	config Config
 
-	conn   *grpc.ClientConn
-	client dalc.DALCServiceClient
+	conns   []*grpc.ClientConn
+	clients []dalc.DALCServiceClient
+	pool    *da.EndpointPool
+	cancel  context.CancelFunc
+
+	// lastHeight is the DA height of the last successful submission. The search for a batch which may already be
+	// included doesn't go below it when failing over.
+	lastHeight uint64
 
 	logger log.Logger
 }
@@ -29,14 +43,42 @@ type Config struct {
 	// TODO(tzdybal): add more options!
 	Host string `json:"host"`
 	Port int    `json:"port"`
+	// SubmitTimeout bounds a single SubmitBatch call. Zero means no timeout.
+	SubmitTimeout time.Duration `json:"submit_timeout"`
+	// RetrieveTimeout bounds a single CheckBatchAvailability or RetrieveBatches call. Zero means no timeout.
+	RetrieveTimeout time.Duration `json:"retrieve_timeout"`
+	// Endpoints are the host:port addresses of the gRPC servers. Calls fail over to the next endpoint on errors.
+	// Defaults to Host and Port.
+	Endpoints []string `json:"endpoints"`
+	// EndpointSelection is the order in which the endpoints are tried, priority or round-robin.
+	EndpointSelection da.EndpointSelection `json:"endpoint_selection"`
+	// HealthCheckInterval is the interval between health checks of the endpoints, if there are several of them.
+	HealthCheckInterval time.Duration `json:"health_check_interval"`
+	// Codec is the compression codec of submitted batches.
+	Codec types.BatchCodec `json:"codec"`
+	// FailoverSearchWindow is the number of DA heights, up to the current one, searched for a batch which may already
+	// be included when failing over, so it isn't submitted twice.
+	FailoverSearchWindow uint64 `json:"failover_search_window"`
 }
 
 // DefaultConfig defines default values for DataAvailabilityLayerClient configuration.
 var DefaultConfig = Config{
-	Host: "127.0.0.1",
-	Port: 7980,
+	Host:                 "127.0.0.1",
+	Port:                 7980,
+	Codec:                types.DefaultBatchCodec,
+	FailoverSearchWindow: DefaultFailoverSearchWindow,
 }
 
+const (
+	// DefaultHealthCheckInterval is the default interval between health checks of the endpoints.
+	DefaultHealthCheckInterval = 10 * time.Second
+	// DefaultFailoverSearchWindow is the default number of DA heights searched for a batch when failing over.
+	DefaultFailoverSearchWindow = 100
+)
+
+// errSubmitFailed is returned by a server which failed to submit the batch.
+var errSubmitFailed = errors.New("submit failed")
+
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BatchRetriever = &DataAvailabilityLayerClient{}
 
@@ -45,32 +87,93 @@ func (d *DataAvailabilityLayerClient) Init(config []byte, _ store.KVStore, logge
 	d.logger = logger
 	if len(config) == 0 {
 		d.config = DefaultConfig
-		return nil
+	} else {
+		d.config.Codec = types.DefaultBatchCodec
+		if err := json.Unmarshal(config, &d.config); err != nil {
+			return err
+		}
 	}
-	return json.Unmarshal(config, &d.config)
+	if len(d.config.Endpoints) == 0 {
+		d.config.Endpoints = []string{d.config.Host + ":" + strconv.Itoa(d.config.Port)}
+	}
+	if d.config.HealthCheckInterval == 0 {
+		d.config.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if d.config.FailoverSearchWindow == 0 {
+		d.config.FailoverSearchWindow = DefaultFailoverSearchWindow
+	}
+	var err error
+	d.pool, err = da.NewEndpointPool(len(d.config.Endpoints), d.config.EndpointSelection)
+	return err
 }
 
 // Start creates connection to gRPC server and instantiates gRPC client.
 func (d *DataAvailabilityLayerClient) Start() error {
-	d.logger.Info("starting GRPC DALC", "host", d.config.Host, "port", d.config.Port)
-	var err error
+	d.logger.Info("starting GRPC DALC", "endpoints", d.config.Endpoints)
 	var opts []grpc.DialOption
 	// TODO(tzdybal): add more options
 	opts = append(opts, grpc.WithInsecure())
-	d.conn, err = grpc.Dial(d.config.Host+":"+strconv.Itoa(d.config.Port), opts...)
-	if err != nil {
-		return err
+	for _, endpoint := range d.config.Endpoints {
+		conn, err := grpc.Dial(endpoint, opts...)
+		if err != nil {
+			return err
+		}
+		d.conns = append(d.conns, conn)
+		d.clients = append(d.clients, dalc.NewDALCServiceClient(conn))
 	}
 
-	d.client = dalc.NewDALCServiceClient(d.conn)
-
+	if len(d.conns) > 1 {
+		var ctx context.Context
+		ctx, d.cancel = context.WithCancel(context.Background())
+		go d.pool.RunHealthCheck(ctx, d.config.HealthCheckInterval, d.checkHealth)
+	}
 	return nil
 }
 
 // Stop closes connection to gRPC server.
 func (d *DataAvailabilityLayerClient) Stop() error {
 	d.logger.Info("stopoing GRPC DALC")
-	return d.conn.Close()
+	if d.cancel != nil {
+		d.cancel()
+	}
+	var err error
+	for _, conn := range d.conns {
+		if cerr := conn.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// checkHealth is the health check of the endpoints. Connections the gRPC client failed to establish are unhealthy.
+func (d *DataAvailabilityLayerClient) checkHealth(_ context.Context, i int) error {
+	switch state := d.conns[i].GetState(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return errors.New(state.String())
+	default:
+		return nil
+	}
+}
+
+// call runs fn against the endpoints in the order picked by the pool, failing over to the next endpoint on errors.
+// The error of the last endpoint is returned if all of them fail.
+func (d *DataAvailabilityLayerClient) call(ctx context.Context, fn func(client dalc.DALCServiceClient) error) error {
+	var err error
+	for _, i := range d.pool.Candidates() {
+		err = fn(d.clients[i])
+		if err == nil {
+			d.pool.MarkHealthy(i)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if len(d.clients) > 1 {
+			d.logger.Info("gRPC DA endpoint failed, failing over", "endpoint", d.config.Endpoints[i], "error", err)
+		}
+		d.pool.MarkFailed(i)
+	}
+	return err
 }
 
 // GetClientType returns client type.
@@ -79,27 +182,149 @@ func (d *DataAvailabilityLayerClient) GetClientType() da.Client {
 }
 
 // SubmitBatch proxies SubmitBatch request to gRPC server.
-func (d *DataAvailabilityLayerClient) SubmitBatch(batch *types.Batch) da.ResultSubmitBatch {
-	resp, err := d.client.SubmitBatch(context.TODO(), &dalc.SubmitBatchRequest{Batch: batch.ToProto()})
+// The batch is sent encoded into a DA blob, which the server submits as is.
+func (d *DataAvailabilityLayerClient) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	ctx, cancel := da.WithTimeout(ctx, d.config.SubmitTimeout)
+	defer cancel()
+
+	blob, err := types.EncodeBatch(batch, d.config.Codec)
+	if err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	var resp *dalc.SubmitBatchResponse
+	attempts := 0
+	err = d.call(ctx, func(client dalc.DALCServiceClient) error {
+		attempts++
+		if attempts > 1 {
+			height, err := d.findBatch(ctx, client, batch)
+			if err != nil {
+				return err
+			}
+			if height != 0 {
+				d.logger.Info("batch already included, skipping resubmission", "daHeight", height)
+				resp = &dalc.SubmitBatchResponse{Result: &dalc.DAResponse{Code: dalc.StatusCode_STATUS_CODE_SUCCESS, DataLayerHeight: height}}
+				return nil
+			}
+		}
+		var err error
+		resp, err = client.SubmitBatch(ctx, &dalc.SubmitBatchRequest{Blob: blob})
+		if err != nil {
+			return err
+		}
+		if da.StatusCode(resp.Result.Code) != da.StatusSuccess {
+			return fmt.Errorf("%w: %s", errSubmitFailed, resp.Result.Message)
+		}
+		return nil
+	})
+	if errors.Is(err, errSubmitFailed) {
+		return da.ResultSubmitBatch{
+			BaseResult: da.BaseResult{Code: da.StatusCode(resp.Result.Code), Message: resp.Result.Message},
+		}
+	}
 	if err != nil {
 		return da.ResultSubmitBatch{
-			BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()},
+			BaseResult: da.BaseResult{Code: errorStatus(err), Message: err.Error()},
 		}
 	}
+	atomic.StoreUint64(&d.lastHeight, resp.Result.DataLayerHeight)
 	return da.ResultSubmitBatch{
 		BaseResult: da.BaseResult{
 			Code:     da.StatusCode(resp.Result.Code),
 			Message:  resp.Result.Message,
 			DAHeight: resp.Result.DataLayerHeight,
 		},
+		Size:    len(blob),
+		Blobs:   1,
+		Retries: attempts - 1,
 	}
 }
 
+// findBatch searches the last FailoverSearchWindow DA heights for the batch, until a height the server can't retrieve
+// yet. The heights before the last successful submission aren't searched, as the batch was submitted after it.
+// It returns 0 if the batch isn't found.
+func (d *DataAvailabilityLayerClient) findBatch(ctx context.Context, client dalc.DALCServiceClient, batch *types.Batch) (uint64, error) {
+	from := atomic.LoadUint64(&d.lastHeight)
+	current, err := d.currentHeight(ctx, client, from)
+	if err != nil {
+		return 0, err
+	}
+	if current > d.config.FailoverSearchWindow && current-d.config.FailoverSearchWindow > from {
+		from = current - d.config.FailoverSearchWindow
+	}
+	if from == 0 {
+		from = 1
+	}
+	raw, err := batch.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	for h := from; ; h++ {
+		resp, err := client.RetrieveBatches(ctx, &dalc.RetrieveBatchesRequest{DataLayerHeight: h})
+		if err != nil {
+			return 0, err
+		}
+		if da.StatusCode(resp.Result.Code) != da.StatusSuccess {
+			return 0, nil
+		}
+		// The batches are compared in their serialized form, as the codec of the blobs may differ
+		for _, b := range d.decodeBatches(resp) {
+			other, err := b.MarshalBinary()
+			if err == nil && bytes.Equal(raw, other) {
+				return h, nil
+			}
+		}
+	}
+}
+
+// currentHeight returns the highest DA height the server can retrieve, searching exponentially and then by bisection
+// from the given height. It returns the given height if no higher height can be retrieved.
+func (d *DataAvailabilityLayerClient) currentHeight(ctx context.Context, client dalc.DALCServiceClient, from uint64) (uint64, error) {
+	retrievable := func(h uint64) (bool, error) {
+		resp, err := client.RetrieveBatches(ctx, &dalc.RetrieveBatchesRequest{DataLayerHeight: h})
+		if err != nil {
+			return false, err
+		}
+		return da.StatusCode(resp.Result.Code) == da.StatusSuccess, nil
+	}
+	lo, hi := from, from+1
+	for step := uint64(1); ; step *= 2 {
+		ok, err := retrievable(hi)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		lo, hi = hi, hi+step
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		ok, err := retrievable(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
 // CheckBatchAvailability proxies CheckBatchAvailability request to gRPC server.
-func (d *DataAvailabilityLayerClient) CheckBatchAvailability(dataLayerHeight uint64) da.ResultCheckBatch {
-	resp, err := d.client.CheckBatchAvailability(context.TODO(), &dalc.CheckBatchAvailabilityRequest{DataLayerHeight: dataLayerHeight})
+func (d *DataAvailabilityLayerClient) CheckBatchAvailability(ctx context.Context, dataLayerHeight uint64) da.ResultCheckBatch {
+	ctx, cancel := da.WithTimeout(ctx, d.config.RetrieveTimeout)
+	defer cancel()
+
+	var resp *dalc.CheckBatchAvailabilityResponse
+	err := d.call(ctx, func(client dalc.DALCServiceClient) error {
+		var err error
+		resp, err = client.CheckBatchAvailability(ctx, &dalc.CheckBatchAvailabilityRequest{DataLayerHeight: dataLayerHeight})
+		return err
+	})
 	if err != nil {
-		return da.ResultCheckBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		return da.ResultCheckBatch{BaseResult: da.BaseResult{Code: errorStatus(err), Message: err.Error()}}
 	}
 	return da.ResultCheckBatch{
 		BaseResult:    da.BaseResult{Code: da.StatusCode(resp.Result.Code), Message: resp.Result.Message},
@@ -108,27 +333,57 @@ func (d *DataAvailabilityLayerClient) CheckBatchAvailability(dataLayerHeight uin
 }
 
 // RetrieveBatches proxies RetrieveBlocks request to gRPC server.
-func (d *DataAvailabilityLayerClient) RetrieveBatches(dataLayerHeight uint64) da.ResultRetrieveBatch {
-	resp, err := d.client.RetrieveBatches(context.TODO(), &dalc.RetrieveBatchesRequest{DataLayerHeight: dataLayerHeight})
+func (d *DataAvailabilityLayerClient) RetrieveBatches(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBatch {
+	ctx, cancel := da.WithTimeout(ctx, d.config.RetrieveTimeout)
+	defer cancel()
+
+	var resp *dalc.RetrieveBatchesResponse
+	err := d.call(ctx, func(client dalc.DALCServiceClient) error {
+		var err error
+		resp, err = client.RetrieveBatches(ctx, &dalc.RetrieveBatchesRequest{DataLayerHeight: dataLayerHeight})
+		return err
+	})
 	if err != nil {
-		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: errorStatus(err), Message: err.Error()}}
 	}
 
-	batches := make([]*types.Batch, len(resp.Batches))
-	for i, batch := range resp.Batches {
-		var b types.Batch
-		err = b.FromProto(batch)
-		if err != nil {
-			return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
-		}
-		batches[i] = &b
-	}
 	return da.ResultRetrieveBatch{
 		BaseResult: da.BaseResult{
 			Code:     da.StatusCode(resp.Result.Code),
 			Message:  resp.Result.Message,
 			DAHeight: dataLayerHeight,
 		},
-		Batches: batches,
+		Batches: d.decodeBatches(resp),
+	}
+}
+
+// decodeBatches decodes the batches of the response, whether the server returned them as DA blobs or in their
+// protobuf form. Anyone can post to the namespace, so the blobs which aren't batches are skipped.
+func (d *DataAvailabilityLayerClient) decodeBatches(resp *dalc.RetrieveBatchesResponse) []*types.Batch {
+	var batches []*types.Batch
+	for _, blob := range resp.Blobs {
+		batch, err := types.DecodeBatch(blob)
+		if err != nil {
+			d.logger.Debug("failed to decode batch", "error", err)
+			continue
+		}
+		batches = append(batches, batch)
+	}
+	for _, pbBatch := range resp.Batches {
+		var batch types.Batch
+		if err := batch.FromProto(pbBatch); err != nil {
+			d.logger.Debug("failed to decode batch", "error", err)
+			continue
+		}
+		batches = append(batches, &batch)
+	}
+	return batches
+}
+
+// errorStatus maps gRPC errors to DA status codes, as gRPC doesn't wrap context errors.
+func errorStatus(err error) da.StatusCode {
+	if status.Code(err) == codes.DeadlineExceeded {
+		return da.StatusTimeout
 	}
+	return da.ErrorStatus(err)
 }
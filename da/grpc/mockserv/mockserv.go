@@ -12,7 +12,6 @@ import (
 	"github.com/dymensionxyz/dymint/store"
 	"github.com/dymensionxyz/dymint/types"
 	"github.com/dymensionxyz/dymint/types/pb/dalc"
-	"github.com/dymensionxyz/dymint/types/pb/dymint"
 )
 
 // GetServer creates and returns gRPC server instance.
@@ -39,13 +38,12 @@ type mockImpl struct {
 	mock mock.DataAvailabilityLayerClient
 }
 
-func (m *mockImpl) SubmitBatch(_ context.Context, request *dalc.SubmitBatchRequest) (*dalc.SubmitBatchResponse, error) {
-	var b types.Batch
-	err := b.FromProto(request.Batch)
+func (m *mockImpl) SubmitBatch(ctx context.Context, request *dalc.SubmitBatchRequest) (*dalc.SubmitBatchResponse, error) {
+	b, err := submittedBatch(request)
 	if err != nil {
 		return nil, err
 	}
-	resp := m.mock.SubmitBatch(&b)
+	resp := m.mock.SubmitBatch(ctx, b)
 	return &dalc.SubmitBatchResponse{
 		Result: &dalc.DAResponse{
 			Code:            dalc.StatusCode(resp.Code),
@@ -55,8 +53,8 @@ func (m *mockImpl) SubmitBatch(_ context.Context, request *dalc.SubmitBatchReque
 	}, nil
 }
 
-func (m *mockImpl) CheckBatchAvailability(_ context.Context, request *dalc.CheckBatchAvailabilityRequest) (*dalc.CheckBatchAvailabilityResponse, error) {
-	resp := m.mock.CheckBatchAvailability(request.DataLayerHeight)
+func (m *mockImpl) CheckBatchAvailability(ctx context.Context, request *dalc.CheckBatchAvailabilityRequest) (*dalc.CheckBatchAvailabilityResponse, error) {
+	resp := m.mock.CheckBatchAvailability(ctx, request.DataLayerHeight)
 	return &dalc.CheckBatchAvailabilityResponse{
 		Result: &dalc.DAResponse{
 			Code:    dalc.StatusCode(resp.Code),
@@ -66,17 +64,34 @@ func (m *mockImpl) CheckBatchAvailability(_ context.Context, request *dalc.Check
 	}, nil
 }
 
-func (m *mockImpl) RetrieveBatches(context context.Context, request *dalc.RetrieveBatchesRequest) (*dalc.RetrieveBatchesResponse, error) {
-	resp := m.mock.RetrieveBatches(request.DataLayerHeight)
-	batches := make([]*dymint.Batch, len(resp.Batches))
+func (m *mockImpl) RetrieveBatches(ctx context.Context, request *dalc.RetrieveBatchesRequest) (*dalc.RetrieveBatchesResponse, error) {
+	resp := m.mock.RetrieveBatches(ctx, request.DataLayerHeight)
+	blobs := make([][]byte, len(resp.Batches))
 	for i := range resp.Batches {
-		batches[i] = resp.Batches[i].ToProto()
+		blob, err := types.EncodeBatch(resp.Batches[i], types.DefaultBatchCodec)
+		if err != nil {
+			return nil, err
+		}
+		blobs[i] = blob
 	}
 	return &dalc.RetrieveBatchesResponse{
 		Result: &dalc.DAResponse{
 			Code:    dalc.StatusCode(resp.Code),
 			Message: resp.Message,
 		},
-		Batches: batches,
+		Blobs: blobs,
 	}, nil
 }
+
+// submittedBatch returns the batch of the request. The mock DA client encodes the batches itself, so the blob is
+// decoded back into the batch.
+func submittedBatch(request *dalc.SubmitBatchRequest) (*types.Batch, error) {
+	if len(request.Blob) > 0 {
+		return types.DecodeBatch(request.Blob)
+	}
+	var b types.Batch
+	if err := b.FromProto(request.Batch); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
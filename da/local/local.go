@@ -0,0 +1,263 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/log"
+	"github.com/dymensionxyz/dymint/store"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// DataAvailabilityLayerClient stores batches in a directory shared by all the dymint processes running on the same
+// machine, so a sequencer and full nodes can run as separate processes. DA block heights are simulated from the
+// genesis time of the directory, so all the processes agree on them.
+type DataAvailabilityLayerClient struct {
+	logger  log.Logger
+	config  Config
+	genesis genesis
+}
+
+// Config contains configuration options for DataAvailabilityLayerClient.
+type Config struct {
+	// RootDir is the directory the batches are stored in.
+	RootDir string `json:"root_dir"`
+	// BlockTime is the simulated DA block time. It is used only by the process which creates the directory.
+	BlockTime time.Duration `json:"block_time"`
+}
+
+// DefaultConfig defines default values for DataAvailabilityLayerClient configuration.
+var DefaultConfig = Config{
+	RootDir:   filepath.Join(os.TempDir(), "dymint-local-da"),
+	BlockTime: 3 * time.Second,
+}
+
+// genesis is shared by all the processes using the directory.
+type genesis struct {
+	GenesisTime time.Time     `json:"genesis_time"`
+	BlockTime   time.Duration `json:"block_time"`
+}
+
+const genesisFile = "genesis.json"
+
+var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
+var _ da.BatchRetriever = &DataAvailabilityLayerClient{}
+var _ da.HeaderRetriever = &DataAvailabilityLayerClient{}
+
+// Init sets the configuration options.
+func (l *DataAvailabilityLayerClient) Init(config []byte, _ store.KVStore, logger log.Logger) error {
+	l.logger = logger
+	l.config = DefaultConfig
+	if len(config) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(config, &l.config); err != nil {
+		return err
+	}
+	if l.config.BlockTime <= 0 {
+		return errors.New("block time must be positive")
+	}
+	return nil
+}
+
+// Start creates the shared directory, or joins it if it was already created by another process.
+func (l *DataAvailabilityLayerClient) Start() error {
+	l.logger.Info("starting local Data Availability Layer Client", "rootDir", l.config.RootDir)
+	if err := os.MkdirAll(l.config.RootDir, 0o750); err != nil {
+		return err
+	}
+
+	path := filepath.Join(l.config.RootDir, genesisFile)
+	l.genesis = genesis{GenesisTime: time.Now(), BlockTime: l.config.BlockTime}
+	data, err := json.Marshal(l.genesis)
+	if err != nil {
+		return err
+	}
+	// only the first process creates the genesis, the rest use it
+	err = writeFileExclusive(path, data)
+	if errors.Is(err, os.ErrExist) {
+		data, err = os.ReadFile(path) //#nosec
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &l.genesis); err != nil {
+			return fmt.Errorf("failed to read genesis of local DA: %w", err)
+		}
+		if l.genesis.BlockTime != l.config.BlockTime {
+			l.logger.Info("using block time of existing local DA", "blockTime", l.genesis.BlockTime)
+		}
+		return nil
+	}
+	return err
+}
+
+// Stop implements DataAvailabilityLayerClient interface.
+func (l *DataAvailabilityLayerClient) Stop() error {
+	l.logger.Info("stopping local Data Availability Layer Client")
+	return nil
+}
+
+// GetClientType returns client type.
+func (l *DataAvailabilityLayerClient) GetClientType() da.Client {
+	return da.Local
+}
+
+// SubmitBatch stores the batch at the current DA height.
+func (l *DataAvailabilityLayerClient) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	if err := ctx.Err(); err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
+	blob, err := types.EncodeBatch(batch, types.DefaultBatchCodec)
+	if err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+
+	// the blob is written aside and then moved to the height directory, so readers never see partial blobs
+	tmp, err := os.CreateTemp(l.config.RootDir, ".blob-*")
+	if err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	_, err = tmp.Write(blob)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+
+	daHeight := l.currentHeight()
+	heightDir := l.heightDir(daHeight)
+	if err := os.MkdirAll(heightDir, 0o750); err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	hash := sha256.Sum256(blob)
+	if err := os.Rename(tmp.Name(), filepath.Join(heightDir, hex.EncodeToString(hash[:]))); err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	l.logger.Debug("Submitted batch to local DA", "startHeight", batch.StartHeight, "endHeight", batch.EndHeight, "daHeight", daHeight)
+
+	return da.ResultSubmitBatch{
+		BaseResult: da.BaseResult{
+			Code:     da.StatusSuccess,
+			Message:  "OK",
+			DAHeight: daHeight,
+		},
+		Size:  len(blob),
+		Blobs: 1,
+	}
+}
+
+// CheckBatchAvailability checks if there are batches stored at given height.
+func (l *DataAvailabilityLayerClient) CheckBatchAvailability(ctx context.Context, dataLayerHeight uint64) da.ResultCheckBatch {
+	batchesRes := l.RetrieveBatches(ctx, dataLayerHeight)
+	return da.ResultCheckBatch{BaseResult: da.BaseResult{Code: batchesRes.Code, Message: batchesRes.Message}, DataAvailable: len(batchesRes.Batches) > 0}
+}
+
+// RetrieveBatches returns the batches stored at given height, together with their inclusion proofs.
+func (l *DataAvailabilityLayerClient) RetrieveBatches(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBatch {
+	if err := ctx.Err(); err != nil {
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
+	blobs, err := l.getBlobs(dataLayerHeight)
+	if err != nil {
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+
+	_, proofs := merkle.ProofsFromByteSlices(blobs)
+	var batches []*types.Batch
+	var batchProofs []*da.InclusionProof
+	for i, blob := range blobs {
+		batch, err := types.DecodeBatch(blob)
+		if err != nil {
+			l.logger.Error("failed to decode batch", "daHeight", dataLayerHeight, "position", i, "error", err)
+			continue
+		}
+		batches = append(batches, batch)
+		batchProofs = append(batchProofs, &da.InclusionProof{Blob: blob, Proof: *proofs[i]})
+	}
+
+	return da.ResultRetrieveBatch{
+		BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: dataLayerHeight},
+		Batches:    batches,
+		Proofs:     batchProofs,
+	}
+}
+
+// RetrieveHeader returns the header of the simulated DA block at given height. Its data root is the merkle root of
+// all the blobs stored at this height.
+func (l *DataAvailabilityLayerClient) RetrieveHeader(ctx context.Context, dataLayerHeight uint64) (*da.Header, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	blobs, err := l.getBlobs(dataLayerHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &da.Header{Height: dataLayerHeight, DataRoot: merkle.HashFromByteSlices(blobs)}, nil
+}
+
+// getBlobs returns the blobs stored at given height, ordered by their hash. The height must be lower than the
+// current height, so no more blobs are added to it.
+func (l *DataAvailabilityLayerClient) getBlobs(dataLayerHeight uint64) ([][]byte, error) {
+	if dataLayerHeight >= l.currentHeight() {
+		return nil, errors.New("batch not found")
+	}
+	entries, err := os.ReadDir(l.heightDir(dataLayerHeight))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs [][]byte
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		blob, err := os.ReadFile(filepath.Join(l.heightDir(dataLayerHeight), entry.Name())) //#nosec
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+	return blobs, nil
+}
+
+// currentHeight returns the simulated height of the DA block which is currently being built.
+func (l *DataAvailabilityLayerClient) currentHeight() uint64 {
+	return uint64(time.Since(l.genesis.GenesisTime)/l.genesis.BlockTime) + 1
+}
+
+func (l *DataAvailabilityLayerClient) heightDir(daHeight uint64) string {
+	return filepath.Join(l.config.RootDir, fmt.Sprintf("%020d", daHeight))
+}
+
+// writeFileExclusive atomically creates the file with the given data. It fails with os.ErrExist if the file exists.
+func writeFileExclusive(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Link(tmp.Name(), path)
+}
@@ -0,0 +1,136 @@
+package local_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/da/local"
+	"github.com/dymensionxyz/dymint/log/test"
+	"github.com/dymensionxyz/dymint/testutil"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+func newClient(t *testing.T, conf local.Config) *local.DataAvailabilityLayerClient {
+	t.Helper()
+	dalc := &local.DataAvailabilityLayerClient{}
+	b, err := json.Marshal(conf)
+	require.NoError(t, err)
+	require.NoError(t, dalc.Init(b, nil, test.NewLogger(t)))
+	require.NoError(t, dalc.Start())
+	t.Cleanup(func() {
+		require.NoError(t, dalc.Stop())
+	})
+	return dalc
+}
+
+func newBatch(t *testing.T, startHeight uint64) *types.Batch {
+	t.Helper()
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	batch, err := testutil.GenerateBatch(startHeight, startHeight+2, key)
+	require.NoError(t, err)
+	return batch
+}
+
+func TestRetrieveCurrentHeight(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	dalc := newClient(t, local.Config{RootDir: t.TempDir(), BlockTime: time.Hour})
+	resp := dalc.SubmitBatch(context.Background(), newBatch(t, 1))
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	assert.Equal(uint64(1), resp.DAHeight)
+
+	// blobs can still be added to the current height, so it can't be retrieved yet
+	ret := dalc.RetrieveBatches(context.Background(), resp.DAHeight)
+	assert.Equal(da.StatusError, ret.Code)
+	assert.Empty(ret.Batches)
+	_, err := dalc.RetrieveHeader(context.Background(), resp.DAHeight)
+	assert.Error(err)
+	check := dalc.CheckBatchAvailability(context.Background(), resp.DAHeight)
+	assert.False(check.DataAvailable)
+}
+
+func TestInclusionProofs(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	dalc := newClient(t, local.Config{RootDir: t.TempDir(), BlockTime: 200 * time.Millisecond})
+
+	// both batches are stored at the same DA height
+	var daHeight uint64
+	var submitted []*types.Batch
+	for daHeight == 0 {
+		submitted = []*types.Batch{newBatch(t, 1), newBatch(t, 4)}
+		var heights []uint64
+		for _, batch := range submitted {
+			resp := dalc.SubmitBatch(context.Background(), batch)
+			require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+			heights = append(heights, resp.DAHeight)
+		}
+		if heights[0] == heights[1] {
+			daHeight = heights[0]
+		}
+	}
+	require.Eventually(func() bool {
+		return dalc.RetrieveBatches(context.Background(), daHeight).Code == da.StatusSuccess
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ret := dalc.RetrieveBatches(context.Background(), daHeight)
+	require.Len(ret.Batches, 2)
+	require.Len(ret.Proofs, 2)
+	header, err := dalc.RetrieveHeader(context.Background(), daHeight)
+	require.NoError(err)
+	assert.Equal(daHeight, header.Height)
+	for i, batch := range ret.Batches {
+		assert.NoError(ret.Proofs[i].Verify(header, batch))
+	}
+	assert.ElementsMatch(submitted, ret.Batches)
+
+	// the proofs don't verify swapped batches nor another height
+	assert.ErrorIs(ret.Proofs[0].Verify(header, ret.Batches[1]), da.ErrInvalidInclusionProof)
+	otherHeader, err := dalc.RetrieveHeader(context.Background(), daHeight-1)
+	require.NoError(err)
+	assert.ErrorIs(ret.Proofs[0].Verify(otherHeader, ret.Batches[0]), da.ErrInvalidInclusionProof)
+}
+
+func TestSharedGenesis(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	rootDir := t.TempDir()
+	sequencer := newClient(t, local.Config{RootDir: rootDir, BlockTime: 200 * time.Millisecond})
+	// the block time of the process creating the directory is used by all the processes
+	fullNode := newClient(t, local.Config{RootDir: rootDir, BlockTime: time.Hour})
+
+	batch := newBatch(t, 1)
+	resp := sequencer.SubmitBatch(context.Background(), batch)
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	require.Eventually(func() bool {
+		return fullNode.CheckBatchAvailability(context.Background(), resp.DAHeight).DataAvailable
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ret := fullNode.RetrieveBatches(context.Background(), resp.DAHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Len(ret.Batches, 1)
+	assert.Equal(batch, ret.Batches[0])
+	header, err := fullNode.RetrieveHeader(context.Background(), resp.DAHeight)
+	require.NoError(err)
+	assert.NoError(ret.Proofs[0].Verify(header, ret.Batches[0]))
+
+	// the batches submitted by both processes are stored at the heights of the same DA blocks
+	resp = fullNode.SubmitBatch(context.Background(), newBatch(t, 4))
+	require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+	other := sequencer.SubmitBatch(context.Background(), newBatch(t, 7))
+	require.Equal(da.StatusSuccess, other.Code, other.Message)
+	assert.LessOrEqual(resp.DAHeight, other.DAHeight)
+	assert.GreaterOrEqual(resp.DAHeight+1, other.DAHeight)
+}
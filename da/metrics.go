@@ -0,0 +1,160 @@
+package da
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "data_availability"
+)
+
+// Metrics contains the metrics of the calls to the DA layer.
+type Metrics struct {
+	// Size in bytes of the blobs submitted to the DA layer.
+	SubmittedBytes metrics.Counter
+
+	// Histogram of the number of blobs a batch was submitted in.
+	BlobsPerBatch metrics.Histogram
+
+	// Histogram of the time it takes to submit a batch to the DA layer, in seconds.
+	SubmitDuration metrics.Histogram
+
+	// Number of batch submissions retried by the node, i.e. calls to the DA client following a failed one.
+	SubmitRetries metrics.Counter
+
+	// Number of retries done by the DA client within a successful submission, e.g. resubmissions with a higher fee or
+	// failovers to another endpoint.
+	SubmitClientRetries metrics.Counter
+
+	// Number of batch submissions which failed.
+	SubmitFailures metrics.Counter
+
+	// Fees paid for the submissions, in the smallest denomination of the DA layer. Only the fees reported by the DA
+	// client are counted.
+	FeesPaid metrics.Counter
+
+	// DA height of the last successful batch submission.
+	LastSubmittedHeight metrics.Gauge
+
+	// Histogram of the time it takes to retrieve the batches of a DA height, in seconds.
+	RetrieveDuration metrics.Histogram
+
+	// Number of batch retrievals which failed.
+	RetrieveFailures metrics.Counter
+
+	// DA height of the last successful batch retrieval.
+	LastRetrievedHeight metrics.Gauge
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		SubmittedBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "submitted_bytes",
+			Help:      "Size in bytes of the blobs submitted to the DA layer.",
+		}, labels).With(labelsAndValues...),
+
+		BlobsPerBatch: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "blobs_per_batch",
+			Help:      "Number of blobs a batch was submitted in.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 8),
+		}, labels).With(labelsAndValues...),
+
+		SubmitDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "submit_duration_seconds",
+			Help:      "Time it takes to submit a batch to the DA layer, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 14),
+		}, labels).With(labelsAndValues...),
+
+		SubmitRetries: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "submit_retries",
+			Help:      "Number of batch submissions retried by the node.",
+		}, labels).With(labelsAndValues...),
+
+		SubmitClientRetries: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "submit_client_retries",
+			Help:      "Number of retries done by the DA client within successful batch submissions.",
+		}, labels).With(labelsAndValues...),
+
+		SubmitFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "submit_failures",
+			Help:      "Number of batch submissions which failed.",
+		}, labels).With(labelsAndValues...),
+
+		FeesPaid: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "fees_paid",
+			Help:      "Fees paid for the submissions, in the smallest denomination of the DA layer, as reported by the DA client.",
+		}, labels).With(labelsAndValues...),
+
+		LastSubmittedHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "last_submitted_height",
+			Help:      "DA height of the last successful batch submission.",
+		}, labels).With(labelsAndValues...),
+
+		RetrieveDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "retrieve_duration_seconds",
+			Help:      "Time it takes to retrieve the batches of a DA height, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 12),
+		}, labels).With(labelsAndValues...),
+
+		RetrieveFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "retrieve_failures",
+			Help:      "Number of batch retrievals which failed.",
+		}, labels).With(labelsAndValues...),
+
+		LastRetrievedHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "last_retrieved_height",
+			Help:      "DA height of the last successful batch retrieval.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		SubmittedBytes:      discard.NewCounter(),
+		BlobsPerBatch:       discard.NewHistogram(),
+		SubmitDuration:      discard.NewHistogram(),
+		SubmitRetries:       discard.NewCounter(),
+		SubmitClientRetries: discard.NewCounter(),
+		SubmitFailures:      discard.NewCounter(),
+		FeesPaid:            discard.NewCounter(),
+		LastSubmittedHeight: discard.NewGauge(),
+		RetrieveDuration:    discard.NewHistogram(),
+		RetrieveFailures:    discard.NewCounter(),
+		LastRetrievedHeight: discard.NewGauge(),
+	}
+}
@@ -1,12 +1,16 @@
 package mock
 
 import (
+	"context"
 	"crypto/sha1" //#nosec
 	"encoding/binary"
+	"errors"
 	"math/rand"
 	"sync/atomic"
 	"time"
 
+	"github.com/tendermint/tendermint/crypto/merkle"
+
 	"github.com/dymensionxyz/dymint/da"
 	"github.com/dymensionxyz/dymint/log"
 	"github.com/dymensionxyz/dymint/store"
@@ -30,6 +34,7 @@ type config struct {
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BatchRetriever = &DataAvailabilityLayerClient{}
+var _ da.HeaderRetriever = &DataAvailabilityLayerClient{}
 
 // Init is called once to allow DA client to read configuration and initialize resources.
 func (m *DataAvailabilityLayerClient) Init(config []byte, dalcKV store.KVStore, logger log.Logger) error {
@@ -74,11 +79,14 @@ func (m *DataAvailabilityLayerClient) GetClientType() da.Client {
 // SubmitBatch submits the passed in batch to the DA layer.
 // This should create a transaction which (potentially)
 // triggers a state transition in the DA layer.
-func (m *DataAvailabilityLayerClient) SubmitBatch(batch *types.Batch) da.ResultSubmitBatch {
+func (m *DataAvailabilityLayerClient) SubmitBatch(ctx context.Context, batch *types.Batch) da.ResultSubmitBatch {
+	if err := ctx.Err(); err != nil {
+		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
 	daHeight := atomic.LoadUint64(&m.daHeight)
 	m.logger.Debug("Submitting batch to DA layer", "start height", batch.StartHeight, "end height", batch.EndHeight, "da height", daHeight)
 
-	blob, err := batch.MarshalBinary()
+	blob, err := types.EncodeBatch(batch, types.DefaultBatchCodec)
 	if err != nil {
 		return da.ResultSubmitBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
 	}
@@ -98,44 +106,79 @@ func (m *DataAvailabilityLayerClient) SubmitBatch(batch *types.Batch) da.ResultS
 			Message:  "OK",
 			DAHeight: daHeight,
 		},
+		Size:  len(blob),
+		Blobs: 1,
 	}
 }
 
 // CheckBatchAvailability queries DA layer to check data availability of block corresponding to given header.
-func (m *DataAvailabilityLayerClient) CheckBatchAvailability(dataLayerHeight uint64) da.ResultCheckBatch {
-	batchesRes := m.RetrieveBatches(dataLayerHeight)
-	return da.ResultCheckBatch{BaseResult: da.BaseResult{Code: batchesRes.Code}, DataAvailable: len(batchesRes.Batches) > 0}
+func (m *DataAvailabilityLayerClient) CheckBatchAvailability(ctx context.Context, dataLayerHeight uint64) da.ResultCheckBatch {
+	batchesRes := m.RetrieveBatches(ctx, dataLayerHeight)
+	return da.ResultCheckBatch{BaseResult: da.BaseResult{Code: batchesRes.Code, Message: batchesRes.Message}, DataAvailable: len(batchesRes.Batches) > 0}
 }
 
 // RetrieveBatches returns block at given height from data availability layer.
-func (m *DataAvailabilityLayerClient) RetrieveBatches(dataLayerHeight uint64) da.ResultRetrieveBatch {
+// The batches come with inclusion proofs against the header returned by RetrieveHeader.
+func (m *DataAvailabilityLayerClient) RetrieveBatches(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBatch {
+	if err := ctx.Err(); err != nil {
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.ErrorStatus(err), Message: err.Error()}}
+	}
+	blobs, err := m.getBlobs(dataLayerHeight)
+	if err != nil {
+		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+
+	_, proofs := merkle.ProofsFromByteSlices(blobs)
+	var batches []*types.Batch
+	var batchProofs []*da.InclusionProof
+	for i, blob := range blobs {
+		batch, err := types.DecodeBatch(blob)
+		if err != nil {
+			m.logger.Error("failed to decode batch", "daHeight", dataLayerHeight, "position", i, "error", err)
+			continue
+		}
+		batches = append(batches, batch)
+		batchProofs = append(batchProofs, &da.InclusionProof{Blob: blob, Proof: *proofs[i]})
+	}
+
+	return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusSuccess}, Batches: batches, Proofs: batchProofs}
+}
+
+// RetrieveHeader returns the header of the mock DA block at given height. Its data root is the merkle root of all
+// the blobs included at this height.
+func (m *DataAvailabilityLayerClient) RetrieveHeader(ctx context.Context, dataLayerHeight uint64) (*da.Header, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	blobs, err := m.getBlobs(dataLayerHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &da.Header{Height: dataLayerHeight, DataRoot: merkle.HashFromByteSlices(blobs)}, nil
+}
+
+// getBlobs returns the blobs included at given height, which must be lower than the current height.
+func (m *DataAvailabilityLayerClient) getBlobs(dataLayerHeight uint64) ([][]byte, error) {
 	if dataLayerHeight >= atomic.LoadUint64(&m.daHeight) {
-		return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: "batch not found"}}
+		return nil, errors.New("batch not found")
 	}
 
 	iter := m.dalcKV.PrefixIterator(uint64ToBinary(dataLayerHeight))
 	defer iter.Discard()
 
-	var batches []*types.Batch
+	var blobs [][]byte
 	for iter.Valid() {
 		hash := iter.Value()
 
 		blob, err := m.dalcKV.Get(hash)
 		if err != nil {
-			return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
-		}
-
-		batch := &types.Batch{}
-		err = batch.UnmarshalBinary(blob)
-		if err != nil {
-			return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+			return nil, err
 		}
-		batches = append(batches, batch)
+		blobs = append(blobs, blob)
 
 		iter.Next()
 	}
-
-	return da.ResultRetrieveBatch{BaseResult: da.BaseResult{Code: da.StatusSuccess}, Batches: batches}
+	return blobs, nil
 }
 
 func uint64ToBinary(daHeight uint64) []byte {
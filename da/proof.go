@@ -0,0 +1,63 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// ErrInvalidInclusionProof is returned when a batch inclusion proof doesn't verify against the DA header.
+var ErrInvalidInclusionProof = errors.New("invalid inclusion proof")
+
+// Header is the header of a DA block. Its DataRoot commits to all the blobs included in the block.
+type Header struct {
+	Height   uint64
+	DataRoot []byte
+}
+
+// InclusionProof proves that the blob of a batch is included in a DA block.
+type InclusionProof struct {
+	// Blob is the batch blob as it was posted to the DA layer.
+	Blob []byte
+	// Proof is the merkle proof of the blob against the DataRoot of the DA block.
+	Proof merkle.Proof
+}
+
+// Verify checks that the proof blob is included in the DA block with the given header, and that it holds the batch.
+func (p *InclusionProof) Verify(header *Header, batch *types.Batch) error {
+	if p == nil {
+		return fmt.Errorf("%w: DA height %d: missing proof", ErrInvalidInclusionProof, header.Height)
+	}
+	if err := p.Proof.Verify(header.DataRoot, p.Blob); err != nil {
+		return fmt.Errorf("%w: DA height %d: %s", ErrInvalidInclusionProof, header.Height, err)
+	}
+	proven, err := types.DecodeBatch(p.Blob)
+	if err != nil {
+		return fmt.Errorf("%w: DA height %d: %s", ErrInvalidInclusionProof, header.Height, err)
+	}
+	provenBytes, err := proven.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	batchBytes, err := batch.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(provenBytes, batchBytes) {
+		return fmt.Errorf("%w: DA height %d: blob doesn't match batch", ErrInvalidInclusionProof, header.Height)
+	}
+	return nil
+}
+
+// HeaderRetriever is additional interface that can be implemented by Data Availability Layer Client that is able to
+// retrieve DA block headers. Batches retrieved by such client must come with inclusion proofs, which are verified
+// against the headers before the batches are applied.
+type HeaderRetriever interface {
+	// RetrieveHeader returns the header of the DA block at given height.
+	RetrieveHeader(ctx context.Context, dataLayerHeight uint64) (*Header, error)
+}
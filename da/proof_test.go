@@ -0,0 +1,55 @@
+package da_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/da/mock"
+	"github.com/dymensionxyz/dymint/log/test"
+	"github.com/dymensionxyz/dymint/store"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+func TestMockInclusionProofs(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	dalc := &mock.DataAvailabilityLayerClient{}
+	require.NoError(dalc.Init([]byte(mockDaBlockTime.String()), store.NewDefaultInMemoryKVStore(), test.NewLogger(t)))
+	require.NoError(dalc.Start())
+
+	var daHeight uint64
+	for i := uint64(1); i <= 3; i++ {
+		b := getRandomBlock(i, 5)
+		resp := dalc.SubmitBatch(context.Background(), &types.Batch{StartHeight: i, EndHeight: i, Blocks: []*types.Block{b}})
+		require.Equal(da.StatusSuccess, resp.Code, resp.Message)
+		daHeight = resp.DAHeight
+	}
+	time.Sleep(mockDaBlockTime + 20*time.Millisecond)
+
+	ret := dalc.RetrieveBatches(context.Background(), daHeight)
+	require.Equal(da.StatusSuccess, ret.Code, ret.Message)
+	require.Len(ret.Proofs, len(ret.Batches))
+	header, err := dalc.RetrieveHeader(context.Background(), daHeight)
+	require.NoError(err)
+
+	for i, batch := range ret.Batches {
+		assert.NoError(ret.Proofs[i].Verify(header, batch))
+	}
+
+	// a proof doesn't hold for another batch
+	other := &types.Batch{StartHeight: 100, EndHeight: 100, Blocks: []*types.Block{getRandomBlock(100, 5)}}
+	assert.ErrorIs(ret.Proofs[0].Verify(header, other), da.ErrInvalidInclusionProof)
+
+	// nor against another header
+	tampered := &da.Header{Height: header.Height, DataRoot: make([]byte, len(header.DataRoot))}
+	assert.ErrorIs(ret.Proofs[0].Verify(tampered, ret.Batches[0]), da.ErrInvalidInclusionProof)
+
+	var missing *da.InclusionProof
+	assert.ErrorIs(missing.Verify(header, ret.Batches[0]), da.ErrInvalidInclusionProof)
+}
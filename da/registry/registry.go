@@ -3,7 +3,9 @@ package registry
 import (
 	"github.com/dymensionxyz/dymint/da"
 	"github.com/dymensionxyz/dymint/da/celestia"
+	"github.com/dymensionxyz/dymint/da/faulty"
 	"github.com/dymensionxyz/dymint/da/grpc"
+	"github.com/dymensionxyz/dymint/da/local"
 	"github.com/dymensionxyz/dymint/da/mock"
 )
 
@@ -12,6 +14,10 @@ var clients = map[string]func() da.DataAvailabilityLayerClient{
 	"mock":     func() da.DataAvailabilityLayerClient { return &mock.DataAvailabilityLayerClient{} },
 	"grpc":     func() da.DataAvailabilityLayerClient { return &grpc.DataAvailabilityLayerClient{} },
 	"celestia": func() da.DataAvailabilityLayerClient { return &celestia.DataAvailabilityLayerClient{} },
+	"local":    func() da.DataAvailabilityLayerClient { return &local.DataAvailabilityLayerClient{} },
+	"faulty-mock": func() da.DataAvailabilityLayerClient {
+		return faulty.NewDataAvailabilityLayerClient(&mock.DataAvailabilityLayerClient{})
+	},
 }
 
 // GetClient returns client identified by name.
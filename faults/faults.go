@@ -0,0 +1,137 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrInjected is returned by the wrapped layer clients when a failure is injected.
+var ErrInjected = errors.New("injected fault")
+
+// Config defines the faults injected into the calls of a wrapped layer client. Rates are probabilities between 0
+// and 1, evaluated independently for every call.
+type Config struct {
+	// Latency is added to every call.
+	Latency time.Duration `json:"latency"`
+	// LatencyJitter is the upper bound of a random delay added on top of Latency.
+	LatencyJitter time.Duration `json:"latency_jitter"`
+	// ErrorRate is the rate of calls failing with ErrInjected.
+	ErrorRate float64 `json:"error_rate"`
+	// TimeoutRate is the rate of calls hanging until the context is done or Timeout elapses.
+	TimeoutRate float64 `json:"timeout_rate"`
+	// Timeout bounds the hanging calls which have no deadline.
+	Timeout time.Duration `json:"timeout"`
+	// DropRate is the rate of dropped events.
+	DropRate float64 `json:"drop_rate"`
+	// ReorderRate is the rate of results or events delivered out of order.
+	ReorderRate float64 `json:"reorder_rate"`
+	// CorruptRate is the rate of corrupted results.
+	CorruptRate float64 `json:"corrupt_rate"`
+	// Seed makes the injected faults reproducible. Zero seeds from the current time.
+	Seed int64 `json:"seed"`
+}
+
+// DefaultTimeout is used for hanging calls when Config.Timeout is not set.
+const DefaultTimeout = 10 * time.Second
+
+// Injector decides which faults are injected into a call. It is safe for concurrent use.
+type Injector struct {
+	config Config
+	mtx    sync.Mutex
+	rand   *rand.Rand
+}
+
+// NewInjector returns an injector for the given configuration.
+func NewInjector(config Config) *Injector {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &Injector{config: config, rand: rand.New(rand.NewSource(seed))} //#nosec
+}
+
+// Config returns the configuration of the injector.
+func (i *Injector) Config() Config {
+	return i.config
+}
+
+// Hit returns true with the given probability.
+func (i *Injector) Hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	return i.rand.Float64() < rate
+}
+
+// Intn returns a random number in [0,n).
+func (i *Injector) Intn(n int) int {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	return i.rand.Intn(n)
+}
+
+// Delay waits for the configured latency, or until the context is done.
+func (i *Injector) Delay(ctx context.Context) error {
+	delay := i.config.Latency
+	if i.config.LatencyJitter > 0 {
+		i.mtx.Lock()
+		delay += time.Duration(i.rand.Int63n(int64(i.config.LatencyJitter)))
+		i.mtx.Unlock()
+	}
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	return sleep(ctx, delay)
+}
+
+// Call injects the faults which precede a call: latency, timeouts and errors. The call must not be passed to the
+// wrapped client if an error is returned.
+func (i *Injector) Call(ctx context.Context) error {
+	if err := i.Delay(ctx); err != nil {
+		return err
+	}
+	if i.Hit(i.config.TimeoutRate) {
+		if err := sleep(ctx, i.config.Timeout); err != nil {
+			return err
+		}
+		return context.DeadlineExceeded
+	}
+	if i.Hit(i.config.ErrorRate) {
+		return ErrInjected
+	}
+	return nil
+}
+
+// Drop returns true if the event should be dropped.
+func (i *Injector) Drop() bool {
+	return i.Hit(i.config.DropRate)
+}
+
+// Reorder returns true if the results or event should be delivered out of order.
+func (i *Injector) Reorder() bool {
+	return i.Hit(i.config.ReorderRate)
+}
+
+// Corrupt returns true if the result should be corrupted.
+func (i *Injector) Corrupt() bool {
+	return i.Hit(i.config.CorruptRate)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+package fraudproof
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/proxy"
+
+	"github.com/dymensionxyz/dymint/types"
+)
+
+var (
+	// ErrInvalidFraudProof is returned when a fraud proof doesn't prove a fraud.
+	ErrInvalidFraudProof = errors.New("invalid fraud proof")
+)
+
+// FraudProof proves that the sequencer committed to a wrong intermediate state root in a block.
+// It contains the block along with the intermediate state roots claimed by the sequencer, and the
+// witnesses of the state needed to execute the offending step of the block on top of the previous root.
+type FraudProof struct {
+	// Block is the block with the intermediate state roots claimed by the sequencer
+	Block *types.Block
+	// Commit is the commit of the block signed by the sequencer
+	Commit *types.Commit
+	// StepIndex is the index of the first intermediate state root which doesn't match the execution of the block.
+	// Index 0 is BeginBlock, index i in [1, len(txs)] is the DeliverTx of tx i-1 and the last index is EndBlock.
+	StepIndex int
+	// Request is the proto encoded abci.Request of the offending step
+	Request []byte
+	// Witnesses are the witnesses of the state before the offending step
+	Witnesses [][]byte
+}
+
+// FraudError is returned when executing a block yields intermediate state roots different from the block's.
+type FraudError struct {
+	Proof *FraudProof
+}
+
+func (e *FraudError) Error() string {
+	return fmt.Sprintf("%s: mismatch at height %d step %d", types.ErrInvalidISR, e.Proof.Block.Header.Height, e.Proof.StepIndex)
+}
+
+// Unwrap returns types.ErrInvalidISR, so the error can be checked with errors.Is.
+func (e *FraudError) Unwrap() error {
+	return types.ErrInvalidISR
+}
+
+// Generate generates a fraud proof for the given step of the block, which was just executed by the app and
+// resulted in a state root different from the one claimed by the block. The witnesses of the state before
+// the step are queried from the app.
+func Generate(app proxy.AppConnQuery, block *types.Block, stepIndex int, request *abci.Request) (*FraudProof, error) {
+	requestBytes, err := request.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := app.QuerySync(abci.RequestQuery{Path: types.FraudProofWitnessesQueryPath, Data: requestBytes})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("failed to get fraud proof witnesses from app: %s", resp.Log)
+	}
+	var witnesses [][]byte
+	if err := json.Unmarshal(resp.Value, &witnesses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fraud proof witnesses: %w", err)
+	}
+	return &FraudProof{
+		Block:     block,
+		StepIndex: stepIndex,
+		Request:   requestBytes,
+		Witnesses: witnesses,
+	}, nil
+}
+
+// ValidateBasic performs basic validation of a fraud proof.
+func (fp *FraudProof) ValidateBasic() error {
+	if fp.Block == nil || fp.Commit == nil {
+		return errors.New("missing block or commit")
+	}
+	if err := fp.Block.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := fp.Commit.ValidateBasic(); err != nil {
+		return err
+	}
+	isrs := fp.Block.Data.IntermediateStateRoots.RawRootsList
+	if len(isrs) != len(fp.Block.Data.Txs)+2 {
+		return fmt.Errorf("expected %d intermediate state roots, got %d", len(fp.Block.Data.Txs)+2, len(isrs))
+	}
+	if fp.StepIndex < 0 || fp.StepIndex >= len(isrs) {
+		return fmt.Errorf("step index %d out of range", fp.StepIndex)
+	}
+	if len(fp.Request) == 0 {
+		return errors.New("missing request")
+	}
+	return nil
+}
+
+// PreStateRoot returns the state root before the offending step. For BeginBlock it's the app hash of the
+// previous block, which is included in the header.
+func (fp *FraudProof) PreStateRoot() []byte {
+	if fp.StepIndex == 0 {
+		return fp.Block.Header.AppHash[:]
+	}
+	return fp.Block.Data.IntermediateStateRoots.RawRootsList[fp.StepIndex-1]
+}
+
+// ClaimedStateRoot returns the state root after the offending step, as claimed by the sequencer.
+func (fp *FraudProof) ClaimedStateRoot() []byte {
+	return fp.Block.Data.IntermediateStateRoots.RawRootsList[fp.StepIndex]
+}
+
+// Tx returns the offending tx, or nil in case the offending step is BeginBlock or EndBlock.
+func (fp *FraudProof) Tx() types.Tx {
+	if fp.StepIndex == 0 || fp.StepIndex > len(fp.Block.Data.Txs) {
+		return nil
+	}
+	return fp.Block.Data.Txs[fp.StepIndex-1]
+}
+
+type fraudProofJSON struct {
+	Block     []byte
+	Commit    []byte
+	StepIndex int
+	Request   []byte
+	Witnesses [][]byte
+}
+
+// MarshalBinary encodes FraudProof into binary form and returns it.
+func (fp *FraudProof) MarshalBinary() ([]byte, error) {
+	if fp.Block == nil || fp.Commit == nil {
+		return nil, errors.New("missing block or commit")
+	}
+	block, err := fp.Block.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := fp.Commit.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fraudProofJSON{
+		Block:     block,
+		Commit:    commit,
+		StepIndex: fp.StepIndex,
+		Request:   fp.Request,
+		Witnesses: fp.Witnesses,
+	})
+}
+
+// UnmarshalBinary decodes binary form of FraudProof into object.
+func (fp *FraudProof) UnmarshalBinary(data []byte) error {
+	var decoded fraudProofJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	block := new(types.Block)
+	if err := block.UnmarshalBinary(decoded.Block); err != nil {
+		return err
+	}
+	commit := new(types.Commit)
+	if err := commit.UnmarshalBinary(decoded.Commit); err != nil {
+		return err
+	}
+	*fp = FraudProof{
+		Block:     block,
+		Commit:    commit,
+		StepIndex: decoded.StepIndex,
+		Request:   decoded.Request,
+		Witnesses: decoded.Witnesses,
+	}
+	return nil
+}
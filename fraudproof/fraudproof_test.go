@@ -0,0 +1,151 @@
+package fraudproof_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	abciconv "github.com/dymensionxyz/dymint/conv/abci"
+	"github.com/dymensionxyz/dymint/fraudproof"
+	"github.com/dymensionxyz/dymint/mempool"
+	mempoolv1 "github.com/dymensionxyz/dymint/mempool/v1"
+	"github.com/dymensionxyz/dymint/mocks"
+	"github.com/dymensionxyz/dymint/state"
+	"github.com/dymensionxyz/dymint/testutil"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+type sequencersGetter struct {
+	sequencers []*types.Sequencer
+}
+
+func (s *sequencersGetter) GetSequencersList() []*types.Sequencer {
+	return s.sequencers
+}
+
+func TestFraudProof(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+	nsID := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	proposerKey := ed25519.GenPrivKey()
+	proposer := &types.Sequencer{
+		PublicKey: proposerKey.PubKey(),
+	}
+
+	getExecutor := func(app *mocks.Application) (*state.BlockExecutor, mempool.Mempool, proxy.AppConnQuery) {
+		clientCreator := proxy.NewLocalClientCreator(app)
+		abciClient, err := clientCreator.NewABCIClient()
+		require.NoError(err)
+		mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(abciClient), 0)
+		appConns := &mocks.AppConns{}
+		appConns.On("Consensus").Return(abciClient)
+		appConns.On("Query").Return(abciClient)
+		executor := state.NewBlockExecutor(proposerKey.PubKey().Address(), nsID, "test", mpool, appConns, nil, true, logger)
+		return executor, mpool, proxy.NewAppConnQuery(abciClient)
+	}
+
+	s := types.State{
+		NextValidators: tmtypes.NewValidatorSet(nil),
+		Validators:     tmtypes.NewValidatorSet(nil),
+		LastValidators: tmtypes.NewValidatorSet(nil),
+	}
+	s.InitialHeight = 1
+	s.LastBlockHeight = 0
+	s.ConsensusParams.Block.MaxBytes = 100
+	s.ConsensusParams.Block.MaxGas = 100000
+
+	// A faulty sequencer produces a block with wrong ISRs for its txs
+	sequencer, mpool, _ := getExecutor(testutil.GetFaultyAppMockWithISRs())
+	require.NoError(mpool.CheckTx([]byte{1, 2, 3, 4}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	require.NoError(mpool.CheckTx([]byte{5, 6, 7, 8}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	block := sequencer.CreateBlock(1, &types.Commit{Height: 0}, [32]byte{}, s)
+	require.Len(block.Data.Txs, 2)
+	_, _, err := sequencer.ExecuteBlock(context.Background(), s, block)
+	require.NoError(err)
+	abciHeaderPb := abciconv.ToABCIHeaderPB(&block.Header)
+	abciHeaderBytes, err := abciHeaderPb.Marshal()
+	require.NoError(err)
+	signature, err := proposerKey.Sign(abciHeaderBytes)
+	require.NoError(err)
+	commit := &types.Commit{
+		Height:     block.Header.Height,
+		HeaderHash: block.Header.Hash(),
+		Signatures: []types.Signature{signature},
+	}
+
+	// An honest full node detects the first tx as the offending step
+	fullNode, _, _ := getExecutor(testutil.GetAppMockWithISRs())
+	_, _, err = fullNode.ApplyBlock(context.Background(), s, block, commit, proposer)
+	assert.ErrorIs(err, types.ErrInvalidISR)
+	var fraudErr *fraudproof.FraudError
+	require.True(errors.As(err, &fraudErr))
+	proof := fraudErr.Proof
+	assert.Equal(1, proof.StepIndex)
+	assert.Equal(commit, proof.Commit)
+	assert.Equal(block.Data.Txs[0], proof.Tx())
+	require.NoError(proof.ValidateBasic())
+
+	// The proof survives the gossiping encoding
+	proofBytes, err := proof.MarshalBinary()
+	require.NoError(err)
+	decoded := new(fraudproof.FraudProof)
+	require.NoError(decoded.UnmarshalBinary(proofBytes))
+	assert.Equal(proof.StepIndex, decoded.StepIndex)
+	assert.Equal(proof.Request, decoded.Request)
+	assert.Equal(proof.Witnesses, decoded.Witnesses)
+	assert.Equal(block.Header.Hash(), decoded.Block.Header.Hash())
+
+	// Any honest node verifies the proof
+	_, _, query := getExecutor(testutil.GetAppMockWithISRs())
+	otherSequencer := &types.Sequencer{PublicKey: ed25519.GenPrivKey().PubKey(), Status: types.Proposer}
+	verifier := fraudproof.NewVerifier(query, &sequencersGetter{sequencers: []*types.Sequencer{proposer}})
+	assert.NoError(verifier.Verify(decoded))
+
+	// The proof is verified against the proposer of the block, although the proposer was rotated since
+	proposer.Status = types.Inactive
+	rotatedVerifier := fraudproof.NewVerifier(query, &sequencersGetter{sequencers: []*types.Sequencer{otherSequencer, proposer}})
+	assert.NoError(rotatedVerifier.Verify(decoded))
+
+	// A proof of a block not proposed by a sequencer is rejected
+	otherVerifier := fraudproof.NewVerifier(query, &sequencersGetter{sequencers: []*types.Sequencer{otherSequencer}})
+	assert.ErrorIs(otherVerifier.Verify(decoded), fraudproof.ErrInvalidFraudProof)
+
+	// A proof not signed by the proposer of the block is rejected
+	forged := *decoded
+	forged.Commit = &types.Commit{Height: commit.Height, HeaderHash: commit.HeaderHash, Signatures: []types.Signature{make([]byte, 64)}}
+	assert.ErrorIs(verifier.Verify(&forged), fraudproof.ErrInvalidFraudProof)
+
+	// A proof of an honest step is rejected
+	honestStep := *decoded
+	honestStep.StepIndex = 0
+	honestStep.Request, err = abci.ToRequestBeginBlock(abci.RequestBeginBlock{Header: abciHeaderPb}).Marshal()
+	require.NoError(err)
+	honestStep.Witnesses = [][]byte{block.Header.AppHash[:]}
+	assert.ErrorIs(verifier.Verify(&honestStep), fraudproof.ErrInvalidFraudProof)
+
+	// A proof with a request not matching the offending step is rejected
+	wrongRequest := *decoded
+	wrongRequest.Request, err = abci.ToRequestDeliverTx(abci.RequestDeliverTx{Tx: block.Data.Txs[1]}).Marshal()
+	require.NoError(err)
+	assert.ErrorIs(verifier.Verify(&wrongRequest), fraudproof.ErrInvalidFraudProof)
+
+	// A proof claiming a state root the sequencer didn't sign is rejected
+	wrongClaim := *decoded
+	wrongClaim.Block = new(types.Block)
+	*wrongClaim.Block = *decoded.Block
+	wrongClaim.Block.Data.IntermediateStateRoots.RawRootsList = append([][]byte{}, decoded.Block.Data.IntermediateStateRoots.RawRootsList...)
+	wrongClaim.Block.Data.IntermediateStateRoots.RawRootsList[proof.StepIndex] = make([]byte, 32)
+	assert.ErrorIs(verifier.Verify(&wrongClaim), fraudproof.ErrInvalidFraudProof)
+}
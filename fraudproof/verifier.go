@@ -0,0 +1,133 @@
+package fraudproof
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/proxy"
+
+	abciconv "github.com/dymensionxyz/dymint/conv/abci"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// SequencersGetter returns the sequencers which sign the blocks.
+type SequencersGetter interface {
+	GetSequencersList() []*types.Sequencer
+}
+
+// Verifier verifies fraud proofs by executing the offending step on top of the pre-state witnesses.
+type Verifier struct {
+	app              proxy.AppConnQuery
+	sequencersGetter SequencersGetter
+}
+
+// NewVerifier creates a new Verifier which executes the state transitions using the given app.
+func NewVerifier(app proxy.AppConnQuery, sequencersGetter SequencersGetter) *Verifier {
+	return &Verifier{
+		app:              app,
+		sequencersGetter: sequencersGetter,
+	}
+}
+
+// Verify checks that the fraud proof proves a fraud of the sequencer, i.e. the block was signed by its proposer,
+// the offending step belongs to the block and executing it on top of the pre-state doesn't result in the
+// state root claimed by the sequencer. It returns nil only in case the fraud is proven.
+func (v *Verifier) Verify(proof *FraudProof) error {
+	if err := proof.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFraudProof, err)
+	}
+	if err := v.verifyBlock(proof.Block, proof.Commit); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFraudProof, err)
+	}
+	if err := verifyRequest(proof); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFraudProof, err)
+	}
+
+	transition, err := json.Marshal(types.StateTransition{
+		PreStateRoot: proof.PreStateRoot(),
+		Witnesses:    proof.Witnesses,
+		Request:      proof.Request,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := v.app.QuerySync(abci.RequestQuery{Path: types.StateTransitionQueryPath, Data: transition})
+	if err != nil {
+		return err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return fmt.Errorf("%w: failed to execute state transition: %s", ErrInvalidFraudProof, resp.Log)
+	}
+	if bytes.Equal(resp.Value, proof.ClaimedStateRoot()) {
+		return fmt.Errorf("%w: state transition results in the claimed state root", ErrInvalidFraudProof)
+	}
+	return nil
+}
+
+// verifyBlock checks that the block was signed by its proposer and its data, including the intermediate state roots
+// the pre-state and claimed state roots are taken from, matches the data hash covered by the signature.
+func (v *Verifier) verifyBlock(block *types.Block, commit *types.Commit) error {
+	if commit.Height != block.Header.Height || commit.HeaderHash != block.Header.Hash() {
+		return fmt.Errorf("commit doesn't match block")
+	}
+	abciHeaderPb := abciconv.ToABCIHeaderPB(&block.Header)
+	abciHeaderBytes, err := abciHeaderPb.Marshal()
+	if err != nil {
+		return err
+	}
+	proposer := v.blockProposer(&block.Header)
+	if proposer == nil {
+		return fmt.Errorf("block proposer %X isn't a sequencer", block.Header.ProposerAddress)
+	}
+	if err := commit.Validate(proposer, abciHeaderBytes); err != nil {
+		return err
+	}
+	if len(block.Data.IntermediateStateRoots.RawRootsList) == 0 {
+		return fmt.Errorf("block doesn't commit to intermediate state roots")
+	}
+	if block.Data.Hash() != block.Header.DataHash {
+		return fmt.Errorf("data doesn't match data hash")
+	}
+	return nil
+}
+
+// blockProposer returns the sequencer the block is stamped with, which may not be the proposer anymore.
+// It returns nil if there is no such sequencer.
+func (v *Verifier) blockProposer(header *types.Header) *types.Sequencer {
+	for _, sequencer := range v.sequencersGetter.GetSequencersList() {
+		if sequencer.PublicKey != nil && bytes.Equal(tmcrypto.AddressHash(sequencer.PublicKey.Bytes()), header.ProposerAddress) {
+			return sequencer
+		}
+	}
+	return nil
+}
+
+// verifyRequest checks that the request of the fraud proof is the offending step of the block.
+func verifyRequest(proof *FraudProof) error {
+	var request abci.Request
+	if err := request.Unmarshal(proof.Request); err != nil {
+		return err
+	}
+	height := int64(proof.Block.Header.Height)
+	switch r := request.Value.(type) {
+	case *abci.Request_BeginBlock:
+		if proof.StepIndex != 0 || r.BeginBlock.Header.Height != height {
+			return fmt.Errorf("begin block request doesn't match step")
+		}
+	case *abci.Request_DeliverTx:
+		tx := proof.Tx()
+		if tx == nil || !bytes.Equal(r.DeliverTx.Tx, tx) {
+			return fmt.Errorf("deliver tx request doesn't match step")
+		}
+	case *abci.Request_EndBlock:
+		if proof.StepIndex != len(proof.Block.Data.Txs)+1 || r.EndBlock.Height != height {
+			return fmt.Errorf("end block request doesn't match step")
+		}
+	default:
+		return fmt.Errorf("unexpected request type %T", request.Value)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/dymensionxyz/dymint/config"
+	dafaulty "github.com/dymensionxyz/dymint/da/faulty"
+	"github.com/dymensionxyz/dymint/faults"
+	"github.com/dymensionxyz/dymint/mocks"
+	"github.com/dymensionxyz/dymint/settlement"
+	slfaulty "github.com/dymensionxyz/dymint/settlement/faulty"
+	slmock "github.com/dymensionxyz/dymint/settlement/mock"
+)
+
+// The scenarios below run an aggregator against faulty DA and settlement layers, and check that it either recovers
+// or fails safely: nothing is settled without the layers accepting it, and the node stops cleanly.
+
+func TestFaultyLayersRecover(t *testing.T) {
+	daFaults := faults.Config{Latency: 10 * time.Millisecond, LatencyJitter: 20 * time.Millisecond, ErrorRate: 0.3}
+	slFaults := faults.Config{Latency: 10 * time.Millisecond, ErrorRate: 0.3, DropRate: 0.3, ReorderRate: 0.3}
+	node := startFaultyNode(t, daFaults, slFaults)
+
+	require.Eventually(t, func() bool {
+		settledHeight, err := node.Store.LoadSettledHeight()
+		return err == nil && settledHeight >= 10
+	}, 30*time.Second, 100*time.Millisecond)
+
+	settledHeight, err := node.Store.LoadSettledHeight()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, node.Store.Height(), settledHeight)
+	stopFaultyNode(t, node)
+}
+
+func TestFaultyDATimeouts(t *testing.T) {
+	daFaults := faults.Config{TimeoutRate: 1, Timeout: 100 * time.Millisecond}
+	node := startFaultyNode(t, daFaults, faults.Config{})
+
+	require.Eventually(t, func() bool {
+		return node.Store.Height() > defaultFaultyBatchSize
+	}, 10*time.Second, 50*time.Millisecond)
+	time.Sleep(time.Second)
+
+	// no batch can be settled without being posted to the DA layer
+	settledHeight, err := node.Store.LoadSettledHeight()
+	require.NoError(t, err)
+	assert.Zero(t, settledHeight)
+	_, err = node.settlementlc.RetrieveBatch()
+	assert.ErrorIs(t, err, settlement.ErrBatchNotFound)
+	stopFaultyNode(t, node)
+}
+
+func TestFaultySettlementRejects(t *testing.T) {
+	node := startFaultyNode(t, faults.Config{}, faults.Config{ErrorRate: 1})
+
+	require.Eventually(t, func() bool {
+		return node.Store.Height() > defaultFaultyBatchSize
+	}, 10*time.Second, 50*time.Millisecond)
+	time.Sleep(time.Second)
+
+	settledHeight, err := node.Store.LoadSettledHeight()
+	require.NoError(t, err)
+	assert.Zero(t, settledHeight)
+	stopFaultyNode(t, node)
+}
+
+func TestFaultySettlementDropsAllEvents(t *testing.T) {
+	node := startFaultyNode(t, faults.Config{}, faults.Config{DropRate: 1})
+
+	// the node learns about the accepted batches by polling the settlement layer
+	require.Eventually(t, func() bool {
+		settledHeight, err := node.Store.LoadSettledHeight()
+		return err == nil && settledHeight >= 2*defaultFaultyBatchSize
+	}, 10*time.Second, 50*time.Millisecond)
+
+	res, err := node.settlementlc.RetrieveBatch()
+	require.NoError(t, err)
+	settledHeight, err := node.Store.LoadSettledHeight()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, settledHeight, res.EndHeight)
+	stopFaultyNode(t, node)
+}
+
+const defaultFaultyBatchSize = 5
+
+func startFaultyNode(t *testing.T, daFaults faults.Config, slFaults faults.Config) *Node {
+	t.Helper()
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("InitChain", mock.Anything).Return(abci.ResponseInitChain{})
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	app.On("BeginBlock", mock.Anything).Return(abci.ResponseBeginBlock{})
+	app.On("DeliverTx", mock.Anything).Return(abci.ResponseDeliverTx{})
+	app.On("EndBlock", mock.Anything).Return(abci.ResponseEndBlock{})
+	app.On("Commit", mock.Anything).Return(abci.ResponseCommit{})
+	app.On("Info", mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 0, LastBlockAppHash: []byte{0}})
+
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	signingKey, proposerPubKey, _ := crypto.GenerateEd25519Key(rand.Reader)
+	pubKeyBytes, err := proposerPubKey.Raw()
+	require.NoError(err)
+
+	slInnerConfig, err := json.Marshal(slmock.Config{Config: &settlement.Config{BatchSize: defaultFaultyBatchSize}, ProposerPubKey: pubKeyBytes})
+	require.NoError(err)
+	slConfig, err := json.Marshal(slfaulty.Config{Config: slFaults, InnerConfig: string(slInnerConfig)})
+	require.NoError(err)
+	daConfig, err := json.Marshal(dafaulty.Config{Config: daFaults, InnerConfig: (100 * time.Millisecond).String()})
+	require.NoError(err)
+
+	nodeConfig := config.NodeConfig{
+		Aggregator:       true,
+		DALayer:          "faulty-mock",
+		DAConfig:         string(daConfig),
+		SettlementLayer:  "faulty-mock",
+		SettlementConfig: string(slConfig),
+		BlockManagerConfig: config.BlockManagerConfig{
+			BlockTime:          50 * time.Millisecond,
+			DABlockTime:        100 * time.Millisecond,
+			BatchSyncInterval:  time.Second,
+			BlockBatchSize:     defaultFaultyBatchSize,
+			MaxBatchesInFlight: 2,
+			NamespaceID:        [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+	}
+	node, err := NewNode(context.Background(), nodeConfig, key, signingKey, proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node.Start())
+	return node
+}
+
+// stopFaultyNode checks that the node doesn't hang on stop, whatever the layers are doing.
+func stopFaultyNode(t *testing.T, node *Node) {
+	t.Helper()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- node.Stop()
+	}()
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("node didn't stop")
+	}
+}
@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	mrand "math/rand"
 	"testing"
 	"time"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/dymensionxyz/dymint/config"
 	"github.com/dymensionxyz/dymint/mocks"
+	slmock "github.com/dymensionxyz/dymint/settlement/mock"
 )
 
 func TestAggregatorMode(t *testing.T) {
@@ -37,15 +39,19 @@ func TestAggregatorMode(t *testing.T) {
 	app.On("Info", mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 0, LastBlockAppHash: []byte{0}})
 
 	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
-	signingKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	signingKey, proposerPubKey, _ := crypto.GenerateEd25519Key(rand.Reader)
 	anotherKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	proposerPubKeyBytes, err := proposerPubKey.Raw()
+	require.NoError(err)
+	settlementLayerConfig, err := json.Marshal(slmock.Config{ProposerPubKey: proposerPubKeyBytes})
+	require.NoError(err)
 
 	blockManagerConfig := config.BlockManagerConfig{
 		BlockTime:         1 * time.Second,
 		NamespaceID:       [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
 		BatchSyncInterval: time.Second * 5,
 	}
-	nodeConfig := config.NodeConfig{DALayer: "mock", SettlementLayer: "mock", Aggregator: true, BlockManagerConfig: blockManagerConfig}
+	nodeConfig := config.NodeConfig{DALayer: "mock", SettlementLayer: "mock", SettlementConfig: string(settlementLayerConfig), Aggregator: true, BlockManagerConfig: blockManagerConfig}
 	node, err := NewNode(context.Background(), nodeConfig, key, signingKey, proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
 	require.NoError(err)
 	require.NotNil(node)
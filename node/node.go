@@ -20,6 +20,7 @@ import (
 	"github.com/dymensionxyz/dymint/config"
 	"github.com/dymensionxyz/dymint/da"
 	daregsitry "github.com/dymensionxyz/dymint/da/registry"
+	"github.com/dymensionxyz/dymint/fraudproof"
 	"github.com/dymensionxyz/dymint/mempool"
 	mempoolv1 "github.com/dymensionxyz/dymint/mempool/v1"
 	nodemempool "github.com/dymensionxyz/dymint/node/mempool"
@@ -134,7 +135,9 @@ func NewNode(ctx context.Context, conf config.NodeConfig, p2pKey crypto.PrivKey,
 		return nil, err
 	}
 
-	mp := mempoolv1.NewTxMempool(logger, llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
+	metrics := newNodeMetrics(conf.Instrumentation, genesis.ChainID)
+
+	mp := mempoolv1.NewTxMempool(logger, llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0, mempoolv1.WithMetrics(metrics.mempool))
 	mpIDs := nodemempool.NewMempoolIDs()
 
 	// Set p2p client and it's validators
@@ -145,11 +148,14 @@ func NewNode(ctx context.Context, conf config.NodeConfig, p2pKey crypto.PrivKey,
 	}
 	p2pClient.SetTxValidator(p2pValidator.TxValidator(mp, mpIDs))
 	p2pClient.SetBlockValidator(p2pValidator.BlockValidator())
+	p2pClient.SetFraudProofValidator(p2pValidator.FraudProofValidator(fraudproof.NewVerifier(proxyApp.Query(), settlementlc)))
+	p2pClient.SetBlockStore(s)
 
 	blockManager, err := block.NewManager(signingKey, conf.BlockManagerConfig, genesis, s, mp, proxyApp, dalc, settlementlc, eventBus, pubsubServer, p2pClient, logger.With("module", "BlockManager"))
 	if err != nil {
 		return nil, fmt.Errorf("BlockManager initialization error: %w", err)
 	}
+	blockManager.SetMetrics(metrics.block, metrics.da)
 
 	node := &Node{
 		proxyApp:       proxyApp,
@@ -227,8 +233,16 @@ func (n *Node) OnStart() error {
 	if err != nil {
 		return fmt.Errorf("error while starting settlement layer client: %w", err)
 	}
+	// Only aggregators produce blocks. In read-only mode, an aggregator which isn't the proposer runs as a full node
+	// and never produces, even if it becomes the proposer later on.
 	if n.conf.Aggregator {
-		go n.blockManager.ProduceBlockLoop(n.ctx)
+		if err := n.blockManager.CheckProposer(); err == nil {
+			go n.blockManager.ProposerLoop(n.ctx)
+		} else if !n.conf.ReadOnly {
+			return fmt.Errorf("refusing to produce blocks in aggregator mode: %w", err)
+		} else {
+			n.Logger.Error("Not the proposer, running as a full node in read-only mode", "error", err)
+		}
 	}
 	go n.blockManager.RetriveLoop(n.ctx)
 	go n.blockManager.ApplyBlockLoop(n.ctx)
@@ -284,6 +298,31 @@ func (n *Node) ProxyApp() proxy.AppConns {
 	return n.proxyApp
 }
 
+// Config returns the node configuration.
+func (n *Node) Config() config.NodeConfig {
+	return n.conf
+}
+
+// nodeMetrics holds the metrics of the node components.
+type nodeMetrics struct {
+	block   *block.Metrics
+	da      *da.Metrics
+	mempool *mempool.Metrics
+}
+
+// newNodeMetrics returns Prometheus metrics in case they are enabled, and no-op metrics otherwise.
+// Prometheus metrics are registered globally, so they can be enabled for a single node per process.
+func newNodeMetrics(conf config.InstrumentationConfig, chainID string) *nodeMetrics {
+	if !conf.Prometheus {
+		return &nodeMetrics{block: block.NopMetrics(), da: da.NopMetrics(), mempool: mempool.NopMetrics()}
+	}
+	return &nodeMetrics{
+		block:   block.PrometheusMetrics(conf.Namespace, "chain_id", chainID),
+		da:      da.PrometheusMetrics(conf.Namespace, "chain_id", chainID),
+		mempool: mempool.PrometheusMetrics(conf.Namespace, "chain_id", chainID),
+	}
+}
+
 func createAndStartIndexerService(
 	conf config.NodeConfig,
 	kvStore store.KVStore,
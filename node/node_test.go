@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -18,8 +19,11 @@ import (
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 
+	"github.com/dymensionxyz/dymint/block"
 	"github.com/dymensionxyz/dymint/config"
 	"github.com/dymensionxyz/dymint/mocks"
+	"github.com/dymensionxyz/dymint/settlement"
+	dymtypes "github.com/dymensionxyz/dymint/types"
 )
 
 // simply check that node is starting and stopping without panicking
@@ -48,6 +52,55 @@ func TestStartup(t *testing.T) {
 	assert.True(node.IsRunning())
 }
 
+// TestProposerCheck checks that an aggregator which isn't the proposer on the SL refuses to start,
+// unless it runs in read-only mode, in which case it runs as a full node.
+func TestProposerCheck(t *testing.T) {
+	cases := []struct {
+		name     string
+		readOnly bool
+	}{
+		{"aggregator", false},
+		{"read-only", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			app := &mocks.Application{}
+			app.On("InitChain", mock.Anything).Return(abci.ResponseInitChain{})
+			key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			signingKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			// The mock SL is configured without the proposer key, so the signing key isn't the proposer's
+			nodeConfig := config.NodeConfig{Aggregator: true, ReadOnly: c.readOnly, DALayer: "mock", SettlementLayer: "mock", BlockManagerConfig: config.BlockManagerConfig{BatchSyncInterval: time.Second * 5, BlockTime: 100 * time.Millisecond}}
+			node, err := NewNode(context.Background(), nodeConfig, key, signingKey, proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
+			require.NoError(err)
+
+			err = node.Start()
+			if !c.readOnly {
+				assert.ErrorIs(err, block.ErrNotProposer)
+				return
+			}
+			require.NoError(err)
+			defer func() {
+				assert.NoError(node.Stop())
+			}()
+			time.Sleep(500 * time.Millisecond)
+			assert.Equal(uint64(0), node.Store.Height())
+
+			// The read-only node doesn't produce blocks once it becomes the proposer either
+			rawKey, err := signingKey.GetPublic().Raw()
+			require.NoError(err)
+			err = node.pubsubServer.PublishWithEvents(context.Background(), &settlement.EventDataSequencersListUpdated{
+				Sequencers: []dymtypes.Sequencer{{PublicKey: &ed25519.PubKey{Key: rawKey}, Status: dymtypes.Proposer}},
+			}, map[string][]string{settlement.EventTypeKey: {settlement.EventSequencersListUpdated}})
+			require.NoError(err)
+			time.Sleep(500 * time.Millisecond)
+			assert.Equal(uint64(0), node.Store.Height())
+		})
+	}
+}
+
 func TestMempoolDirectly(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -0,0 +1,158 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	protoio "github.com/gogo/protobuf/io"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+
+	"github.com/dymensionxyz/dymint/types"
+	pb "github.com/dymensionxyz/dymint/types/pb/dymint"
+)
+
+const (
+	// blockSyncProtocolSuffix is added after namespace to create the protocol ID for block sync requests.
+	blockSyncProtocolSuffix = "/blocksync/1.0.0"
+
+	// maxBlocksPerRequest is the max number of blocks served for a single block sync request.
+	maxBlocksPerRequest = 100
+
+	// maxBlockSyncMsgSize is the max size of a block sync message.
+	maxBlockSyncMsgSize = 100 * 1024 * 1024
+
+	// blockSyncTimeout is the max time a block sync request can take.
+	blockSyncTimeout = 30 * time.Second
+)
+
+// BlockStore is the source of the blocks served to peers by the block sync protocol.
+type BlockStore interface {
+	Height() uint64
+	LoadBlock(height uint64) (*types.Block, error)
+	LoadCommit(height uint64) (*types.Commit, error)
+}
+
+// SetBlockStore sets the store used to serve block sync requests of peers.
+func (c *Client) SetBlockStore(store BlockStore) {
+	c.blockStore = store
+}
+
+// GetBlocks requests the blocks in the range [from, to] from the connected peers.
+// Peers are asked one by one until one of them returns blocks. The returned batch might
+// contain less blocks than requested, in case the peer doesn't have all of them or the range is too big.
+// The blocks are not validated.
+func (c *Client) GetBlocks(ctx context.Context, from uint64, to uint64) (*types.Batch, error) {
+	if from == 0 || to < from {
+		return nil, fmt.Errorf("invalid block range [%d, %d]", from, to)
+	}
+	var err error
+	for _, p := range c.host.Network().Peers() {
+		var batch *types.Batch
+		batch, err = c.getBlocksFromPeer(ctx, p, from, to)
+		if err != nil {
+			c.logger.Debug("failed to get blocks from peer", "peer", p, "error", err)
+			continue
+		}
+		if len(batch.Blocks) > 0 {
+			return batch, nil
+		}
+	}
+	if err == nil {
+		err = errNoBlocksFromPeers
+	}
+	return nil, err
+}
+
+func (c *Client) getBlocksFromPeer(ctx context.Context, p peer.ID, from uint64, to uint64) (*types.Batch, error) {
+	ctx, cancel := context.WithTimeout(ctx, blockSyncTimeout)
+	defer cancel()
+	stream, err := c.host.NewStream(ctx, p, c.getBlockSyncProtocol())
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+
+	request := types.Batch{StartHeight: from, EndHeight: to}
+	if err := protoio.NewDelimitedWriter(stream).WriteMsg(request.ToProto()); err != nil {
+		_ = stream.Reset()
+		return nil, err
+	}
+	if err := stream.CloseWrite(); err != nil {
+		_ = stream.Reset()
+		return nil, err
+	}
+
+	var pbBatch pb.Batch
+	if err := protoio.NewDelimitedReader(stream, maxBlockSyncMsgSize).ReadMsg(&pbBatch); err != nil {
+		_ = stream.Reset()
+		return nil, err
+	}
+	var batch types.Batch
+	if err := batch.FromProto(&pbBatch); err != nil {
+		return nil, err
+	}
+	if len(batch.Blocks) != len(batch.Commits) {
+		return nil, errors.New("number of blocks and commits mismatch")
+	}
+	for i, block := range batch.Blocks {
+		if block.Header.Height != from+uint64(i) {
+			return nil, fmt.Errorf("unexpected block height %d", block.Header.Height)
+		}
+	}
+	return &batch, nil
+}
+
+// handleBlockSyncStream serves a block sync request of a peer from the block store.
+func (c *Client) handleBlockSyncStream(stream network.Stream) {
+	defer stream.Close()
+	_ = stream.SetDeadline(time.Now().Add(blockSyncTimeout))
+
+	var pbRequest pb.Batch
+	if err := protoio.NewDelimitedReader(stream, maxBlockSyncMsgSize).ReadMsg(&pbRequest); err != nil {
+		c.logger.Debug("failed to read block sync request", "peer", stream.Conn().RemotePeer(), "error", err)
+		_ = stream.Reset()
+		return
+	}
+	from, to := pbRequest.StartHeight, pbRequest.EndHeight
+	c.logger.Debug("received block sync request", "peer", stream.Conn().RemotePeer(), "from", from, "to", to)
+
+	response := types.Batch{StartHeight: from}
+	if c.blockStore != nil && from > 0 && to >= from {
+		if to >= from+maxBlocksPerRequest {
+			to = from + maxBlocksPerRequest - 1
+		}
+		if storeHeight := c.blockStore.Height(); to > storeHeight {
+			to = storeHeight
+		}
+		for height := from; height <= to; height++ {
+			block, err := c.blockStore.LoadBlock(height)
+			if err != nil {
+				break
+			}
+			commit, err := c.blockStore.LoadCommit(height)
+			if err != nil {
+				break
+			}
+			response.Blocks = append(response.Blocks, block)
+			response.Commits = append(response.Commits, commit)
+		}
+	}
+	if len(response.Blocks) > 0 {
+		response.EndHeight = from + uint64(len(response.Blocks)) - 1
+	}
+	if err := protoio.NewDelimitedWriter(stream).WriteMsg(response.ToProto()); err != nil {
+		c.logger.Debug("failed to write block sync response", "peer", stream.Conn().RemotePeer(), "error", err)
+		_ = stream.Reset()
+	}
+}
+
+func (c *Client) getBlockSyncProtocol() protocol.ID {
+	return protocol.ID("/" + c.getNamespace() + blockSyncProtocolSuffix)
+}
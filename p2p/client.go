@@ -41,6 +41,9 @@ const (
 
 	// blockTopicSuffix is added after namespace to create pubsub topic for block gossiping.
 	blockTopicSuffix = "-block"
+
+	// fraudProofTopicSuffix is added after namespace to create pubsub topic for fraud proof gossiping.
+	fraudProofTopicSuffix = "-fraudproof"
 )
 
 // Client is a P2P client, implemented with libp2p.
@@ -66,6 +69,12 @@ type Client struct {
 	blockGossiper  *Gossiper
 	blockValidator GossipValidator
 
+	fraudProofGossiper  *Gossiper
+	fraudProofValidator GossipValidator
+
+	// blockStore serves the block sync requests of peers
+	blockStore BlockStore
+
 	// cancel is used to cancel context passed to libp2p functions
 	// it's required because of discovery.Advertise call
 	cancel context.CancelFunc
@@ -122,6 +131,9 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 		return err
 	}
 
+	c.logger.Debug("setting up block sync")
+	c.host.SetStreamHandler(c.getBlockSyncProtocol(), c.handleBlockSyncStream)
+
 	c.logger.Debug("setting up DHT")
 	err = c.setupDHT(ctx)
 	if err != nil {
@@ -145,6 +157,7 @@ func (c *Client) Close() error {
 		c.txGossiper.Close(),
 		c.headerGossiper.Close(),
 		c.blockGossiper.Close(),
+		c.fraudProofGossiper.Close(),
 		c.dht.Close(),
 		c.host.Close(),
 	)
@@ -183,6 +196,17 @@ func (c *Client) SetBlockValidator(validator GossipValidator) {
 	c.blockValidator = validator
 }
 
+// GossipFraudProof sends the fraud proof to the P2P network.
+func (c *Client) GossipFraudProof(ctx context.Context, fraudProofBytes []byte) error {
+	c.logger.Debug("Gossiping fraud proof", "len", len(fraudProofBytes))
+	return c.fraudProofGossiper.Publish(ctx, fraudProofBytes)
+}
+
+// SetFraudProofValidator sets the callback function, that will be invoked after fraud proof is received from P2P network.
+func (c *Client) SetFraudProofValidator(validator GossipValidator) {
+	c.fraudProofValidator = validator
+}
+
 // Addrs returns listen addresses of Client.
 func (c *Client) Addrs() []multiaddr.Multiaddr {
 	return c.host.Addrs()
@@ -347,6 +371,13 @@ func (c *Client) setupGossiping(ctx context.Context) error {
 	}
 	go c.blockGossiper.ProcessMessages(ctx)
 
+	c.fraudProofGossiper, err = NewGossiper(c.host, ps, c.getFraudProofTopic(), c.logger,
+		WithValidator(c.fraudProofValidator))
+	if err != nil {
+		return err
+	}
+	go c.fraudProofGossiper.ProcessMessages(ctx)
+
 	return nil
 }
 
@@ -392,6 +423,10 @@ func (c *Client) getBlockTopic() string {
 	return c.getNamespace() + blockTopicSuffix
 }
 
+func (c *Client) getFraudProofTopic() string {
+	return c.getNamespace() + fraudProofTopicSuffix
+}
+
 // NewTxValidator creates a pubsub validator that uses the node's mempool to check the
 // transaction. If the transaction is valid, then it is added to the mempool
 func (c *Client) NewTxValidator() GossipValidator {
@@ -16,6 +16,8 @@ import (
 
 	"github.com/dymensionxyz/dymint/config"
 	"github.com/dymensionxyz/dymint/log/test"
+	"github.com/dymensionxyz/dymint/store"
+	"github.com/dymensionxyz/dymint/testutil"
 )
 
 func TestClientStartup(t *testing.T) {
@@ -127,6 +129,52 @@ func TestGossiping(t *testing.T) {
 	wg.Wait()
 }
 
+func TestBlockSync(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := test.NewLogger(t)
+
+	ctx := context.Background()
+
+	validators := []GossipValidator{nil, nil}
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: {conns: []int{}, chainID: "1"},
+		1: {conns: []int{0}, chainID: "1"},
+	}, validators, logger)
+	clients.WaitForDHT()
+
+	// client 0 serves blocks 1-5 from its store
+	proposerKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, 5, proposerKey)
+	require.NoError(err)
+	blockStore := store.New(store.NewDefaultInMemoryKVStore())
+	for i, block := range batch.Blocks {
+		_, err := blockStore.SaveBlock(block, batch.Commits[i], nil)
+		require.NoError(err)
+		blockStore.SetHeight(block.Header.Height)
+	}
+	clients[0].SetBlockStore(blockStore)
+
+	// blocks beyond the store height are not returned
+	received, err := clients[1].GetBlocks(ctx, 2, 10)
+	require.NoError(err)
+	assert.Equal(uint64(2), received.StartHeight)
+	assert.Equal(uint64(5), received.EndHeight)
+	require.Len(received.Blocks, 4)
+	require.Len(received.Commits, 4)
+	for i, block := range received.Blocks {
+		assert.Equal(batch.Blocks[i+1].Header.Hash(), block.Header.Hash())
+		assert.Equal(batch.Commits[i+1], received.Commits[i])
+	}
+
+	_, err = clients[1].GetBlocks(ctx, 6, 10)
+	assert.ErrorIs(err, errNoBlocksFromPeers)
+
+	_, err = clients[1].GetBlocks(ctx, 3, 2)
+	assert.Error(err)
+}
+
 func TestSeedStringParsing(t *testing.T) {
 	t.Parallel()
 
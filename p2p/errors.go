@@ -3,5 +3,6 @@ package p2p
 import "errors"
 
 var (
-	errNoPrivKey = errors.New("private key not provided")
+	errNoPrivKey         = errors.New("private key not provided")
+	errNoBlocksFromPeers = errors.New("no blocks received from peers")
 )
@@ -21,6 +21,7 @@ const (
 // Define the event types
 const (
 	EventNewGossipedBlock = "NewGossipedBlock"
+	EventNewFraudProof    = "NewFraudProof"
 )
 
 /* -------------------------------------------------------------------------- */
@@ -88,6 +89,8 @@ func (e *GossipedBlock) Validate() error {
 var (
 	// EventQueryNewNewGossipedBlock is the query used for getting EventNewGossipedBlock
 	EventQueryNewNewGossipedBlock = QueryForEvent(EventNewGossipedBlock)
+	// EventQueryNewFraudProof is the query used for getting EventNewFraudProof
+	EventQueryNewFraudProof = QueryForEvent(EventNewFraudProof)
 )
 
 // QueryForEvent returns a query for the given event.
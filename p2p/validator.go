@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/dymensionxyz/dymint/fraudproof"
 	"github.com/dymensionxyz/dymint/log"
 	"github.com/dymensionxyz/dymint/mempool"
 	nodemempool "github.com/dymensionxyz/dymint/node/mempool"
@@ -89,3 +90,27 @@ func (v *Validator) BlockValidator() GossipValidator {
 		return true
 	}
 }
+
+// FraudProofValidator verifies the gossiped fraud proof. Only proofs which prove a fraud of the sequencer are
+// published locally and propagated further.
+func (v *Validator) FraudProofValidator(verifier *fraudproof.Verifier) GossipValidator {
+	return func(fraudProofMsg *GossipMessage) bool {
+		v.logger.Debug("fraud proof event received", "from", fraudProofMsg.From, "bytes", len(fraudProofMsg.Data))
+		var proof fraudproof.FraudProof
+		if err := proof.UnmarshalBinary(fraudProofMsg.Data); err != nil {
+			v.logger.Error("failed to deserialize gossiped fraud proof", "error", err)
+			return false
+		}
+		if err := verifier.Verify(&proof); err != nil {
+			v.logger.Error("Invalid gossiped fraud proof", "error", err)
+			return false
+		}
+		v.logger.Error("Fraud proof received", "height", proof.Block.Header.Height, "step", proof.StepIndex, "from", fraudProofMsg.From)
+		err := v.localPubsubServer.PublishWithEvents(context.Background(), proof, map[string][]string{EventTypeKey: {EventNewFraudProof}})
+		if err != nil {
+			v.logger.Error("Error publishing event", "err", err)
+			return false
+		}
+		return true
+	}
+}
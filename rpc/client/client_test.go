@@ -263,6 +263,39 @@ func TestGetBlock(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestGetBlockWithFinality(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, rpc := getRPC(t)
+
+	for h := uint64(1); h <= 2; h++ {
+		block := getRandomBlock(h, 5)
+		_, err := rpc.node.Store.SaveBlock(block, &types.Commit{Height: h}, nil)
+		require.NoError(err)
+		rpc.node.Store.SetHeight(h)
+	}
+	_, err := rpc.node.Store.SaveSettledHeight(1, nil)
+	require.NoError(err)
+
+	height := int64(1)
+	blockResp, err := rpc.BlockWithFinality(context.Background(), &height)
+	require.NoError(err)
+	assert.Equal(height, blockResp.Block.Height)
+	assert.Equal(types.FinalitySettled, blockResp.Finality)
+
+	blockResp, err = rpc.BlockWithFinality(context.Background(), nil)
+	require.NoError(err)
+	assert.Equal(int64(2), blockResp.Block.Height)
+	assert.Equal(types.FinalitySoftConfirmed, blockResp.Finality)
+
+	status, err := rpc.StatusWithFinality(context.Background())
+	require.NoError(err)
+	assert.Equal(int64(2), status.SyncInfo.LatestBlockHeight)
+	assert.Equal(int64(1), status.FinalityInfo.LatestSettledHeight)
+	assert.Equal(types.FinalitySoftConfirmed, status.FinalityInfo.LatestBlockFinality)
+}
+
 func TestGetCommit(t *testing.T) {
 	require := require.New(t)
 	assert := assert.New(t)
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/p2p"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/dymensionxyz/dymint/types"
+)
+
+// FinalityInfo describes the finality of the blocks in store.
+type FinalityInfo struct {
+	// LatestSettledHeight is the height of the last block accepted by the SL.
+	LatestSettledHeight int64 `json:"latest_settled_height"`
+	// LatestBlockFinality is the finality level of the latest block in store.
+	LatestBlockFinality types.Finality `json:"latest_block_finality"`
+}
+
+// ResultStatus is ctypes.ResultStatus extended with the finality info of the node.
+type ResultStatus struct {
+	NodeInfo      p2p.DefaultNodeInfo  `json:"node_info"`
+	SyncInfo      ctypes.SyncInfo      `json:"sync_info"`
+	ValidatorInfo ctypes.ValidatorInfo `json:"validator_info"`
+	FinalityInfo  FinalityInfo         `json:"finality_info"`
+}
+
+// ResultBlock is ctypes.ResultBlock extended with the finality level of the block.
+type ResultBlock struct {
+	BlockID  tmtypes.BlockID `json:"block_id"`
+	Block    *tmtypes.Block  `json:"block"`
+	Finality types.Finality  `json:"finality"`
+}
+
+// ResultTx is ctypes.ResultTx extended with the finality level of the block containing the transaction.
+type ResultTx struct {
+	Hash     tmbytes.HexBytes       `json:"hash"`
+	Height   int64                  `json:"height"`
+	Index    uint32                 `json:"index"`
+	TxResult abci.ResponseDeliverTx `json:"tx_result"`
+	Tx       tmtypes.Tx             `json:"tx"`
+	Proof    tmtypes.TxProof        `json:"proof,omitempty"`
+	Finality types.Finality         `json:"finality"`
+}
+
+// StatusWithFinality returns the status of the node along with the finality info.
+func (c *Client) StatusWithFinality(ctx context.Context) (*ResultStatus, error) {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settledHeight, err := c.node.Store.LoadSettledHeight()
+	if err != nil {
+		return nil, err
+	}
+	return &ResultStatus{
+		NodeInfo:      status.NodeInfo,
+		SyncInfo:      status.SyncInfo,
+		ValidatorInfo: status.ValidatorInfo,
+		FinalityInfo: FinalityInfo{
+			LatestSettledHeight: int64(settledHeight),
+			LatestBlockFinality: types.GetFinality(uint64(status.SyncInfo.LatestBlockHeight), settledHeight),
+		},
+	}, nil
+}
+
+// BlockWithFinality returns the block at the given height along with its finality level.
+// If height is nil, the latest block is returned.
+func (c *Client) BlockWithFinality(ctx context.Context, height *int64) (*ResultBlock, error) {
+	block, err := c.Block(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	finality, err := c.finality(uint64(block.Block.Height))
+	if err != nil {
+		return nil, err
+	}
+	return &ResultBlock{
+		BlockID:  block.BlockID,
+		Block:    block.Block,
+		Finality: finality,
+	}, nil
+}
+
+// TxWithFinality returns the transaction with the given hash along with the finality level of its block.
+func (c *Client) TxWithFinality(ctx context.Context, hash []byte, prove bool) (*ResultTx, error) {
+	tx, err := c.Tx(ctx, hash, prove)
+	if err != nil {
+		return nil, err
+	}
+	finality, err := c.finality(uint64(tx.Height))
+	if err != nil {
+		return nil, err
+	}
+	return &ResultTx{
+		Hash:     tx.Hash,
+		Height:   tx.Height,
+		Index:    tx.Index,
+		TxResult: tx.TxResult,
+		Tx:       tx.Tx,
+		Proof:    tx.Proof,
+		Finality: finality,
+	}, nil
+}
+
+func (c *Client) finality(height uint64) (types.Finality, error) {
+	settledHeight, err := c.node.Store.LoadSettledHeight()
+	if err != nil {
+		return "", err
+	}
+	return types.GetFinality(height, settledHeight), nil
+}
@@ -162,8 +162,8 @@ func (s *service) Health(req *http.Request, args *healthArgs) (*ctypes.ResultHea
 	return s.client.Health(req.Context())
 }
 
-func (s *service) Status(req *http.Request, args *statusArgs) (*ctypes.ResultStatus, error) {
-	return s.client.Status(req.Context())
+func (s *service) Status(req *http.Request, args *statusArgs) (*client.ResultStatus, error) {
+	return s.client.StatusWithFinality(req.Context())
 }
 
 func (s *service) NetInfo(req *http.Request, args *netInfoArgs) (*ctypes.ResultNetInfo, error) {
@@ -182,8 +182,8 @@ func (s *service) GenesisChunked(req *http.Request, args *genesisChunkedArgs) (*
 	return s.client.GenesisChunked(req.Context(), uint(args.ID))
 }
 
-func (s *service) Block(req *http.Request, args *blockArgs) (*ctypes.ResultBlock, error) {
-	return s.client.Block(req.Context(), (*int64)(&args.Height))
+func (s *service) Block(req *http.Request, args *blockArgs) (*client.ResultBlock, error) {
+	return s.client.BlockWithFinality(req.Context(), (*int64)(&args.Height))
 }
 
 func (s *service) BlockByHash(req *http.Request, args *blockByHashArgs) (*ctypes.ResultBlock, error) {
@@ -202,8 +202,8 @@ func (s *service) CheckTx(req *http.Request, args *checkTxArgs) (*ctypes.ResultC
 	return s.client.CheckTx(req.Context(), args.Tx)
 }
 
-func (s *service) Tx(req *http.Request, args *txArgs) (*ctypes.ResultTx, error) {
-	return s.client.Tx(req.Context(), args.Hash, args.Prove)
+func (s *service) Tx(req *http.Request, args *txArgs) (*client.ResultTx, error) {
+	return s.client.TxWithFinality(req.Context(), args.Hash, args.Prove)
 }
 
 func (s *service) TxSearch(req *http.Request, args *txSearchArgs) (*ctypes.ResultTxSearch, error) {
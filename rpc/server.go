@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/log"
@@ -24,8 +25,9 @@ import (
 type Server struct {
 	*service.BaseService
 
-	config *config.RPCConfig
-	client *client.Client
+	config  *config.RPCConfig
+	client  *client.Client
+	metrics bool
 
 	server http.Server
 }
@@ -33,8 +35,9 @@ type Server struct {
 // NewServer creates new instance of Server with given configuration.
 func NewServer(node *node.Node, config *config.RPCConfig, logger log.Logger) *Server {
 	srv := &Server{
-		config: config,
-		client: client.NewClient(node),
+		config:  config,
+		client:  client.NewClient(node),
+		metrics: node.Config().Instrumentation.Prometheus,
 	}
 	srv.BaseService = service.NewBaseService(logger, "RPC", srv)
 	return srv
@@ -87,6 +90,12 @@ func (s *Server) startRPC() error {
 	if err != nil {
 		return err
 	}
+	if s.metrics {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.Handle("/", handler)
+		handler = mux
+	}
 
 	if s.config.IsCorsEnabled() {
 		s.Logger.Debug("CORS enabled",
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 	"sync/atomic"
 
 	"github.com/dymensionxyz/dymint/da"
@@ -14,6 +15,8 @@ import (
 
 const (
 	defaultBatchSize = 5
+	// sequencersSubscriber is the subscriber name of the sequencers list updates
+	sequencersSubscriber = "settlementSequencersList"
 )
 
 // BaseLayerClient is intended only for usage in tests.
@@ -22,6 +25,7 @@ type BaseLayerClient struct {
 	pubsub         *pubsub.Server
 	latestHeight   uint64
 	sequencersList []*types.Sequencer
+	sequencersMu   sync.RWMutex
 	config         Config
 	ctx            context.Context
 	cancel         context.CancelFunc
@@ -77,14 +81,23 @@ func (b *BaseLayerClient) Start() error {
 	}
 	b.latestHeight = endHeight
 	b.logger.Info("Updated latest height from settlement layer", "latestHeight", b.latestHeight)
-	b.sequencersList, err = b.fetchSequencersList()
+	sequencersList, err := b.fetchSequencersList()
 	if err != nil {
 		if err == ErrNoSequencerForRollapp {
 			panic(err)
 		}
 		return err
 	}
-	b.logger.Info("Updated sequencers list from settlement layer", "sequencersList", b.sequencersList)
+	b.setSequencersList(sequencersList)
+	b.logger.Info("Updated sequencers list from settlement layer", "sequencersList", sequencersList)
+
+	// The list is kept up to date by the updates published by the hub client, so GetProposer follows the
+	// rotations of the proposer.
+	subscription, err := b.pubsub.Subscribe(b.ctx, sequencersSubscriber, EventQuerySequencersListUpdated, 100)
+	if err != nil {
+		return err
+	}
+	go b.sequencersListUpdateLoop(subscription)
 
 	err = b.client.Start()
 	if err != nil {
@@ -151,12 +164,14 @@ func (b *BaseLayerClient) RetrieveBatch(stateIndex ...uint64) (*ResultRetrieveBa
 
 // GetSequencersList returns the current list of sequencers from the settlement layer
 func (b *BaseLayerClient) GetSequencersList() []*types.Sequencer {
+	b.sequencersMu.RLock()
+	defer b.sequencersMu.RUnlock()
 	return b.sequencersList
 }
 
 // GetProposer returns the sequencer which is currently the proposer
 func (b *BaseLayerClient) GetProposer() *types.Sequencer {
-	for _, sequencer := range b.sequencersList {
+	for _, sequencer := range b.GetSequencersList() {
 		if sequencer.Status == types.Proposer {
 			return sequencer
 		}
@@ -164,6 +179,33 @@ func (b *BaseLayerClient) GetProposer() *types.Sequencer {
 	return nil
 }
 
+func (b *BaseLayerClient) setSequencersList(sequencersList []*types.Sequencer) {
+	b.sequencersMu.Lock()
+	defer b.sequencersMu.Unlock()
+	b.sequencersList = sequencersList
+}
+
+// sequencersListUpdateLoop replaces the sequencers list with the updated lists published by the hub client.
+func (b *BaseLayerClient) sequencersListUpdateLoop(subscription *pubsub.Subscription) {
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-subscription.Cancelled():
+			return
+		case event := <-subscription.Out():
+			eventData := event.Data().(*EventDataSequencersListUpdated)
+			sequencersList := make([]*types.Sequencer, len(eventData.Sequencers))
+			for i := range eventData.Sequencers {
+				sequencer := eventData.Sequencers[i]
+				sequencersList[i] = &sequencer
+			}
+			b.setSequencersList(sequencersList)
+			b.logger.Info("Updated sequencers list from settlement layer", "sequencersList", sequencersList)
+		}
+	}
+}
+
 func (b *BaseLayerClient) fetchSequencersList() ([]*types.Sequencer, error) {
 	sequencers, err := b.client.GetSequencers(b.config.RollappID)
 	if err != nil {
@@ -28,16 +28,27 @@ type CosmosClient interface {
 
 type cosmosClient struct {
 	cosmosclient.Client
+	eventListenerStarted bool
 }
 
 var _ CosmosClient = &cosmosClient{}
 
 // NewCosmosClient creates a new cosmos client
 func NewCosmosClient(client cosmosclient.Client) CosmosClient {
-	return &cosmosClient{client}
+	return &cosmosClient{Client: client}
 }
 
+// StartEventListener starts the websocket client of the events. A stopped websocket client can't be started again,
+// so it is replaced by a new one in case the event listener was already started, e.g. when reconnecting.
 func (c *cosmosClient) StartEventListener() error {
+	if c.eventListenerStarted {
+		rpc, err := cosmosclient.NewHttp(c.Client.RPC.Remote(), "/websocket")
+		if err != nil {
+			return err
+		}
+		c.Client.RPC.WSEvents = rpc.WSEvents
+	}
+	c.eventListenerStarted = true
 	return c.Client.RPC.WSEvents.Start()
 }
 
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
@@ -33,6 +36,14 @@ const (
 const (
 	eventStateUpdate          = "state_update.rollapp_id='%s'"
 	eventSequencersListUpdate = "sequencers_list_update.rollapp_id='%s'"
+	eventsSubscriber          = "dymension-client"
+)
+
+const (
+	// initialReconnectBackoff is the delay before retrying to reconnect to the settlement layer websocket
+	initialReconnectBackoff = time.Second
+	// maxReconnectBackoff is the max delay between the reconnection attempts
+	maxReconnectBackoff = time.Minute
 )
 
 // LayerClient is intended only for usage in tests.
@@ -100,6 +111,8 @@ type HubClient struct {
 	sequencerQueryClient sequencertypes.QueryClient
 	protoCodec           *codec.ProtoCodec
 	eventMap             map[string]string
+	// latestStateIndex is the index of the latest state update published
+	latestStateIndex uint64
 }
 
 var _ settlement.HubClient = &HubClient{}
@@ -189,19 +202,20 @@ func (d *HubClient) Start() error {
 	if err != nil {
 		return err
 	}
-	go d.eventHandler()
+	subscriptions, err := d.subscribeToEvents()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to settlement layer events: %w", err)
+	}
+	go d.eventHandler(subscriptions)
 	return nil
 
 }
 
 // Stop stops the HubClient.
 func (d *HubClient) Stop() error {
-	err := d.client.StopEventListener()
-	if err != nil {
-		return err
-	}
+	// The context is canceled first, so the event handler doesn't reconnect once the event listener quits
 	d.cancel()
-	return nil
+	return d.client.StopEventListener()
 }
 
 // PostBatch posts a batch to the Dymension Hub.
@@ -264,68 +278,183 @@ func (d *HubClient) GetSequencers(rollappID string) ([]*types.Sequencer, error)
 		if err != nil {
 			return nil, err
 		}
-		var status types.SequencerStatus
-		if sequencer.Status == sequencertypes.Proposer {
-			status = types.Proposer
-		} else {
-			status = types.Inactive
-		}
-		if err != nil {
-			return nil, err
-		}
 		sequencersList = append(sequencersList, &types.Sequencer{
 			PublicKey: pubKey,
-			Status:    status,
+			Status:    sequencerStatus(sequencer.Status),
 		})
 	}
 	return sequencersList, nil
 }
 
-func (d *HubClient) eventHandler() {
-	// TODO(omritoptix): eventsChannel should be a generic channel which is later filtered by the event type.
-	eventsChannel, err := d.client.SubscribeToEvents(context.Background(), "dymension-client", fmt.Sprintf(eventStateUpdate, d.config.RollappID))
+// sequencerStatus maps the operating status of a sequencer on the hub to its status.
+func sequencerStatus(status sequencertypes.OperatingStatus) types.SequencerStatus {
+	if status == sequencertypes.Proposer {
+		return types.Proposer
+	}
+	return types.Inactive
+}
+
+// eventSubscriptions holds the channels of the settlement layer events the client is subscribed to.
+type eventSubscriptions struct {
+	stateUpdates          <-chan ctypes.ResultEvent
+	sequencersListUpdates <-chan ctypes.ResultEvent
+	// quit is closed once the event listener disconnects
+	quit <-chan struct{}
+}
+
+func (d *HubClient) subscribeToEvents() (*eventSubscriptions, error) {
+	stateUpdates, err := d.client.SubscribeToEvents(d.ctx, eventsSubscriber, fmt.Sprintf(eventStateUpdate, d.config.RollappID))
 	if err != nil {
-		panic("Error subscribing to events")
+		return nil, err
+	}
+	sequencersListUpdates, err := d.client.SubscribeToEvents(d.ctx, eventsSubscriber, fmt.Sprintf(eventSequencersListUpdate, d.config.RollappID))
+	if err != nil {
+		return nil, err
 	}
+	return &eventSubscriptions{
+		stateUpdates:          stateUpdates,
+		sequencersListUpdates: sequencersListUpdates,
+		quit:                  d.client.EventListenerQuit(),
+	}, nil
+}
+
+func (d *HubClient) eventHandler(subscriptions *eventSubscriptions) {
 	for {
 		select {
 		case <-d.ctx.Done():
 			return
-		case <-d.client.EventListenerQuit():
-			panic("Settlement WS disconnected")
-		case event := <-eventsChannel:
-			// Assert value is in map and publish it to the event bus
-			d.logger.Debug("Received event from settlement layer", "event", event)
-			_, ok := d.eventMap[event.Query]
-			if !ok {
-				d.logger.Debug("Ignoring event. Type not supported", "event", event)
-				continue
+		case <-subscriptions.quit:
+			d.logger.Error("Settlement layer websocket disconnected, reconnecting")
+			subscriptions = d.reconnect()
+			if subscriptions == nil {
+				return
 			}
-			eventData, err := d.getEventData(d.eventMap[event.Query], event)
-			if err != nil {
-				d.logger.Error("Failed to get event data", "err", err)
-				continue
-			}
-			err = d.pubsub.PublishWithEvents(d.ctx, eventData, map[string][]string{settlement.EventTypeKey: {d.eventMap[event.Query]}})
-			if err != nil {
-				d.logger.Error("Error publishing event", "err", err)
+			d.reconcileStateUpdates()
+			d.reconcileSequencersList()
+		case event := <-subscriptions.stateUpdates:
+			d.handleEvent(event)
+		case event := <-subscriptions.sequencersListUpdates:
+			d.handleEvent(event)
+		}
+	}
+}
+
+// reconnect restarts the event listener and subscribes to the events again. It retries with an exponential backoff
+// until it succeeds, or returns nil once the client is stopped.
+func (d *HubClient) reconnect() *eventSubscriptions {
+	backoff := initialReconnectBackoff
+	for d.ctx.Err() == nil {
+		// The event listener might still be running, e.g. in case the subscriptions failed
+		_ = d.client.StopEventListener()
+		err := d.client.StartEventListener()
+		if err == nil {
+			var subscriptions *eventSubscriptions
+			subscriptions, err = d.subscribeToEvents()
+			if err == nil {
+				d.logger.Info("Reconnected to settlement layer websocket")
+				return subscriptions
 			}
 		}
+		d.logger.Error("Failed to reconnect to settlement layer websocket", "error", err, "retryIn", backoff)
+		select {
+		case <-d.ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+	return nil
+}
+
+// reconcileStateUpdates publishes the latest state update in case it was missed while the websocket was disconnected.
+func (d *HubClient) reconcileStateUpdates() {
+	latestStateInfoIndexResp, err := d.rollappQueryClient.LatestStateInfoIndex(d.ctx,
+		&rollapptypes.QueryGetLatestStateInfoIndexRequest{RollappId: d.config.RollappID})
+	if err != nil || latestStateInfoIndexResp == nil {
+		d.logger.Error("Failed to get latest state index after reconnecting", "error", err)
+		return
+	}
+	stateIndex := latestStateInfoIndexResp.LatestStateInfoIndex.Index
+	if stateIndex <= atomic.LoadUint64(&d.latestStateIndex) {
+		return
+	}
+	latestBatch, err := d.GetBatchAtIndex(d.config.RollappID, stateIndex)
+	if err != nil {
+		d.logger.Error("Failed to get latest batch after reconnecting", "stateIndex", stateIndex, "error", err)
+		return
+	}
+	d.logger.Info("Publishing state update missed while disconnected", "stateIndex", stateIndex, "endHeight", latestBatch.EndHeight)
+	d.publishEvent(settlement.EventNewSettlementBatchAccepted, &settlement.EventDataNewSettlementBatchAccepted{
+		EndHeight:  latestBatch.EndHeight,
+		StateIndex: stateIndex,
+	})
+}
+
+// reconcileSequencersList publishes the sequencers list in case an update was missed while the websocket was disconnected.
+func (d *HubClient) reconcileSequencersList() {
+	sequencersList, err := d.GetSequencers(d.config.RollappID)
+	if err != nil {
+		d.logger.Error("Failed to get sequencers list after reconnecting", "error", err)
+		return
+	}
+	sequencers := make([]types.Sequencer, len(sequencersList))
+	for i, sequencer := range sequencersList {
+		sequencers[i] = *sequencer
+	}
+	d.publishEvent(settlement.EventSequencersListUpdated, &settlement.EventDataSequencersListUpdated{Sequencers: sequencers})
+}
+
+func (d *HubClient) handleEvent(event ctypes.ResultEvent) {
+	// Assert value is in map and publish it to the event bus
+	d.logger.Debug("Received event from settlement layer", "event", event)
+	eventType, ok := d.eventMap[event.Query]
+	if !ok {
+		d.logger.Debug("Ignoring event. Type not supported", "event", event)
+		return
+	}
+	eventData, err := d.getEventData(eventType, event)
+	if err != nil {
+		d.logger.Error("Failed to get event data", "err", err)
+		return
+	}
+	d.publishEvent(eventType, eventData)
+}
+
+func (d *HubClient) publishEvent(eventType string, eventData interface{}) {
+	if batchAccepted, ok := eventData.(*settlement.EventDataNewSettlementBatchAccepted); ok {
+		d.updateLatestStateIndex(batchAccepted.StateIndex)
+	}
+	err := d.pubsub.PublishWithEvents(d.ctx, eventData, map[string][]string{settlement.EventTypeKey: {eventType}})
+	if err != nil {
+		d.logger.Error("Error publishing event", "err", err)
+	}
+}
+
+// updateLatestStateIndex records the index of the latest state update published.
+func (d *HubClient) updateLatestStateIndex(stateIndex uint64) {
+	for {
+		latest := atomic.LoadUint64(&d.latestStateIndex)
+		if stateIndex <= latest || atomic.CompareAndSwapUint64(&d.latestStateIndex, latest, stateIndex) {
+			return
+		}
 	}
 }
 
 func (d *HubClient) convertBatchToMsgUpdateState(batch *types.Batch, daClient da.Client, daResult *da.ResultSubmitBatch) (*rollapptypes.MsgUpdateState, error) {
 	DAMetaData := &settlement.DAMetaData{
-		Height: daResult.DAHeight,
-		Client: daClient,
+		Height:  daResult.DAHeight,
+		Client:  daClient,
+		Chunked: daResult.Chunked,
 	}
 	blockDescriptors := make([]rollapptypes.BlockDescriptor, len(batch.Blocks))
 	for index, block := range batch.Blocks {
+		isrRoot := block.Data.IntermediateStateRoots.Root()
 		blockDescriptor := rollapptypes.BlockDescriptor{
-			Height:    block.Header.Height,
-			StateRoot: block.Header.AppHash[:],
-			// TODO(omritoptix): Change to a real ISR once supported
-			IntermediateStatesRoot: make([]byte, 32),
+			Height:                 block.Header.Height,
+			StateRoot:              block.Header.AppHash[:],
+			IntermediateStatesRoot: isrRoot[:],
 		}
 		blockDescriptors[index] = blockDescriptor
 	}
@@ -358,10 +487,37 @@ func (d *HubClient) getEventData(eventType string, rawEventData ctypes.ResultEve
 	switch eventType {
 	case settlement.EventNewSettlementBatchAccepted:
 		return d.convertToNewBatchEvent(rawEventData)
+	case settlement.EventSequencersListUpdated:
+		return d.convertToSequencersListUpdatedEvent(rawEventData)
 	}
 	return nil, fmt.Errorf("event type %s not recognized", eventType)
 }
 
+// convertToSequencersListUpdatedEvent decodes the updated sequencers list from the event. For each sequencer of the
+// rollapp, in the same order, the event carries its dymint public key, as the JSON of the Any packing it, and its
+// operating status.
+func (d *HubClient) convertToSequencersListUpdatedEvent(rawEventData ctypes.ResultEvent) (*settlement.EventDataSequencersListUpdated, error) {
+	pubKeys := rawEventData.Events["sequencers_list_update.dymint_pub_key"]
+	statuses := rawEventData.Events["sequencers_list_update.status"]
+	if len(pubKeys) == 0 || len(pubKeys) != len(statuses) {
+		return nil, fmt.Errorf("sequencers list update with %d public keys and %d statuses", len(pubKeys), len(statuses))
+	}
+	sequencers := make([]types.Sequencer, len(pubKeys))
+	for i := range pubKeys {
+		var pubKey cryptotypes.PubKey
+		if err := d.protoCodec.UnmarshalInterfaceJSON([]byte(pubKeys[i]), &pubKey); err != nil {
+			return nil, fmt.Errorf("invalid sequencer public key %s: %w", pubKeys[i], err)
+		}
+		// typed events quote the attribute values
+		status, ok := sequencertypes.OperatingStatus_value[strings.Trim(statuses[i], `"`)]
+		if !ok {
+			return nil, fmt.Errorf("invalid sequencer status %s", statuses[i])
+		}
+		sequencers[i] = types.Sequencer{PublicKey: pubKey, Status: sequencerStatus(sequencertypes.OperatingStatus(status))}
+	}
+	return &settlement.EventDataSequencersListUpdated{Sequencers: sequencers}, nil
+}
+
 func (d *HubClient) convertToNewBatchEvent(rawEventData ctypes.ResultEvent) (*settlement.EventDataNewSettlementBatchAccepted, error) {
 	var multiErr *multierror.Error
 	numBlocks, err := strconv.ParseInt(rawEventData.Events["state_update.num_blocks"][0], 10, 64)
@@ -1,18 +1,30 @@
 package dymension
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/libs/pubsub"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
+	rollapptypes "github.com/dymensionxyz/dymension/x/rollapp/types"
 	sequencertypes "github.com/dymensionxyz/dymension/x/sequencer/types"
+	"github.com/dymensionxyz/dymint/da"
 	"github.com/dymensionxyz/dymint/log/test"
 	mocks "github.com/dymensionxyz/dymint/mocks"
 	settlementmocks "github.com/dymensionxyz/dymint/mocks/settlement"
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/types"
 
 	sdkcodectypes "github.com/cosmos/cosmos-sdk/codec/types"
 )
@@ -44,6 +56,156 @@ func TestGetSequencers(t *testing.T) {
 	require.Len(sequencers, count)
 }
 
+// TestEventHandlerReconnect tests that the client reconnects once the websocket disconnects, and that it publishes
+// the state update it missed while it was disconnected.
+func TestEventHandlerReconnect(t *testing.T) {
+	require := require.New(t)
+	cosmosClientMock := mocks.NewCosmosClient(t)
+	rollappQueryClientMock := settlementmocks.NewRollAppQueryClient(t)
+	cosmosClientMock.On("GetRollappClient").Return(rollappQueryClientMock)
+	sequencerQueryClientMock := settlementmocks.NewSequencerQueryClient(t)
+	sequencersRollappResponse, _ := generateSequencerByRollappResponse(t, 1)
+	sequencerQueryClientMock.On("SequencersByRollapp", mock.Anything, mock.Anything).Return(sequencersRollappResponse, nil)
+	cosmosClientMock.On("GetSequencerClient").Return(sequencerQueryClientMock)
+
+	stateUpdates := make(chan ctypes.ResultEvent, 1)
+	sequencersListUpdates := make(chan ctypes.ResultEvent, 1)
+	quit, reconnectedQuit := make(chan struct{}), make(chan struct{})
+	cosmosClientMock.On("StartEventListener").Return(nil).Once()
+	// The first reconnection attempt fails
+	cosmosClientMock.On("StartEventListener").Return(errors.New("connection refused")).Once()
+	cosmosClientMock.On("StartEventListener").Return(nil)
+	cosmosClientMock.On("StopEventListener").Return(nil)
+	cosmosClientMock.On("SubscribeToEvents", mock.Anything, mock.Anything, fmt.Sprintf(eventStateUpdate, "")).Return((<-chan ctypes.ResultEvent)(stateUpdates), nil)
+	cosmosClientMock.On("SubscribeToEvents", mock.Anything, mock.Anything, fmt.Sprintf(eventSequencersListUpdate, "")).Return((<-chan ctypes.ResultEvent)(sequencersListUpdates), nil)
+	cosmosClientMock.On("EventListenerQuit").Return((<-chan struct{})(quit)).Once()
+	cosmosClientMock.On("EventListenerQuit").Return((<-chan struct{})(reconnectedQuit))
+
+	daPath := (&settlement.DAMetaData{Height: 1, Client: da.Mock}).ToPath()
+	rollappQueryClientMock.On("LatestStateInfoIndex", mock.Anything, mock.Anything).Return(
+		&rollapptypes.QueryGetLatestStateInfoIndexResponse{LatestStateInfoIndex: rollapptypes.StateInfoIndex{Index: 3}}, nil)
+	rollappQueryClientMock.On("StateInfo", mock.Anything, mock.Anything).Return(
+		&rollapptypes.QueryGetStateInfoResponse{StateInfo: rollapptypes.StateInfo{
+			StateInfoIndex: rollapptypes.StateInfoIndex{Index: 3}, StartHeight: 11, NumBlocks: 5, DAPath: daPath,
+		}}, nil)
+
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	subscription, err := pubsubServer.Subscribe(context.Background(), "test", settlement.EventQueryNewSettlementBatchAccepted)
+	require.NoError(err)
+
+	hubClient, err := newDymensionHubClient([]byte{}, pubsubServer, test.NewLogger(t), WithCosmosClient(cosmosClientMock))
+	require.NoError(err)
+	require.NoError(hubClient.Start())
+	defer func() {
+		require.NoError(hubClient.Stop())
+	}()
+
+	nextEvent := func() *settlement.EventDataNewSettlementBatchAccepted {
+		select {
+		case event := <-subscription.Out():
+			return event.Data().(*settlement.EventDataNewSettlementBatchAccepted)
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for state update event")
+			return nil
+		}
+	}
+
+	close(quit)
+	eventData := nextEvent()
+	require.Equal(uint64(3), eventData.StateIndex)
+	require.Equal(uint64(15), eventData.EndHeight)
+
+	// The events are received again once reconnected
+	stateUpdates <- ctypes.ResultEvent{
+		Query: fmt.Sprintf(eventStateUpdate, ""),
+		Events: map[string][]string{
+			"state_update.num_blocks":       {"5"},
+			"state_update.start_height":     {"16"},
+			"state_update.state_info_index": {"4"},
+		},
+	}
+	eventData = nextEvent()
+	require.Equal(uint64(4), eventData.StateIndex)
+	require.Equal(uint64(20), eventData.EndHeight)
+}
+
+// TestSequencersListUpdated tests that the sequencers list updates are published, so the settlement
+// client follows the rotations of the proposer.
+func TestSequencersListUpdated(t *testing.T) {
+	require := require.New(t)
+	cosmosClientMock := mocks.NewCosmosClient(t)
+	rollappQueryClientMock := settlementmocks.NewRollAppQueryClient(t)
+	sequencerQueryClientMock := settlementmocks.NewSequencerQueryClient(t)
+	cosmosClientMock.On("GetRollappClient").Return(rollappQueryClientMock)
+	cosmosClientMock.On("GetSequencerClient").Return(sequencerQueryClientMock)
+
+	sequencersListUpdates := make(chan ctypes.ResultEvent, 1)
+	cosmosClientMock.On("StartEventListener").Return(nil)
+	cosmosClientMock.On("StopEventListener").Return(nil)
+	cosmosClientMock.On("SubscribeToEvents", mock.Anything, mock.Anything, fmt.Sprintf(eventStateUpdate, "")).Return((<-chan ctypes.ResultEvent)(make(chan ctypes.ResultEvent)), nil)
+	cosmosClientMock.On("SubscribeToEvents", mock.Anything, mock.Anything, fmt.Sprintf(eventSequencersListUpdate, "")).Return((<-chan ctypes.ResultEvent)(sequencersListUpdates), nil)
+	cosmosClientMock.On("EventListenerQuit").Return((<-chan struct{})(make(chan struct{})))
+	rollappQueryClientMock.On("LatestStateInfoIndex", mock.Anything, mock.Anything).Return(nil, nil)
+
+	sequencersRollappResponse, proposer := generateSequencerByRollappResponse(t, 2)
+	updatedResponse, updatedProposer := generateSequencerByRollappResponse(t, 2)
+	sequencerQueryClientMock.On("SequencersByRollapp", mock.Anything, mock.Anything).Return(sequencersRollappResponse, nil)
+
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	hubClient, err := newDymensionHubClient([]byte{}, pubsubServer, test.NewLogger(t), WithCosmosClient(cosmosClientMock))
+	require.NoError(err)
+	settlementClient := &settlement.BaseLayerClient{}
+	require.NoError(settlementClient.Init([]byte{}, pubsubServer, test.NewLogger(t), settlement.WithHubClient(hubClient)))
+	require.NoError(settlementClient.Start())
+	defer func() {
+		require.NoError(settlementClient.Stop())
+	}()
+	require.Equal(proposer.Sequencer.DymintPubKey.GetCachedValue(), settlementClient.GetProposer().PublicKey)
+
+	// The updated sequencers list is decoded from the event
+	event := ctypes.ResultEvent{Query: fmt.Sprintf(eventSequencersListUpdate, ""), Events: map[string][]string{}}
+	for _, info := range updatedResponse.SequencerInfoList {
+		pubKey, err := hubClient.protoCodec.MarshalInterfaceJSON(info.Sequencer.DymintPubKey.GetCachedValue().(cryptotypes.PubKey))
+		require.NoError(err)
+		event.Events["sequencers_list_update.dymint_pub_key"] = append(event.Events["sequencers_list_update.dymint_pub_key"], string(pubKey))
+		event.Events["sequencers_list_update.status"] = append(event.Events["sequencers_list_update.status"], strconv.Quote(info.Status.String()))
+	}
+	sequencersListUpdates <- event
+	require.Eventually(func() bool {
+		return settlementClient.GetProposer().PublicKey.Equals(updatedProposer.Sequencer.DymintPubKey.GetCachedValue().(cryptotypes.PubKey))
+	}, time.Second, 10*time.Millisecond)
+	require.Len(settlementClient.GetSequencersList(), 2)
+
+	// Events missing the status of a sequencer are rejected
+	event.Events["sequencers_list_update.status"] = event.Events["sequencers_list_update.status"][:1]
+	_, err = hubClient.convertToSequencersListUpdatedEvent(event)
+	require.Error(err)
+}
+
+func TestConvertBatchToMsgUpdateStateISRs(t *testing.T) {
+	require := require.New(t)
+	hubClient := &HubClient{config: &Config{RollappID: "mock-rollapp"}}
+
+	batch := &types.Batch{
+		StartHeight: 1,
+		EndHeight:   2,
+		Blocks: []*types.Block{
+			{Header: types.Header{Height: 1}},
+			{Header: types.Header{Height: 2}, Data: types.Data{
+				IntermediateStateRoots: types.IntermediateStateRoots{RawRootsList: [][]byte{{1}, {2}, {3}}},
+			}},
+		},
+	}
+	msg, err := hubClient.convertBatchToMsgUpdateState(batch, da.Mock, &da.ResultSubmitBatch{})
+	require.NoError(err)
+	require.Len(msg.BDs.BD, 2)
+	// Blocks without ISRs get a zero root
+	require.Equal(make([]byte, 32), msg.BDs.BD[0].IntermediateStatesRoot)
+	require.Equal(merkle.HashFromByteSlices([][]byte{{1}, {2}, {3}}), msg.BDs.BD[1].IntermediateStatesRoot)
+}
+
 /* -------------------------------------------------------------------------- */
 /*                                    Utils                                   */
 /* -------------------------------------------------------------------------- */
@@ -37,6 +37,7 @@ type EventDataSequencersListUpdated struct {
 // Define queries
 var (
 	EventQueryNewSettlementBatchAccepted = QueryForEvent(EventNewSettlementBatchAccepted)
+	EventQuerySequencersListUpdated      = QueryForEvent(EventSequencersListUpdated)
 )
 
 // QueryForEvent returns a query for the given event.
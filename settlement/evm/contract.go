@@ -0,0 +1,245 @@
+package evm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// The state commitment contract the client interacts with. Its Solidity interface is:
+//
+//	interface IStateCommitment {
+//	    event StateUpdate(uint256 indexed stateIndex, uint64 startHeight, uint64 numBlocks, string daPath);
+//	    event SequencersListUpdate();
+//
+//	    function updateState(uint64 startHeight, uint64 numBlocks, string calldata daPath,
+//	        bytes32[] calldata stateRoots, bytes32[] calldata intermediateStatesRoots) external;
+//	    function latestStateIndex() external view returns (uint256);
+//	    function stateInfo(uint256 index) external view returns (uint64 startHeight, uint64 numBlocks, string memory daPath);
+//	    function sequencers() external view returns (bytes32[] memory dymintPubKeys, uint256 proposer);
+//	}
+//
+// The state indexes start at 1, as on the Dymension hub. updateState reverts unless startHeight follows the end
+// height of the latest state. sequencers returns the ed25519 public keys of the sequencers and the position of the
+// proposer in the list.
+const (
+	updateStateSignature          = "updateState(uint64,uint64,string,bytes32[],bytes32[])"
+	latestStateIndexSignature     = "latestStateIndex()"
+	stateInfoSignature            = "stateInfo(uint256)"
+	sequencersSignature           = "sequencers()"
+	stateUpdateEventSignature     = "StateUpdate(uint256,uint64,uint64,string)"
+	sequencersListUpdateSignature = "SequencersListUpdate()"
+)
+
+var (
+	updateStateSelector      = selector(updateStateSignature)
+	latestStateIndexSelector = selector(latestStateIndexSignature)
+	stateInfoSelector        = selector(stateInfoSignature)
+	sequencersSelector       = selector(sequencersSignature)
+
+	stateUpdateTopic          = keccak256([]byte(stateUpdateEventSignature))
+	sequencersListUpdateTopic = keccak256([]byte(sequencersListUpdateSignature))
+)
+
+const wordSize = 32
+
+func keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		hash.Write(b) //nolint:errcheck
+	}
+	return hash.Sum(nil)
+}
+
+func selector(signature string) []byte {
+	return keccak256([]byte(signature))[:4]
+}
+
+// stateInfo is a state update stored by the contract.
+type stateInfo struct {
+	StartHeight uint64
+	NumBlocks   uint64
+	DAPath      string
+}
+
+// updateStateCall holds the arguments of updateState.
+type updateStateCall struct {
+	stateInfo
+	StateRoots              [][32]byte
+	IntermediateStatesRoots [][32]byte
+}
+
+func packUpdateState(call *updateStateCall) ([]byte, error) {
+	args, err := abiEncode(call.StartHeight, call.NumBlocks, call.DAPath, call.StateRoots, call.IntermediateStatesRoots)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, updateStateSelector...), args...), nil
+}
+
+func unpackUpdateState(args []byte) (*updateStateCall, error) {
+	var call updateStateCall
+	var err error
+	r := abiReader(args)
+	if call.StartHeight, err = r.uint64(0); err != nil {
+		return nil, err
+	}
+	if call.NumBlocks, err = r.uint64(1); err != nil {
+		return nil, err
+	}
+	if call.DAPath, err = r.string(2); err != nil {
+		return nil, err
+	}
+	if call.StateRoots, err = r.bytes32Array(3); err != nil {
+		return nil, err
+	}
+	if call.IntermediateStatesRoots, err = r.bytes32Array(4); err != nil {
+		return nil, err
+	}
+	return &call, nil
+}
+
+func packStateInfo(index uint64) []byte {
+	return append(append([]byte{}, stateInfoSelector...), abiWord(index)...)
+}
+
+// unpackStateInfo decodes the values returned by stateInfo, or the data of a StateUpdate log.
+func unpackStateInfo(data []byte) (*stateInfo, error) {
+	var info stateInfo
+	var err error
+	r := abiReader(data)
+	if info.StartHeight, err = r.uint64(0); err != nil {
+		return nil, err
+	}
+	if info.NumBlocks, err = r.uint64(1); err != nil {
+		return nil, err
+	}
+	if info.DAPath, err = r.string(2); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// unpackSequencers decodes the values returned by sequencers.
+func unpackSequencers(data []byte) ([][32]byte, uint64, error) {
+	r := abiReader(data)
+	pubKeys, err := r.bytes32Array(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	proposer, err := r.uint64(1)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pubKeys, proposer, nil
+}
+
+// abiWord encodes an unsigned integer as an ABI word.
+func abiWord(n uint64) []byte {
+	word := make([]byte, wordSize)
+	binary.BigEndian.PutUint64(word[wordSize-8:], n)
+	return word
+}
+
+// abiEncode encodes the values with the contract ABI. Only the types used by the contract are supported: uint64,
+// string and bytes32[].
+func abiEncode(values ...interface{}) ([]byte, error) {
+	head := make([]byte, 0, wordSize*len(values))
+	var tail []byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case uint64:
+			head = append(head, abiWord(v)...)
+		case string:
+			head = append(head, abiWord(uint64(wordSize*len(values)+len(tail)))...)
+			tail = append(tail, abiWord(uint64(len(v)))...)
+			tail = append(tail, v...)
+			if padding := len(v) % wordSize; padding != 0 {
+				tail = append(tail, make([]byte, wordSize-padding)...)
+			}
+		case [][32]byte:
+			head = append(head, abiWord(uint64(wordSize*len(values)+len(tail)))...)
+			tail = append(tail, abiWord(uint64(len(v)))...)
+			for _, item := range v {
+				tail = append(tail, item[:]...)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported ABI type %T", value)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+// abiReader decodes ABI encoded values.
+type abiReader []byte
+
+var errShortABIData = errors.New("ABI data too short")
+
+func (r abiReader) word(offset uint64) ([]byte, error) {
+	if offset > uint64(len(r)) || uint64(len(r))-offset < wordSize {
+		return nil, errShortABIData
+	}
+	return r[offset : offset+wordSize], nil
+}
+
+// wordUint64 decodes the word at the given byte offset as an unsigned integer which must fit in 64 bits.
+func (r abiReader) wordUint64(offset uint64) (uint64, error) {
+	word, err := r.word(offset)
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range word[:wordSize-8] {
+		if b != 0 {
+			return 0, errors.New("ABI integer overflows uint64")
+		}
+	}
+	return binary.BigEndian.Uint64(word[wordSize-8:]), nil
+}
+
+// uint64 decodes the i-th value as an unsigned integer.
+func (r abiReader) uint64(i int) (uint64, error) {
+	return r.wordUint64(uint64(i) * wordSize)
+}
+
+// dynamic returns the length of the i-th value, of a dynamic type, and the offset of its content.
+func (r abiReader) dynamic(i int) (uint64, uint64, error) {
+	offset, err := r.uint64(i)
+	if err != nil {
+		return 0, 0, err
+	}
+	length, err := r.wordUint64(offset)
+	if err != nil {
+		return 0, 0, err
+	}
+	return length, offset + wordSize, nil
+}
+
+// string decodes the i-th value as a string.
+func (r abiReader) string(i int) (string, error) {
+	length, offset, err := r.dynamic(i)
+	if err != nil {
+		return "", err
+	}
+	if offset > uint64(len(r)) || uint64(len(r))-offset < length {
+		return "", errShortABIData
+	}
+	return string(r[offset : offset+length]), nil
+}
+
+// bytes32Array decodes the i-th value as a bytes32 array.
+func (r abiReader) bytes32Array(i int) ([][32]byte, error) {
+	length, offset, err := r.dynamic(i)
+	if err != nil {
+		return nil, err
+	}
+	if offset > uint64(len(r)) || (uint64(len(r))-offset)/wordSize < length {
+		return nil, errShortABIData
+	}
+	items := make([][32]byte, length)
+	for j := range items {
+		copy(items[j][:], r[offset+uint64(j)*wordSize:])
+	}
+	return items, nil
+}
@@ -0,0 +1,579 @@
+package evm
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/log"
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/types"
+	"github.com/tendermint/tendermint/libs/pubsub"
+)
+
+const (
+	// DefaultNodeAddress is the default JSON-RPC endpoint of the EVM node.
+	DefaultNodeAddress = "http://localhost:8545"
+	// DefaultPollInterval is the default interval between polls of the contract logs.
+	DefaultPollInterval = 5 * time.Second
+	// DefaultTxTimeout is the default time to wait for a state update to be mined.
+	DefaultTxTimeout = 2 * time.Minute
+	// DefaultResendInterval is the default time to wait for a state update to be mined before sending it again.
+	DefaultResendInterval = 30 * time.Second
+)
+
+// receiptPollInterval is the interval between polls of the receipt of a state update.
+const receiptPollInterval = time.Second
+
+// gasPriceBumpDivisor sets the increase of the gas price of a resent state update to 1/8 of its gas price, above the
+// 10% increase nodes require to replace a pending transaction.
+const gasPriceBumpDivisor = 8
+
+// ErrNoPrivateKey is returned when posting a batch without a private key configured.
+var ErrNoPrivateKey = errors.New("no private key configured")
+
+// LayerClient is a settlement layer client anchoring the rollapp to a state commitment contract on an EVM chain.
+type LayerClient struct {
+	*settlement.BaseLayerClient
+}
+
+// Config for the EVM LayerClient
+type Config struct {
+	// NodeAddress is the JSON-RPC endpoint of the EVM node.
+	NodeAddress string `json:"node_address"`
+	// ContractAddress is the address of the state commitment contract of the rollapp.
+	ContractAddress string `json:"contract_address"`
+	// PrivateKey is the hex encoded secp256k1 key of the account sending the state updates. It is only needed by
+	// the sequencers.
+	PrivateKey string `json:"private_key"`
+	// ChainID is the chain ID the transactions are signed for. Zero means the chain ID of the node.
+	ChainID uint64 `json:"chain_id"`
+	// GasLimit is the gas limit of the state updates. Zero means the gas is estimated by the node.
+	GasLimit uint64 `json:"gas_limit"`
+	// PollInterval is the interval between polls of the contract logs.
+	PollInterval time.Duration `json:"poll_interval"`
+	// TxTimeout bounds the time to wait for a state update to be mined.
+	TxTimeout time.Duration `json:"tx_timeout"`
+	// ResendInterval is the time to wait for a state update to be mined before sending it again, with the same nonce
+	// and a higher gas price.
+	ResendInterval time.Duration `json:"resend_interval"`
+	// Confirmations is the number of blocks mined on top of the block of a log before it is handled. Zero handles the
+	// logs as soon as they are mined, which is only safe on chains without reorgs.
+	Confirmations uint64 `json:"confirmations"`
+	// Timeout bounds a single JSON-RPC call. Zero means no timeout.
+	Timeout time.Duration `json:"timeout"`
+	// RollappID is the ID of the rollapp anchored to the contract.
+	RollappID string `json:"rollapp_id"`
+}
+
+var _ settlement.LayerClient = &LayerClient{}
+
+// Init is called once. it initializes the struct members.
+func (c *LayerClient) Init(config []byte, pubsub *pubsub.Server, logger log.Logger, options ...settlement.Option) error {
+	hubClient, err := NewHubClient(config, pubsub, logger)
+	if err != nil {
+		return err
+	}
+	baseOptions := []settlement.Option{
+		settlement.WithHubClient(hubClient),
+	}
+	if options == nil {
+		options = baseOptions
+	} else {
+		options = append(baseOptions, options...)
+	}
+	c.BaseLayerClient = &settlement.BaseLayerClient{}
+	return c.BaseLayerClient.Init(config, pubsub, logger, options...)
+}
+
+// PostBatchResp is the response after posting a batch to the state commitment contract.
+type PostBatchResp struct {
+	txHash string
+	code   uint32
+}
+
+// GetTxHash returns the transaction hash.
+func (r PostBatchResp) GetTxHash() string {
+	return r.txHash
+}
+
+// GetCode returns the response code, 0 if the transaction succeeded.
+func (r PostBatchResp) GetCode() uint32 {
+	return r.code
+}
+
+// HubClient is the client for the state commitment contract.
+type HubClient struct {
+	config   *Config
+	logger   log.Logger
+	pubsub   *pubsub.Server
+	rpc      *rpcClient
+	contract Address
+	key      *btcec.PrivateKey
+	sender   Address
+	chainID  uint64
+	ctx      context.Context
+	cancel   context.CancelFunc
+	// txMu serializes the state updates, so they are sent with consecutive nonces
+	txMu sync.Mutex
+	// pendingTx is the latest state update which wasn't mined in time. It is replaced by the next state update.
+	pendingTx *transaction
+	wg        sync.WaitGroup
+}
+
+var _ settlement.HubClient = &HubClient{}
+
+// NewHubClient returns a client for the state commitment contract configured in config.
+func NewHubClient(config []byte, pubsub *pubsub.Server, logger log.Logger) (*HubClient, error) {
+	conf, err := getConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := ParseAddress(conf.ContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	hubClient := &HubClient{
+		config:   conf,
+		logger:   logger,
+		pubsub:   pubsub,
+		rpc:      newRPCClient(conf.NodeAddress, conf.Timeout),
+		contract: contract,
+		chainID:  conf.ChainID,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if conf.PrivateKey != "" {
+		rawKey, err := hex.DecodeString(strings.TrimPrefix(conf.PrivateKey, "0x"))
+		if err == nil && len(rawKey) != btcec.PrivKeyBytesLen {
+			err = errors.New("private key must be 32 bytes long")
+		}
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		key, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), rawKey)
+		hubClient.key = key
+		hubClient.sender = pubKeyAddress(pubKey)
+	}
+	return hubClient, nil
+}
+
+func getConfig(config []byte) (*Config, error) {
+	c := &Config{NodeAddress: DefaultNodeAddress}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return nil, err
+		}
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = DefaultPollInterval
+	}
+	if c.TxTimeout == 0 {
+		c.TxTimeout = DefaultTxTimeout
+	}
+	if c.ResendInterval == 0 {
+		c.ResendInterval = DefaultResendInterval
+	}
+	return c, nil
+}
+
+// Start starts the HubClient. The logs of the contract are polled from the first block of the node which isn't
+// confirmed yet on.
+func (c *HubClient) Start() error {
+	if c.chainID == 0 {
+		chainID, err := c.rpc.callUint64(c.ctx, "eth_chainId")
+		if err != nil {
+			return fmt.Errorf("failed to get chain id: %w", err)
+		}
+		c.chainID = chainID
+	}
+	head, err := c.rpc.callUint64(c.ctx, "eth_blockNumber")
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.logsLoop(c.confirmedBlock(head) + 1)
+	}()
+	return nil
+}
+
+// Stop stops the HubClient.
+func (c *HubClient) Stop() error {
+	c.cancel()
+	c.wg.Wait()
+	return nil
+}
+
+// PostBatch sends a state update for the batch to the contract and waits for it to be mined. A state update which
+// isn't mined within the resend interval is sent again with the same nonce and a higher gas price. An error is
+// returned if the state update is reverted.
+func (c *HubClient) PostBatch(batch *types.Batch, daClient da.Client, daResult *da.ResultSubmitBatch) (settlement.PostBatchResp, error) {
+	if c.key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	data, err := packUpdateState(convertBatchToUpdateState(batch, daClient, daResult))
+	if err != nil {
+		return nil, err
+	}
+
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.TxTimeout)
+	defer cancel()
+	tx, err := c.newTransaction(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	var txHashes []string
+	for {
+		txHash, err := c.sendTransaction(ctx, tx)
+		if err != nil {
+			if len(txHashes) == 0 {
+				c.logger.Error("Error sending batch to settlement layer", "error", err)
+				return nil, err
+			}
+			// One of the transactions sent before may have been mined meanwhile
+			c.logger.Debug("Failed to resend state update", "nonce", tx.Nonce, "error", err)
+		} else {
+			txHashes = append(txHashes, txHash)
+		}
+		lastTxHash := txHashes[len(txHashes)-1]
+
+		resendCtx, resendCancel := context.WithTimeout(ctx, c.config.ResendInterval)
+		receipt, err := c.waitForReceipt(resendCtx, txHashes)
+		resendCancel()
+		if err == nil {
+			c.pendingTx = nil
+			if receipt.Status != "0x1" {
+				return PostBatchResp{txHash: receipt.TxHash, code: 1}, fmt.Errorf("state update %s reverted", receipt.TxHash)
+			}
+			return PostBatchResp{txHash: receipt.TxHash}, nil
+		}
+		if ctx.Err() != nil {
+			// The next state update replaces this one, so its nonce isn't left pending
+			c.pendingTx = tx
+			return PostBatchResp{txHash: lastTxHash, code: 1}, fmt.Errorf("state update %s not mined: %w", lastTxHash, ctx.Err())
+		}
+		tx.GasPrice = bumpGasPrice(tx.GasPrice)
+		c.logger.Info("State update not mined, resending it with a higher gas price", "txHash", lastTxHash,
+			"nonce", tx.Nonce, "gasPrice", tx.GasPrice)
+	}
+}
+
+// GetLatestBatch returns the latest batch stored by the contract.
+func (c *HubClient) GetLatestBatch(rollappID string) (*settlement.ResultRetrieveBatch, error) {
+	result, err := c.callContract(latestStateIndexSelector)
+	if err != nil {
+		return nil, err
+	}
+	index, err := abiReader(result).uint64(0)
+	if err != nil {
+		return nil, err
+	}
+	if index == 0 {
+		return nil, settlement.ErrBatchNotFound
+	}
+	return c.GetBatchAtIndex(rollappID, index)
+}
+
+// GetBatchAtIndex returns the batch at the given index from the contract.
+func (c *HubClient) GetBatchAtIndex(rollappID string, index uint64) (*settlement.ResultRetrieveBatch, error) {
+	result, err := c.callContract(packStateInfo(index))
+	if err != nil {
+		return nil, err
+	}
+	info, err := unpackStateInfo(result)
+	if err != nil {
+		return nil, err
+	}
+	if info.NumBlocks == 0 {
+		return nil, settlement.ErrBatchNotFound
+	}
+	return convertStateInfoToResultRetrieveBatch(info, index)
+}
+
+// GetSequencers returns the sequencers registered in the contract.
+func (c *HubClient) GetSequencers(rollappID string) ([]*types.Sequencer, error) {
+	result, err := c.callContract(sequencersSelector)
+	if err != nil {
+		return nil, err
+	}
+	pubKeys, proposer, err := unpackSequencers(result)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKeys) == 0 {
+		return nil, settlement.ErrNoSequencerForRollapp
+	}
+	sequencersList := make([]*types.Sequencer, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		status := types.Inactive
+		if uint64(i) == proposer {
+			status = types.Proposer
+		}
+		sequencersList[i] = &types.Sequencer{
+			PublicKey: &ed25519.PubKey{Key: append([]byte{}, pubKey[:]...)},
+			Status:    status,
+		}
+	}
+	return sequencersList, nil
+}
+
+// newTransaction returns an unsigned transaction calling the contract with the given data. The nonce is the one
+// following the mined transactions of the sender, so a pending state update which wasn't mined in time is replaced,
+// at a higher gas price.
+func (c *HubClient) newTransaction(ctx context.Context, data []byte) (*transaction, error) {
+	nonce, err := c.rpc.callUint64(ctx, "eth_getTransactionCount", c.sender.String(), "latest")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := c.rpc.callUint64(ctx, "eth_gasPrice")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	if c.pendingTx != nil && c.pendingTx.Nonce == nonce {
+		if replacementPrice := bumpGasPrice(c.pendingTx.GasPrice); replacementPrice > gasPrice {
+			gasPrice = replacementPrice
+		}
+	}
+	gas := c.config.GasLimit
+	if gas == 0 {
+		gas, err = c.rpc.callUint64(ctx, "eth_estimateGas",
+			callMsg{From: c.sender.String(), To: c.contract.String(), Data: encodeBytes(data)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+	}
+	return &transaction{Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: c.contract, Data: data}, nil
+}
+
+// sendTransaction signs and sends the transaction, and returns its hash.
+func (c *HubClient) sendTransaction(ctx context.Context, tx *transaction) (string, error) {
+	if err := tx.sign(c.key, c.chainID); err != nil {
+		return "", err
+	}
+	var txHash string
+	if err := c.rpc.call(ctx, &txHash, "eth_sendRawTransaction", encodeBytes(tx.encode())); err != nil {
+		return "", err
+	}
+	return txHash, nil
+}
+
+// bumpGasPrice returns the gas price replacing a pending transaction sent at the given gas price.
+func bumpGasPrice(gasPrice uint64) uint64 {
+	bump := gasPrice / gasPriceBumpDivisor
+	if bump == 0 {
+		bump = 1
+	}
+	return gasPrice + bump
+}
+
+// waitForReceipt polls the receipts of the transactions, sent with the same nonce, until one of them is mined.
+func (c *HubClient) waitForReceipt(ctx context.Context, txHashes []string) (*rpcReceipt, error) {
+	for {
+		for _, txHash := range txHashes {
+			var receipt *rpcReceipt
+			if err := c.rpc.call(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+				c.logger.Debug("Failed to get receipt", "txHash", txHash, "error", err)
+			} else if receipt != nil {
+				return receipt, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
+
+func (c *HubClient) callContract(data []byte) ([]byte, error) {
+	return c.rpc.callBytes(c.ctx, "eth_call", callMsg{To: c.contract.String(), Data: encodeBytes(data)}, "latest")
+}
+
+// logsLoop polls the logs of the contract and publishes them as settlement events.
+func (c *HubClient) logsLoop(fromBlock uint64) {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			nextBlock, err := c.pollLogs(fromBlock)
+			if err != nil {
+				c.logger.Error("Failed to poll settlement layer logs", "fromBlock", fromBlock, "error", err)
+				continue
+			}
+			fromBlock = nextBlock
+		}
+	}
+}
+
+// pollLogs handles the logs of the contract from fromBlock to the latest confirmed block, and returns the next block to
+// poll.
+func (c *HubClient) pollLogs(fromBlock uint64) (uint64, error) {
+	latest, err := c.rpc.callUint64(c.ctx, "eth_blockNumber")
+	if err != nil {
+		return fromBlock, err
+	}
+	head := c.confirmedBlock(latest)
+	if head < fromBlock {
+		return fromBlock, nil
+	}
+	var logs []rpcLog
+	err = c.rpc.call(c.ctx, &logs, "eth_getLogs", logFilter{
+		FromBlock: encodeUint64(fromBlock),
+		ToBlock:   encodeUint64(head),
+		Address:   c.contract.String(),
+		Topics:    [][]string{{encodeBytes(stateUpdateTopic), encodeBytes(sequencersListUpdateTopic)}},
+	})
+	if err != nil {
+		return fromBlock, err
+	}
+	for _, l := range logs {
+		// The block of the log was reorged out, the transaction is polled again if it is mined in another block
+		if l.Removed {
+			c.logger.Info("Ignoring removed log from settlement layer", "txHash", l.TxHash, "blockNumber", l.BlockNumber)
+			continue
+		}
+		c.handleLog(l)
+	}
+	return head + 1, nil
+}
+
+// confirmedBlock returns the latest block with enough confirmations, given the latest block of the node.
+func (c *HubClient) confirmedBlock(latest uint64) uint64 {
+	if latest < c.config.Confirmations {
+		return 0
+	}
+	return latest - c.config.Confirmations
+}
+
+func (c *HubClient) handleLog(l rpcLog) {
+	c.logger.Debug("Received log from settlement layer", "log", l)
+	if len(l.Topics) == 0 {
+		return
+	}
+	eventType, eventData, err := c.getEventData(l)
+	if err != nil {
+		c.logger.Error("Failed to get event data", "err", err)
+		return
+	}
+	if eventData == nil {
+		c.logger.Debug("Ignoring log. Type not supported", "log", l)
+		return
+	}
+	err = c.pubsub.PublishWithEvents(c.ctx, eventData, map[string][]string{settlement.EventTypeKey: {eventType}})
+	if err != nil {
+		c.logger.Error("Error publishing event", "err", err)
+	}
+}
+
+func (c *HubClient) getEventData(l rpcLog) (string, interface{}, error) {
+	topic, err := decodeBytes(l.Topics[0])
+	if err != nil {
+		return "", nil, err
+	}
+	switch string(topic) {
+	case string(stateUpdateTopic):
+		eventData, err := convertToNewBatchEvent(l)
+		return settlement.EventNewSettlementBatchAccepted, eventData, err
+	case string(sequencersListUpdateTopic):
+		eventData, err := c.convertToSequencersListUpdatedEvent()
+		return settlement.EventSequencersListUpdated, eventData, err
+	}
+	return "", nil, nil
+}
+
+// convertToSequencersListUpdatedEvent returns the updated sequencers list, queried from the contract as the log
+// doesn't hold it.
+func (c *HubClient) convertToSequencersListUpdatedEvent() (*settlement.EventDataSequencersListUpdated, error) {
+	sequencersList, err := c.GetSequencers(c.config.RollappID)
+	if err != nil {
+		return nil, err
+	}
+	sequencers := make([]types.Sequencer, len(sequencersList))
+	for i, sequencer := range sequencersList {
+		sequencers[i] = *sequencer
+	}
+	return &settlement.EventDataSequencersListUpdated{Sequencers: sequencers}, nil
+}
+
+func convertToNewBatchEvent(l rpcLog) (*settlement.EventDataNewSettlementBatchAccepted, error) {
+	if len(l.Topics) != 2 {
+		return nil, errors.New("invalid StateUpdate log topics")
+	}
+	topic, err := decodeBytes(l.Topics[1])
+	if err != nil {
+		return nil, err
+	}
+	stateIndex, err := abiReader(topic).uint64(0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeBytes(l.Data)
+	if err != nil {
+		return nil, err
+	}
+	info, err := unpackStateInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	return &settlement.EventDataNewSettlementBatchAccepted{
+		EndHeight:  info.StartHeight + info.NumBlocks - 1,
+		StateIndex: stateIndex,
+	}, nil
+}
+
+func convertBatchToUpdateState(batch *types.Batch, daClient da.Client, daResult *da.ResultSubmitBatch) *updateStateCall {
+	daMetaData := &settlement.DAMetaData{
+		Height:  daResult.DAHeight,
+		Client:  daClient,
+		Chunked: daResult.Chunked,
+	}
+	call := &updateStateCall{
+		stateInfo: stateInfo{
+			StartHeight: batch.StartHeight,
+			NumBlocks:   batch.EndHeight - batch.StartHeight + 1,
+			DAPath:      daMetaData.ToPath(),
+		},
+		StateRoots:              make([][32]byte, len(batch.Blocks)),
+		IntermediateStatesRoots: make([][32]byte, len(batch.Blocks)),
+	}
+	for i, block := range batch.Blocks {
+		call.StateRoots[i] = block.Header.AppHash
+		call.IntermediateStatesRoots[i] = block.Data.IntermediateStateRoots.Root()
+	}
+	return call
+}
+
+func convertStateInfoToResultRetrieveBatch(info *stateInfo, index uint64) (*settlement.ResultRetrieveBatch, error) {
+	daMetaData := &settlement.DAMetaData{}
+	daMetaData, err := daMetaData.FromPath(info.DAPath)
+	if err != nil {
+		return nil, err
+	}
+	batchResult := &settlement.Batch{
+		StartHeight: info.StartHeight,
+		EndHeight:   info.StartHeight + info.NumBlocks - 1,
+		MetaData: &settlement.BatchMetaData{
+			DA: daMetaData,
+		},
+	}
+	return &settlement.ResultRetrieveBatch{
+		BaseResult: settlement.BaseResult{Code: settlement.StatusSuccess, StateIndex: index},
+		Batch:      batchResult}, nil
+}
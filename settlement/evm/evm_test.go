@@ -0,0 +1,398 @@
+package evm
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/pubsub"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/log/test"
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/testutil"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+const (
+	testChainID   = 1337
+	testRollappID = "rollapp_1337-1"
+	batchSize     = 5
+)
+
+var testContract = Address{0xc0, 0x27, 0x4a, 0xc7}
+
+// setupClient starts a settlement client on top of a simulated backend whose contract is owned by the client key. The
+// options modify the config of the client.
+func setupClient(t *testing.T, pubsubServer *pubsub.Server, options ...func(*Config)) (*LayerClient, *SimulatedBackend, []byte) {
+	require := require.New(t)
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(err)
+	backend := NewSimulatedBackend(testChainID, testContract, pubKeyAddress(key.PubKey()))
+	backend.SetSequencers([][32]byte{{1}, {2}}, 0)
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	conf := Config{
+		NodeAddress:     server.URL,
+		ContractAddress: testContract.String(),
+		PrivateKey:      hex.EncodeToString(key.Serialize()),
+		PollInterval:    10 * time.Millisecond,
+		RollappID:       testRollappID,
+	}
+	for _, option := range options {
+		option(&conf)
+	}
+	config, err := json.Marshal(conf)
+	require.NoError(err)
+	client := &LayerClient{}
+	require.NoError(client.Init(config, pubsubServer, test.NewLogger(t)))
+	require.NoError(client.Start())
+	t.Cleanup(func() {
+		require.NoError(client.Stop())
+	})
+	return client, backend, config
+}
+
+func TestSubmitAndRetrieve(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	subscription, err := pubsubServer.Subscribe(context.Background(), "test", settlement.EventQueryNewSettlementBatchAccepted)
+	require.NoError(err)
+	client, _, _ := setupClient(t, pubsubServer)
+
+	_, err = client.RetrieveBatch()
+	assert.Equal(settlement.ErrBatchNotFound, err)
+	_, err = client.RetrieveBatch(100)
+	assert.Equal(settlement.ErrBatchNotFound, err)
+
+	proposerKey, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(err)
+	numBatches := 3
+	for i := 0; i < numBatches; i++ {
+		startHeight := uint64(i)*batchSize + 1
+		batch, err := testutil.GenerateBatch(startHeight, startHeight+batchSize-1, proposerKey)
+		require.NoError(err)
+		daResult := &da.ResultSubmitBatch{BaseResult: da.BaseResult{DAHeight: uint64(i + 1)}, Chunked: i == 1}
+		resultSubmitBatch := client.SubmitBatch(batch, da.Mock, daResult)
+		require.Equal(settlement.StatusSuccess, resultSubmitBatch.Code)
+
+		// The state update is published once its log is polled
+		select {
+		case event := <-subscription.Out():
+			eventData := event.Data().(*settlement.EventDataNewSettlementBatchAccepted)
+			assert.Equal(uint64(i+1), eventData.StateIndex)
+			assert.Equal(batch.EndHeight, eventData.EndHeight)
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for state update event")
+		}
+	}
+
+	latestBatch, err := client.RetrieveBatch()
+	require.NoError(err)
+	assert.Equal(uint64(numBatches), latestBatch.StateIndex)
+	assert.Equal(uint64(numBatches*batchSize), latestBatch.EndHeight)
+
+	batch, err := client.RetrieveBatch(2)
+	require.NoError(err)
+	assert.Equal(uint64(batchSize+1), batch.StartHeight)
+	assert.Equal(uint64(2*batchSize), batch.EndHeight)
+	assert.Equal(&settlement.DAMetaData{Height: 2, Client: da.Mock, Chunked: true}, batch.MetaData.DA)
+}
+
+// TestRevertedStateUpdate tests that a state update rejected by the contract fails the submission.
+func TestRevertedStateUpdate(t *testing.T) {
+	require := require.New(t)
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	client, _, config := setupClient(t, pubsubServer)
+
+	proposerKey, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(err)
+	daResult := &da.ResultSubmitBatch{BaseResult: da.BaseResult{DAHeight: 1}}
+	batch, err := testutil.GenerateBatch(1, batchSize, proposerKey)
+	require.NoError(err)
+	require.Equal(settlement.StatusSuccess, client.SubmitBatch(batch, da.Mock, daResult).Code)
+
+	// The contract rejects a state update which doesn't follow the latest one
+	hubClient, err := NewHubClient(config, pubsubServer, test.NewLogger(t))
+	require.NoError(err)
+	require.NoError(hubClient.Start())
+	defer func() {
+		require.NoError(hubClient.Stop())
+	}()
+	batch, err = testutil.GenerateBatch(batchSize+2, 2*batchSize, proposerKey)
+	require.NoError(err)
+	resp, err := hubClient.PostBatch(batch, da.Mock, daResult)
+	require.Error(err)
+	require.NotZero(resp.GetCode())
+	require.NotEmpty(resp.GetTxHash())
+
+	latestBatch, err := client.RetrieveBatch()
+	require.NoError(err)
+	require.Equal(uint64(1), latestBatch.StateIndex)
+}
+
+// TestResendStateUpdate tests that a state update which isn't mined is sent again with a higher gas price.
+func TestResendStateUpdate(t *testing.T) {
+	require := require.New(t)
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	client, backend, config := setupClient(t, pubsubServer, func(c *Config) {
+		c.ResendInterval = 50 * time.Millisecond
+	})
+	backend.SetMinGasPrice(SimulatedGasPrice * 3 / 2)
+
+	hubClient, err := NewHubClient(config, pubsubServer, test.NewLogger(t))
+	require.NoError(err)
+	require.NoError(hubClient.Start())
+	defer func() {
+		require.NoError(hubClient.Stop())
+	}()
+	proposerKey, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, batchSize, proposerKey)
+	require.NoError(err)
+	resp, err := hubClient.PostBatch(batch, da.Mock, &da.ResultSubmitBatch{BaseResult: da.BaseResult{DAHeight: 1}})
+	require.NoError(err)
+	require.Zero(resp.GetCode())
+
+	latestBatch, err := client.RetrieveBatch()
+	require.NoError(err)
+	require.Equal(uint64(1), latestBatch.StateIndex)
+	require.Equal(uint64(1), backend.nonces[hubClient.sender])
+}
+
+// TestReplacePendingStateUpdate tests that a state update which wasn't mined in time is replaced by the next one,
+// instead of leaving its nonce pending.
+func TestReplacePendingStateUpdate(t *testing.T) {
+	require := require.New(t)
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	client, backend, config := setupClient(t, pubsubServer, func(c *Config) {
+		c.TxTimeout = 200 * time.Millisecond
+	})
+	backend.SetMinGasPrice(SimulatedGasPrice * 100)
+
+	hubClient, err := NewHubClient(config, pubsubServer, test.NewLogger(t))
+	require.NoError(err)
+	require.NoError(hubClient.Start())
+	defer func() {
+		require.NoError(hubClient.Stop())
+	}()
+	proposerKey, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(err)
+	batch, err := testutil.GenerateBatch(1, batchSize, proposerKey)
+	require.NoError(err)
+	daResult := &da.ResultSubmitBatch{BaseResult: da.BaseResult{DAHeight: 1}}
+	resp, err := hubClient.PostBatch(batch, da.Mock, daResult)
+	require.Error(err)
+	require.NotZero(resp.GetCode())
+
+	// The retry pays enough to replace the pending state update
+	backend.SetMinGasPrice(0)
+	resp, err = hubClient.PostBatch(batch, da.Mock, daResult)
+	require.NoError(err)
+	require.Zero(resp.GetCode())
+
+	latestBatch, err := client.RetrieveBatch()
+	require.NoError(err)
+	require.Equal(uint64(1), latestBatch.StateIndex)
+	require.Equal(uint64(1), backend.nonces[hubClient.sender])
+}
+
+// TestConfirmations tests that the logs are handled once confirmed, unless they were removed by a reorg.
+func TestConfirmations(t *testing.T) {
+	require := require.New(t)
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	subscription, err := pubsubServer.Subscribe(context.Background(), "test", settlement.EventQueryNewSettlementBatchAccepted)
+	require.NoError(err)
+	client, backend, _ := setupClient(t, pubsubServer, func(c *Config) {
+		c.Confirmations = 2
+	})
+
+	proposerKey, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(err)
+	for i := 0; i < 2; i++ {
+		startHeight := uint64(i)*batchSize + 1
+		batch, err := testutil.GenerateBatch(startHeight, startHeight+batchSize-1, proposerKey)
+		require.NoError(err)
+		daResult := &da.ResultSubmitBatch{BaseResult: da.BaseResult{DAHeight: uint64(i + 1)}}
+		require.Equal(settlement.StatusSuccess, client.SubmitBatch(batch, da.Mock, daResult).Code)
+	}
+	// The log of the second state update is removed by a reorg
+	backend.mu.Lock()
+	backend.logs[len(backend.logs)-1].Removed = true
+	backend.mu.Unlock()
+
+	// Only the block of the first state update is confirmed
+	backend.Mine(1)
+	select {
+	case event := <-subscription.Out():
+		eventData := event.Data().(*settlement.EventDataNewSettlementBatchAccepted)
+		require.Equal(uint64(1), eventData.StateIndex)
+	case <-time.After(5 * time.Second):
+		require.FailNow("timed out waiting for state update event")
+	}
+	backend.Mine(2)
+	select {
+	case event := <-subscription.Out():
+		require.FailNow("unexpected state update event", "event", event.Data())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestSequencersListUpdated tests that the updates of the sequencers in the contract are followed by the client.
+func TestSequencersListUpdated(t *testing.T) {
+	require := require.New(t)
+	pubsubServer := pubsub.NewServer()
+	require.NoError(pubsubServer.Start())
+	subscription, err := pubsubServer.Subscribe(context.Background(), "test", settlement.EventQuerySequencersListUpdated)
+	require.NoError(err)
+	client, backend, _ := setupClient(t, pubsubServer)
+
+	require.Len(client.GetSequencersList(), 2)
+	require.Equal([]byte{1}, client.GetProposer().PublicKey.Bytes()[:1])
+
+	backend.SetSequencers([][32]byte{{1}, {2}, {3}}, 2)
+	select {
+	case event := <-subscription.Out():
+		eventData := event.Data().(*settlement.EventDataSequencersListUpdated)
+		require.Len(eventData.Sequencers, 3)
+		require.Equal(types.Proposer, eventData.Sequencers[2].Status)
+		require.Equal(types.Inactive, eventData.Sequencers[0].Status)
+	case <-time.After(5 * time.Second):
+		require.FailNow("timed out waiting for sequencers list event")
+	}
+	require.Eventually(func() bool {
+		proposer := client.GetProposer()
+		return proposer != nil && proposer.PublicKey.Bytes()[0] == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTransactionSignature(t *testing.T) {
+	require := require.New(t)
+	rawKey, err := hex.DecodeString("4646464646464646464646464646464646464646464646464646464646464646")
+	require.NoError(err)
+	key, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), rawKey)
+	// The address of the key of the EIP-155 example
+	require.Equal("0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", pubKeyAddress(pubKey).String())
+
+	tx := &transaction{Nonce: 9, GasPrice: 20000000000, Gas: 21000, To: testContract, Data: []byte("data")}
+	require.NoError(tx.sign(key, testChainID))
+	decoded, err := decodeTransaction(tx.encode())
+	require.NoError(err)
+	require.Equal(tx, decoded)
+	sender, err := decoded.sender(testChainID)
+	require.NoError(err)
+	require.Equal(pubKeyAddress(pubKey), sender)
+	_, err = decoded.sender(testChainID + 1)
+	require.Error(err)
+}
+
+// TestEIP155Example checks the signing and the encoding of transactions against the example of EIP-155.
+func TestEIP155Example(t *testing.T) {
+	require := require.New(t)
+	rawKey, err := hex.DecodeString("4646464646464646464646464646464646464646464646464646464646464646")
+	require.NoError(err)
+	key, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), rawKey)
+	to, err := ParseAddress("0x3535353535353535353535353535353535353535")
+	require.NoError(err)
+
+	tx := &transaction{Nonce: 9, GasPrice: 20000000000, Gas: 21000, To: to, Value: 1000000000000000000, Data: []byte{}}
+	require.Equal("daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53", hex.EncodeToString(tx.signingHash(1)))
+	require.NoError(tx.sign(key, 1))
+	require.Equal(uint64(37), tx.V)
+	require.Equal("28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276", hex.EncodeToString(tx.R))
+	require.Equal("67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83", hex.EncodeToString(tx.S))
+	signed := "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025" +
+		"a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276" +
+		"a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	require.Equal(signed, hex.EncodeToString(tx.encode()))
+
+	raw, err := hex.DecodeString(signed)
+	require.NoError(err)
+	decoded, err := decodeTransaction(raw)
+	require.NoError(err)
+	require.Equal(tx, decoded)
+	sender, err := decoded.sender(1)
+	require.NoError(err)
+	require.Equal(pubKeyAddress(pubKey), sender)
+}
+
+// TestRLPVectors checks the RLP encoding against the examples of the RLP specification.
+func TestRLPVectors(t *testing.T) {
+	lorem := "Lorem ipsum dolor sit amet, consectetur adipisicing elit"
+	cases := []struct {
+		name     string
+		encoded  []byte
+		expected string
+	}{
+		{"dog", rlpString([]byte("dog")), "83646f67"},
+		{"cat dog list", rlpList(rlpString([]byte("cat")), rlpString([]byte("dog"))), "c88363617483646f67"},
+		{"empty string", rlpString(nil), "80"},
+		{"empty list", rlpList(), "c0"},
+		{"zero", rlpUint(0), "80"},
+		{"single byte", rlpString([]byte{0x0f}), "0f"},
+		{"two bytes", rlpString([]byte{0x04, 0x00}), "820400"},
+		{"1024", rlpUint(1024), "820400"},
+		{"long string", rlpString([]byte(lorem)), "b838" + hex.EncodeToString([]byte(lorem))},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, hex.EncodeToString(c.encoded))
+		})
+	}
+
+	items, err := rlpDecodeList(rlpList(rlpString([]byte("cat")), rlpString([]byte(lorem)), rlpUint(0)))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("cat"), []byte(lorem), {}}, items)
+}
+
+// TestABIVectors checks the ABI encoding against the examples of the Solidity ABI specification.
+func TestABIVectors(t *testing.T) {
+	require := require.New(t)
+	require.Equal("c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(keccak256()))
+	require.Equal("a9059cbb", hex.EncodeToString(selector("transfer(address,uint256)")))
+	require.Equal("cdcd77c0", hex.EncodeToString(selector("baz(uint32,bool)")))
+	require.Equal("a5643bf2", hex.EncodeToString(selector("sam(bytes,bool,uint256[])")))
+
+	// sam("dave", true, [1, 2, 3]), bytes and bool being encoded as string and uint64
+	expected := "0000000000000000000000000000000000000000000000000000000000000060" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6461766500000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000003"
+	array := make([][32]byte, 3)
+	for i := range array {
+		array[i][31] = byte(i + 1)
+	}
+	encoded, err := abiEncode("dave", uint64(1), array)
+	require.NoError(err)
+	require.Equal(expected, hex.EncodeToString(encoded))
+
+	r := abiReader(encoded)
+	s, err := r.string(0)
+	require.NoError(err)
+	require.Equal("dave", s)
+	b, err := r.uint64(1)
+	require.NoError(err)
+	require.Equal(uint64(1), b)
+	decoded, err := r.bytes32Array(2)
+	require.NoError(err)
+	require.Equal(array, decoded)
+}
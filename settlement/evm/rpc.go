@@ -0,0 +1,180 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// rpcClient is a minimal Ethereum JSON-RPC client over HTTP.
+type rpcClient struct {
+	url    string
+	http   *http.Client
+	nextID uint64
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+// callMsg is the call object of eth_call and eth_estimateGas.
+type callMsg struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Data string `json:"data"`
+}
+
+// rpcLog is a log returned by eth_getLogs.
+type rpcLog struct {
+	Address     string   `json:"address"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber string   `json:"blockNumber"`
+	TxHash      string   `json:"transactionHash"`
+	LogIndex    string   `json:"logIndex"`
+	Removed     bool     `json:"removed"`
+}
+
+// logFilter is the filter object of eth_getLogs.
+type logFilter struct {
+	FromBlock string     `json:"fromBlock"`
+	ToBlock   string     `json:"toBlock"`
+	Address   string     `json:"address"`
+	Topics    [][]string `json:"topics,omitempty"`
+}
+
+// rpcReceipt is a transaction receipt returned by eth_getTransactionReceipt.
+type rpcReceipt struct {
+	TxHash      string `json:"transactionHash"`
+	BlockNumber string `json:"blockNumber"`
+	Status      string `json:"status"`
+	GasUsed     string `json:"gasUsed"`
+}
+
+func newRPCClient(url string, timeout time.Duration) *rpcClient {
+	return &rpcClient{url: url, http: &http.Client{Timeout: timeout}}
+}
+
+// call invokes the method and decodes its result into result, unless result is nil.
+func (c *rpcClient) call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: atomic.AddUint64(&c.nextID, 1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", method, res.Status)
+	}
+	var resp rpcResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// callUint64 invokes a method returning a quantity.
+func (c *rpcClient) callUint64(ctx context.Context, method string, params ...interface{}) (uint64, error) {
+	var result string
+	if err := c.call(ctx, &result, method, params...); err != nil {
+		return 0, err
+	}
+	return decodeUint64(result)
+}
+
+// callBytes invokes a method returning data.
+func (c *rpcClient) callBytes(ctx context.Context, method string, params ...interface{}) ([]byte, error) {
+	var result string
+	if err := c.call(ctx, &result, method, params...); err != nil {
+		return nil, err
+	}
+	return decodeBytes(result)
+}
+
+func encodeUint64(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+func decodeUint64(s string) (uint64, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return strconv.ParseUint(s[2:], 16, 64)
+}
+
+func encodeBytes(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeBytes(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("invalid data %q", s)
+	}
+	return hex.DecodeString(s[2:])
+}
+
+// Address is the address of an Ethereum account or contract.
+type Address [20]byte
+
+// ParseAddress parses a hex encoded address.
+func ParseAddress(s string) (Address, error) {
+	var address Address
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return address, err
+	}
+	if len(b) != len(address) {
+		return address, errors.New("address must be 20 bytes long")
+	}
+	copy(address[:], b)
+	return address, nil
+}
+
+// String returns the hex encoding of the address.
+func (a Address) String() string {
+	return encodeBytes(a[:])
+}
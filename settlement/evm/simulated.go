@@ -0,0 +1,364 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// SimulatedGasPrice is the gas price of the simulated backend.
+	SimulatedGasPrice = 1000000000
+	// SimulatedGas is the gas the simulated backend estimates for any transaction.
+	SimulatedGas = 1000000
+)
+
+// JSON-RPC error codes returned by the simulated backend.
+const (
+	rpcCodeInvalidParams  = -32602
+	rpcCodeMethodNotFound = -32601
+	rpcCodeServerError    = -32000
+)
+
+// SimulatedBackend is an in-process EVM node serving the Ethereum JSON-RPC API over HTTP, for tests. It runs the
+// state commitment contract at a single address and mines every transaction in its own block right away, unless it
+// pays less than the minimum gas price. Only the owner of the contract can update the state.
+type SimulatedBackend struct {
+	mu          sync.Mutex
+	chainID     uint64
+	contract    Address
+	owner       Address
+	blockNumber uint64
+	nonces      map[Address]uint64
+	states      []stateInfo
+	sequencers  [][32]byte
+	proposer    uint64
+	receipts    map[string]*rpcReceipt
+	logs        []rpcLog
+	minGasPrice uint64
+	// pending holds the transactions paying less than the minimum gas price, by sender
+	pending map[Address]*transaction
+}
+
+var _ http.Handler = &SimulatedBackend{}
+
+// NewSimulatedBackend returns a simulated backend for the given chain running the contract at the given address.
+func NewSimulatedBackend(chainID uint64, contract Address, owner Address) *SimulatedBackend {
+	return &SimulatedBackend{
+		chainID:  chainID,
+		contract: contract,
+		owner:    owner,
+		nonces:   make(map[Address]uint64),
+		receipts: make(map[string]*rpcReceipt),
+		pending:  make(map[Address]*transaction),
+	}
+}
+
+// SetMinGasPrice sets the minimum gas price of the transactions mined. Transactions paying less are kept pending, until
+// they are replaced by a transaction with the same nonce paying enough.
+func (b *SimulatedBackend) SetMinGasPrice(gasPrice uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.minGasPrice = gasPrice
+}
+
+// Mine mines the given number of empty blocks.
+func (b *SimulatedBackend) Mine(blocks uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blockNumber += blocks
+}
+
+// SetSequencers registers the sequencers, given by their ed25519 public keys, in the contract. proposer is the
+// position of the proposer in the list.
+func (b *SimulatedBackend) SetSequencers(pubKeys [][32]byte, proposer uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sequencers = pubKeys
+	b.proposer = proposer
+	b.blockNumber++
+	b.emitLog([][]byte{sequencersListUpdateTopic}, nil, encodeBytes(keccak256(abiWord(b.blockNumber))))
+}
+
+// ServeHTTP serves a JSON-RPC request.
+func (b *SimulatedBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	result, err := b.handle(req.Method, req.Params)
+	if err != nil {
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{Code: rpcCodeServerError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+	} else {
+		resp.Result, err = json.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (b *SimulatedBackend) handle(method string, params []json.RawMessage) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch method {
+	case "eth_chainId":
+		return encodeUint64(b.chainID), nil
+	case "eth_blockNumber":
+		return encodeUint64(b.blockNumber), nil
+	case "eth_gasPrice":
+		return encodeUint64(SimulatedGasPrice), nil
+	case "eth_estimateGas":
+		return encodeUint64(SimulatedGas), nil
+	case "eth_getTransactionCount":
+		var address string
+		if err := decodeParams(params, &address); err != nil {
+			return nil, err
+		}
+		account, err := ParseAddress(address)
+		if err != nil {
+			return nil, &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+		}
+		return encodeUint64(b.nonces[account]), nil
+	case "eth_sendRawTransaction":
+		var rawTx string
+		if err := decodeParams(params, &rawTx); err != nil {
+			return nil, err
+		}
+		return b.sendRawTransaction(rawTx)
+	case "eth_getTransactionReceipt":
+		var txHash string
+		if err := decodeParams(params, &txHash); err != nil {
+			return nil, err
+		}
+		return b.receipts[strings.ToLower(txHash)], nil
+	case "eth_call":
+		var msg callMsg
+		if err := decodeParams(params, &msg); err != nil {
+			return nil, err
+		}
+		return b.call(msg)
+	case "eth_getLogs":
+		var filter logFilter
+		if err := decodeParams(params, &filter); err != nil {
+			return nil, err
+		}
+		return b.getLogs(filter)
+	}
+	return nil, &rpcError{Code: rpcCodeMethodNotFound, Message: fmt.Sprintf("method %s not found", method)}
+}
+
+// decodeParams decodes the first param into param. The rest of the params, like block tags, are ignored.
+func decodeParams(params []json.RawMessage, param interface{}) error {
+	if len(params) == 0 {
+		return &rpcError{Code: rpcCodeInvalidParams, Message: "missing params"}
+	}
+	if err := json.Unmarshal(params[0], param); err != nil {
+		return &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (b *SimulatedBackend) sendRawTransaction(rawTx string) (string, error) {
+	raw, err := decodeBytes(rawTx)
+	if err != nil {
+		return "", &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+	}
+	tx, err := decodeTransaction(raw)
+	if err != nil {
+		return "", err
+	}
+	sender, err := tx.sender(b.chainID)
+	if err != nil {
+		return "", err
+	}
+	if tx.Nonce != b.nonces[sender] {
+		return "", fmt.Errorf("invalid nonce: got %d, expected %d", tx.Nonce, b.nonces[sender])
+	}
+	if tx.Gas < SimulatedGas {
+		return "", errors.New("intrinsic gas too low")
+	}
+	if tx.Value != 0 {
+		return "", errors.New("value transfers are not supported")
+	}
+	txHash := encodeBytes(tx.hash())
+	if pending, ok := b.pending[sender]; ok && tx.GasPrice*10 < pending.GasPrice*11 {
+		return "", errors.New("replacement transaction underpriced")
+	}
+	if tx.GasPrice < b.minGasPrice {
+		b.pending[sender] = tx
+		return txHash, nil
+	}
+	delete(b.pending, sender)
+	b.nonces[sender]++
+	b.blockNumber++
+	status := "0x1"
+	if tx.To == b.contract {
+		if err := b.execute(sender, tx.Data, txHash); err != nil {
+			status = "0x0"
+		}
+	}
+	b.receipts[txHash] = &rpcReceipt{
+		TxHash:      txHash,
+		BlockNumber: encodeUint64(b.blockNumber),
+		Status:      status,
+		GasUsed:     encodeUint64(SimulatedGas),
+	}
+	return txHash, nil
+}
+
+// execute runs a transaction sent to the contract. The contract state is untouched if an error is returned.
+func (b *SimulatedBackend) execute(sender Address, data []byte, txHash string) error {
+	if !bytes.HasPrefix(data, updateStateSelector) {
+		return errors.New("unknown function")
+	}
+	if sender != b.owner {
+		return errors.New("sender is not the owner")
+	}
+	call, err := unpackUpdateState(data[len(updateStateSelector):])
+	if err != nil {
+		return err
+	}
+	var expectedStartHeight uint64 = 1
+	if len(b.states) > 0 {
+		latest := b.states[len(b.states)-1]
+		expectedStartHeight = latest.StartHeight + latest.NumBlocks
+	}
+	if call.StartHeight != expectedStartHeight {
+		return errors.New("start height must follow the latest state")
+	}
+	if call.NumBlocks == 0 || uint64(len(call.StateRoots)) != call.NumBlocks ||
+		uint64(len(call.IntermediateStatesRoots)) != call.NumBlocks {
+		return errors.New("invalid number of blocks")
+	}
+	b.states = append(b.states, call.stateInfo)
+	logData, err := abiEncode(call.StartHeight, call.NumBlocks, call.DAPath)
+	if err != nil {
+		return err
+	}
+	b.emitLog([][]byte{stateUpdateTopic, abiWord(uint64(len(b.states)))}, logData, txHash)
+	return nil
+}
+
+// emitLog adds a log of the contract to the current block.
+func (b *SimulatedBackend) emitLog(topics [][]byte, data []byte, txHash string) {
+	encodedTopics := make([]string, len(topics))
+	for i, topic := range topics {
+		encodedTopics[i] = encodeBytes(topic)
+	}
+	b.logs = append(b.logs, rpcLog{
+		Address:     b.contract.String(),
+		Topics:      encodedTopics,
+		Data:        encodeBytes(data),
+		BlockNumber: encodeUint64(b.blockNumber),
+		TxHash:      txHash,
+		LogIndex:    encodeUint64(0),
+	})
+}
+
+func (b *SimulatedBackend) call(msg callMsg) (string, error) {
+	to, err := ParseAddress(msg.To)
+	if err != nil {
+		return "", &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+	}
+	data, err := decodeBytes(msg.Data)
+	if err != nil {
+		return "", &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+	}
+	if to != b.contract {
+		return encodeBytes(nil), nil
+	}
+	var result []byte
+	switch {
+	case bytes.HasPrefix(data, latestStateIndexSelector):
+		result = abiWord(uint64(len(b.states)))
+	case bytes.HasPrefix(data, stateInfoSelector):
+		index, err := abiReader(data[len(stateInfoSelector):]).uint64(0)
+		if err != nil {
+			return "", err
+		}
+		// Like a Solidity mapping, unknown indexes hold a zero state
+		var info stateInfo
+		if index > 0 && index <= uint64(len(b.states)) {
+			info = b.states[index-1]
+		}
+		result, err = abiEncode(info.StartHeight, info.NumBlocks, info.DAPath)
+		if err != nil {
+			return "", err
+		}
+	case bytes.HasPrefix(data, sequencersSelector):
+		result, err = abiEncode(b.sequencers, b.proposer)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", errors.New("execution reverted")
+	}
+	return encodeBytes(result), nil
+}
+
+func (b *SimulatedBackend) getLogs(filter logFilter) ([]rpcLog, error) {
+	fromBlock, err := decodeUint64(filter.FromBlock)
+	if err != nil {
+		return nil, &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+	}
+	toBlock, err := decodeUint64(filter.ToBlock)
+	if err != nil {
+		return nil, &rpcError{Code: rpcCodeInvalidParams, Message: err.Error()}
+	}
+	logs := []rpcLog{}
+	for _, l := range b.logs {
+		blockNumber, _ := decodeUint64(l.BlockNumber)
+		if blockNumber < fromBlock || blockNumber > toBlock {
+			continue
+		}
+		if filter.Address != "" && !strings.EqualFold(filter.Address, l.Address) {
+			continue
+		}
+		if !matchTopics(filter.Topics, l.Topics) {
+			continue
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+// matchTopics checks whether the topics of a log match the topics of a filter. Each position of the filter holds the
+// alternatives matching the topic at the same position, or none to match any topic.
+func matchTopics(filter [][]string, topics []string) bool {
+	if len(filter) > len(topics) {
+		return false
+	}
+	for i, alternatives := range filter {
+		if len(alternatives) == 0 {
+			continue
+		}
+		matched := false
+		for _, alternative := range alternatives {
+			if strings.EqualFold(alternative, topics[i]) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
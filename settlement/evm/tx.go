@@ -0,0 +1,244 @@
+package evm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// transaction is a legacy Ethereum transaction, signed with EIP-155 replay protection. Transactions sent by the
+// client don't transfer any value, and contract creations aren't supported.
+type transaction struct {
+	Nonce    uint64
+	GasPrice uint64
+	Gas      uint64
+	To       Address
+	Value    uint64
+	Data     []byte
+	V        uint64
+	R        []byte
+	S        []byte
+}
+
+// signingHash returns the hash signed by the sender of the transaction.
+func (tx *transaction) signingHash(chainID uint64) []byte {
+	return keccak256(rlpList(
+		rlpUint(tx.Nonce),
+		rlpUint(tx.GasPrice),
+		rlpUint(tx.Gas),
+		rlpString(tx.To[:]),
+		rlpUint(tx.Value),
+		rlpString(tx.Data),
+		rlpUint(chainID),
+		rlpUint(0),
+		rlpUint(0),
+	))
+}
+
+// sign signs the transaction with the key for the given chain.
+func (tx *transaction) sign(key *btcec.PrivateKey, chainID uint64) error {
+	sig, err := btcec.SignCompact(btcec.S256(), key, tx.signingHash(chainID), false)
+	if err != nil {
+		return err
+	}
+	// The compact signature is prefixed with 27 + the recovery id
+	tx.V = uint64(sig[0]-27) + 35 + 2*chainID
+	tx.R = trimLeadingZeros(sig[1:33])
+	tx.S = trimLeadingZeros(sig[33:65])
+	return nil
+}
+
+// sender recovers the address of the sender of the transaction.
+func (tx *transaction) sender(chainID uint64) (Address, error) {
+	recoveryID := tx.V - 35 - 2*chainID
+	if tx.V < 35+2*chainID || recoveryID > 1 {
+		return Address{}, fmt.Errorf("invalid signature for chain %d", chainID)
+	}
+	if len(tx.R) > 32 || len(tx.S) > 32 {
+		return Address{}, errors.New("invalid signature values")
+	}
+	sig := make([]byte, 65)
+	sig[0] = byte(27 + recoveryID)
+	copy(sig[33-len(tx.R):33], tx.R)
+	copy(sig[65-len(tx.S):], tx.S)
+	pubKey, _, err := btcec.RecoverCompact(btcec.S256(), sig, tx.signingHash(chainID))
+	if err != nil {
+		return Address{}, err
+	}
+	return pubKeyAddress(pubKey), nil
+}
+
+// encode returns the RLP encoding of the signed transaction.
+func (tx *transaction) encode() []byte {
+	return rlpList(
+		rlpUint(tx.Nonce),
+		rlpUint(tx.GasPrice),
+		rlpUint(tx.Gas),
+		rlpString(tx.To[:]),
+		rlpUint(tx.Value),
+		rlpString(tx.Data),
+		rlpUint(tx.V),
+		rlpString(tx.R),
+		rlpString(tx.S),
+	)
+}
+
+// hash returns the hash the transaction is identified by.
+func (tx *transaction) hash() []byte {
+	return keccak256(tx.encode())
+}
+
+// decodeTransaction decodes a signed legacy transaction.
+func decodeTransaction(b []byte) (*transaction, error) {
+	items, err := rlpDecodeList(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != 9 {
+		return nil, errors.New("not a legacy transaction")
+	}
+	var tx transaction
+	for i, n := range []*uint64{&tx.Nonce, &tx.GasPrice, &tx.Gas} {
+		if *n, err = decodeRLPUint(items[i]); err != nil {
+			return nil, err
+		}
+	}
+	if len(items[3]) != len(tx.To) {
+		return nil, errors.New("contract creations are not supported")
+	}
+	copy(tx.To[:], items[3])
+	if tx.Value, err = decodeRLPUint(items[4]); err != nil {
+		return nil, err
+	}
+	tx.Data = items[5]
+	if tx.V, err = decodeRLPUint(items[6]); err != nil {
+		return nil, err
+	}
+	tx.R = items[7]
+	tx.S = items[8]
+	return &tx, nil
+}
+
+// pubKeyAddress returns the address of the account of the public key.
+func pubKeyAddress(pubKey *btcec.PublicKey) Address {
+	var address Address
+	copy(address[:], keccak256(pubKey.SerializeUncompressed()[1:])[12:])
+	return address
+}
+
+func trimLeadingZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+func rlpUint(n uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	return rlpString(trimLeadingZeros(b[:]))
+}
+
+func rlpString(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(rlpLength(len(b), 0x80), b...)
+}
+
+func rlpList(items ...[]byte) []byte {
+	var payload []byte
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(rlpLength(len(payload), 0xc0), payload...)
+}
+
+func rlpLength(length int, offset byte) []byte {
+	if length < 56 {
+		return []byte{offset + byte(length)}
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(length))
+	lengthBytes := trimLeadingZeros(b[:])
+	return append([]byte{offset + 55 + byte(len(lengthBytes))}, lengthBytes...)
+}
+
+func decodeRLPUint(b []byte) (uint64, error) {
+	if len(b) > 8 || (len(b) > 0 && b[0] == 0) {
+		return 0, errors.New("invalid RLP integer")
+	}
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+// rlpDecodeList decodes a list of strings.
+func rlpDecodeList(b []byte) ([][]byte, error) {
+	isList, content, rest, err := rlpSplit(b)
+	if err != nil {
+		return nil, err
+	}
+	if !isList || len(rest) != 0 {
+		return nil, errors.New("invalid RLP list")
+	}
+	var items [][]byte
+	for len(content) > 0 {
+		var item []byte
+		isList, item, content, err = rlpSplit(content)
+		if err != nil {
+			return nil, err
+		}
+		if isList {
+			return nil, errors.New("unexpected nested RLP list")
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// rlpSplit splits the first RLP item off b. It returns whether the item is a list, its content and the rest of b.
+func rlpSplit(b []byte) (bool, []byte, []byte, error) {
+	if len(b) == 0 {
+		return false, nil, nil, errors.New("empty RLP input")
+	}
+	prefix := b[0]
+	switch {
+	case prefix < 0x80:
+		return false, b[:1], b[1:], nil
+	case prefix < 0xb8:
+		content, rest, err := rlpContent(b[1:], uint64(prefix-0x80))
+		return false, content, rest, err
+	case prefix < 0xc0:
+		content, rest, err := rlpLongContent(b[1:], int(prefix-0xb7))
+		return false, content, rest, err
+	case prefix < 0xf8:
+		content, rest, err := rlpContent(b[1:], uint64(prefix-0xc0))
+		return true, content, rest, err
+	default:
+		content, rest, err := rlpLongContent(b[1:], int(prefix-0xf7))
+		return true, content, rest, err
+	}
+}
+
+func rlpLongContent(b []byte, lengthSize int) ([]byte, []byte, error) {
+	if len(b) < lengthSize {
+		return nil, nil, errors.New("RLP input too short")
+	}
+	length, err := decodeRLPUint(b[:lengthSize])
+	if err != nil {
+		return nil, nil, err
+	}
+	return rlpContent(b[lengthSize:], length)
+}
+
+func rlpContent(b []byte, length uint64) ([]byte, []byte, error) {
+	if uint64(len(b)) < length {
+		return nil, nil, errors.New("RLP input too short")
+	}
+	return b[:length], b[length:], nil
+}
@@ -0,0 +1,168 @@
+package faulty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/tendermint/tendermint/libs/pubsub"
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
+
+	"github.com/dymensionxyz/dymint/da"
+	"github.com/dymensionxyz/dymint/faults"
+	"github.com/dymensionxyz/dymint/log"
+	"github.com/dymensionxyz/dymint/settlement"
+	"github.com/dymensionxyz/dymint/types"
+)
+
+const eventsSubscriber = "faultySettlementEvents"
+
+// LayerClient wraps another settlement layer client and injects faults into its calls and events. It is intended
+// only for testing how the node behaves with slow, flaky or lying settlement layers.
+type LayerClient struct {
+	inner       settlement.LayerClient
+	faults      *faults.Injector
+	logger      log.Logger
+	pubsub      *pubsub.Server
+	innerPubsub *pubsub.Server
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// Config contains configuration options for LayerClient.
+type Config struct {
+	faults.Config
+	// InnerConfig is passed as is to the wrapped client.
+	InnerConfig string `json:"inner_config"`
+}
+
+var _ settlement.LayerClient = &LayerClient{}
+
+// NewLayerClient returns a client injecting faults into the calls and events of inner client.
+func NewLayerClient(inner settlement.LayerClient) *LayerClient {
+	return &LayerClient{inner: inner}
+}
+
+// Init parses the faults configuration and initializes the wrapped client. The wrapped client publishes its events
+// to a private pubsub server, from which they are forwarded subject to the injected faults.
+func (f *LayerClient) Init(config []byte, pubsubServer *pubsub.Server, logger log.Logger, options ...settlement.Option) error {
+	var conf Config
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &conf); err != nil {
+			return err
+		}
+	}
+	f.faults = faults.NewInjector(conf.Config)
+	f.logger = logger
+	f.pubsub = pubsubServer
+	f.innerPubsub = pubsub.NewServer()
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	return f.inner.Init([]byte(conf.InnerConfig), f.innerPubsub, logger, options...)
+}
+
+// Start starts forwarding the events and the wrapped client.
+func (f *LayerClient) Start() error {
+	f.logger.Info("starting faulty settlement Layer Client", "faults", f.faults.Config())
+	if err := f.innerPubsub.Start(); err != nil {
+		return err
+	}
+	subscription, err := f.innerPubsub.Subscribe(f.ctx, eventsSubscriber, tmquery.Empty{}, 100)
+	if err != nil {
+		return err
+	}
+	go f.forwardEvents(subscription)
+	return f.inner.Start()
+}
+
+// Stop stops the wrapped client and the events forwarding.
+func (f *LayerClient) Stop() error {
+	err := f.inner.Stop()
+	f.cancel()
+	if stopErr := f.innerPubsub.Stop(); err == nil {
+		err = stopErr
+	}
+	return err
+}
+
+// SubmitBatch submits the batch to the wrapped client, unless a failure is injected.
+func (f *LayerClient) SubmitBatch(batch *types.Batch, daClient da.Client, daResult *da.ResultSubmitBatch) *settlement.ResultSubmitBatch {
+	if err := f.faults.Call(f.ctx); err != nil {
+		return &settlement.ResultSubmitBatch{BaseResult: settlement.BaseResult{Code: statusCode(err), Message: err.Error()}}
+	}
+	return f.inner.SubmitBatch(batch, daClient, daResult)
+}
+
+// RetrieveBatch retrieves the batch from the wrapped client, unless a failure is injected. The DA metadata of the
+// batch may be corrupted.
+func (f *LayerClient) RetrieveBatch(stateIndex ...uint64) (*settlement.ResultRetrieveBatch, error) {
+	if err := f.faults.Call(f.ctx); err != nil {
+		return nil, err
+	}
+	res, err := f.inner.RetrieveBatch(stateIndex...)
+	if err != nil || res == nil || res.Batch == nil || res.MetaData == nil || res.MetaData.DA == nil {
+		return res, err
+	}
+	if f.faults.Corrupt() {
+		f.logger.Debug("corrupting batch DA metadata", "startHeight", res.StartHeight, "daHeight", res.MetaData.DA.Height)
+		batch := *res.Batch
+		daMetaData := *res.MetaData.DA
+		daMetaData.Height++
+		batch.MetaData = &settlement.BatchMetaData{DA: &daMetaData}
+		res = &settlement.ResultRetrieveBatch{BaseResult: res.BaseResult, Batch: &batch}
+	}
+	return res, nil
+}
+
+// GetSequencersList returns the sequencers list of the wrapped client.
+func (f *LayerClient) GetSequencersList() []*types.Sequencer {
+	return f.inner.GetSequencersList()
+}
+
+// GetProposer returns the proposer of the wrapped client.
+func (f *LayerClient) GetProposer() *types.Sequencer {
+	return f.inner.GetProposer()
+}
+
+// forwardEvents forwards the events of the wrapped client, dropping, delaying or reordering some of them.
+func (f *LayerClient) forwardEvents(subscription *pubsub.Subscription) {
+	var held *pubsub.Message
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-subscription.Cancelled():
+			return
+		case msg := <-subscription.Out():
+			if f.faults.Drop() {
+				f.logger.Debug("dropping settlement event", "events", msg.Events())
+				continue
+			}
+			if held == nil && f.faults.Reorder() {
+				// the event is published after the next one
+				held = &msg
+				continue
+			}
+			if err := f.faults.Delay(f.ctx); err != nil {
+				return
+			}
+			f.publish(msg)
+			if held != nil {
+				f.publish(*held)
+				held = nil
+			}
+		}
+	}
+}
+
+func (f *LayerClient) publish(msg pubsub.Message) {
+	if err := f.pubsub.PublishWithEvents(f.ctx, msg.Data(), msg.Events()); err != nil {
+		f.logger.Error("failed to forward settlement event", "error", err)
+	}
+}
+
+func statusCode(err error) settlement.StatusCode {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return settlement.StatusTimeout
+	}
+	return settlement.StatusError
+}
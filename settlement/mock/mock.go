@@ -244,8 +244,9 @@ func (c *HubClient) convertBatchtoSettlementBatch(batch *types.Batch, daClient d
 		EndHeight:   batch.EndHeight,
 		MetaData: &settlement.BatchMetaData{
 			DA: &settlement.DAMetaData{
-				Height: daResult.DAHeight,
-				Client: daClient,
+				Height:  daResult.DAHeight,
+				Client:  daClient,
+				Chunked: daResult.Chunked,
 			},
 		},
 	}
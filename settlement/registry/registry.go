@@ -3,6 +3,8 @@ package registry
 import (
 	"github.com/dymensionxyz/dymint/settlement"
 	"github.com/dymensionxyz/dymint/settlement/dymension"
+	"github.com/dymensionxyz/dymint/settlement/evm"
+	"github.com/dymensionxyz/dymint/settlement/faulty"
 	"github.com/dymensionxyz/dymint/settlement/mock"
 )
 
@@ -14,12 +16,20 @@ const (
 	Mock Client = "mock"
 	// Dymension is a client for interacting with dymension settlement layer
 	Dymension Client = "dymension"
+	// FaultyMock is a mock client injecting faults, for testing the node against unreliable settlement layers
+	FaultyMock Client = "faulty-mock"
+	// EVM is a client for anchoring the rollapp to a state commitment contract on an EVM chain
+	EVM Client = "evm"
 )
 
 // A central registry for all Settlement Layer Clients
 var clients = map[Client]func() settlement.LayerClient{
 	Mock:      func() settlement.LayerClient { return &mock.SettlementLayerClient{} },
 	Dymension: func() settlement.LayerClient { return &dymension.LayerClient{} },
+	EVM:       func() settlement.LayerClient { return &evm.LayerClient{} },
+	FaultyMock: func() settlement.LayerClient {
+		return faulty.NewLayerClient(&mock.SettlementLayerClient{})
+	},
 }
 
 // GetClient returns client identified by name.
@@ -37,12 +37,20 @@ type DAMetaData struct {
 	Height uint64
 	// Client is the client to use to fetch data from the da layer
 	Client da.Client
+	// Chunked is true if the batch was split across several blobs, in which case Height points at the manifest blob
+	Chunked bool
 }
 
+// chunkedPathSuffix is appended to the path of a chunked batch.
+const chunkedPathSuffix = "manifest"
+
 // ToPath converts a DAMetaData to a path.
 func (d *DAMetaData) ToPath() string {
 	// convert uint64 to string
 	path := []string{string(d.Client), ".", strconv.FormatUint(d.Height, 10)}
+	if d.Chunked {
+		path = append(path, ".", chunkedPathSuffix)
+	}
 	return strings.Join(path, "")
 }
 
@@ -54,8 +62,9 @@ func (d *DAMetaData) FromPath(path string) (*DAMetaData, error) {
 		return nil, err
 	}
 	return &DAMetaData{
-		Height: height,
-		Client: da.Client(pathParts[0]),
+		Height:  height,
+		Client:  da.Client(pathParts[0]),
+		Chunked: len(pathParts) > 2 && pathParts[2] == chunkedPathSuffix,
 	}, nil
 }
 
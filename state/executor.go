@@ -17,6 +17,7 @@ import (
 	"go.uber.org/multierr"
 
 	abciconv "github.com/dymensionxyz/dymint/conv/abci"
+	"github.com/dymensionxyz/dymint/fraudproof"
 	"github.com/dymensionxyz/dymint/log"
 	"github.com/dymensionxyz/dymint/mempool"
 	"github.com/dymensionxyz/dymint/types"
@@ -30,6 +31,7 @@ type BlockExecutor struct {
 	proxyAppConsensusConn proxy.AppConnConsensus
 	proxyAppQueryConn     proxy.AppConnQuery
 	mempool               mempool.Mempool
+	fraudProofsEnabled    bool
 
 	eventBus *tmtypes.EventBus
 
@@ -38,7 +40,8 @@ type BlockExecutor struct {
 
 // NewBlockExecutor creates new instance of BlockExecutor.
 // Proposer address and namespace ID will be used in all newly created blocks.
-func NewBlockExecutor(proposerAddress []byte, namespaceID [8]byte, chainID string, mempool mempool.Mempool, proxyApp proxy.AppConns, eventBus *tmtypes.EventBus, logger log.Logger) *BlockExecutor {
+// In case fraud proofs are enabled, intermediate state roots are generated while executing blocks.
+func NewBlockExecutor(proposerAddress []byte, namespaceID [8]byte, chainID string, mempool mempool.Mempool, proxyApp proxy.AppConns, eventBus *tmtypes.EventBus, fraudProofsEnabled bool, logger log.Logger) *BlockExecutor {
 	return &BlockExecutor{
 		proposerAddress:       proposerAddress,
 		namespaceID:           namespaceID,
@@ -46,6 +49,7 @@ func NewBlockExecutor(proposerAddress []byte, namespaceID [8]byte, chainID strin
 		proxyAppConsensusConn: proxyApp.Consensus(),
 		proxyAppQueryConn:     proxyApp.Query(),
 		mempool:               mempool,
+		fraudProofsEnabled:    fraudProofsEnabled,
 		eventBus:              eventBus,
 		logger:                logger,
 	}
@@ -118,13 +122,14 @@ func (e *BlockExecutor) CreateBlock(height uint64, lastCommit *types.Commit, las
 		LastCommit: *lastCommit,
 	}
 	copy(block.Header.LastCommitHash[:], e.getLastCommitHash(lastCommit, &block.Header))
-	copy(block.Header.DataHash[:], e.getDataHash(block))
+	block.Header.DataHash = block.Data.Hash()
 	copy(block.Header.AggregatorsHash[:], state.Validators.Hash())
 
 	return block
 }
 
-// ApplyBlock validates and executes the block.
+// ApplyBlock validates and executes a block signed by the proposer. In case fraud proofs are enabled, the
+// intermediate state roots the block commits to are checked against the execution.
 func (e *BlockExecutor) ApplyBlock(ctx context.Context, state types.State, block *types.Block, commit *types.Commit, proposer *types.Sequencer) (types.State, *tmstate.ABCIResponses, error) {
 	err := e.validateBlock(state, block)
 	if err != nil {
@@ -135,13 +140,43 @@ func (e *BlockExecutor) ApplyBlock(ctx context.Context, state types.State, block
 		return types.State{}, nil, err
 	}
 	// This makes calls to the ProxyApp
-	resp, err := e.execute(ctx, state, block)
+	resp, _, err := e.execute(ctx, state, block)
+	if err != nil {
+		var fraudErr *fraudproof.FraudError
+		if errors.As(err, &fraudErr) {
+			fraudErr.Proof.Commit = commit
+		}
+		return types.State{}, nil, err
+	}
+
+	return e.nextState(state, block, resp)
+}
+
+// ExecuteBlock validates and executes a block created by this node, before it's signed. In case fraud proofs are
+// enabled, the intermediate state roots of the execution are set in the block and its data hash is updated to commit
+// to them, so the block must be signed only afterwards.
+func (e *BlockExecutor) ExecuteBlock(ctx context.Context, state types.State, block *types.Block) (types.State, *tmstate.ABCIResponses, error) {
+	err := e.validateBlock(state, block)
+	if err != nil {
+		return types.State{}, nil, err
+	}
+	// This makes calls to the ProxyApp
+	resp, isrs, err := e.execute(ctx, state, block)
 	if err != nil {
 		return types.State{}, nil, err
 	}
+	if e.fraudProofsEnabled {
+		block.Data.IntermediateStateRoots.RawRootsList = isrs
+		block.Header.DataHash = block.Data.Hash()
+	}
+
+	return e.nextState(state, block, resp)
+}
 
+// nextState returns the state resulting from the execution of the block.
+func (e *BlockExecutor) nextState(state types.State, block *types.Block, resp *tmstate.ABCIResponses) (types.State, *tmstate.ABCIResponses, error) {
 	abciValUpdates := resp.EndBlock.ValidatorUpdates
-	err = validateValidatorUpdates(abciValUpdates, state.ConsensusParams.Validator)
+	err := validateValidatorUpdates(abciValUpdates, state.ConsensusParams.Validator)
 	if err != nil {
 		return state, nil, fmt.Errorf("error in validator updates: %v", err)
 	}
@@ -294,7 +329,9 @@ func (e *BlockExecutor) validateCommit(proposer *types.Sequencer, commit *types.
 	return nil
 }
 
-func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *types.Block) (*tmstate.ABCIResponses, error) {
+// execute runs the block on the app and returns the responses and, in case fraud proofs are enabled, the
+// intermediate state roots of the execution.
+func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *types.Block) (*tmstate.ABCIResponses, [][]byte, error) {
 	abciResponses := new(tmstate.ABCIResponses)
 	abciResponses.DeliverTxs = make([]*abci.ResponseDeliverTx, len(block.Data.Txs))
 
@@ -303,6 +340,16 @@ func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *t
 	invalidTxs := 0
 
 	var err error
+	var isrs [][]byte
+	// delivered is notified once the response of each DeliverTx is received
+	delivered := make(chan struct{}, len(block.Data.Txs))
+
+	if e.fraudProofsEnabled {
+		claimedISRs := block.Data.IntermediateStateRoots.RawRootsList
+		if claimedISRs != nil && len(claimedISRs) != len(block.Data.Txs)+2 {
+			return nil, nil, fmt.Errorf("%w: expected %d roots, got %d", types.ErrInvalidISR, len(block.Data.Txs)+2, len(claimedISRs))
+		}
+	}
 
 	e.proxyAppConsensusConn.SetResponseCallback(func(req *abci.Request, res *abci.Response) {
 		if r, ok := res.Value.(*abci.Response_DeliverTx); ok {
@@ -315,40 +362,97 @@ func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *t
 			}
 			abciResponses.DeliverTxs[txIdx] = txRes
 			txIdx++
+			delivered <- struct{}{}
 		}
 	})
 
-	hash := block.Hash()
-	abciHeader := abciconv.ToABCIHeaderPB(&block.Header)
+	// The app executes the block as it was before the intermediate state roots were committed to, since they
+	// result from the execution. The block is thus executed the same way by the sequencer and the full nodes.
+	executedHeader := block.Header
+	executedHeader.DataHash = (&types.Data{Txs: block.Data.Txs}).Hash()
+	hash := executedHeader.Hash()
+	abciHeader := abciconv.ToABCIHeaderPB(&executedHeader)
 	abciHeader.ChainID = e.chainID
 	abciHeader.ValidatorsHash = state.Validators.Hash()
-	abciResponses.BeginBlock, err = e.proxyAppConsensusConn.BeginBlockSync(
-		abci.RequestBeginBlock{
-			Hash:   hash[:],
-			Header: abciHeader,
-			LastCommitInfo: abci.LastCommitInfo{
-				Round: 0,
-				Votes: nil,
-			},
-			ByzantineValidators: nil,
-		})
+	beginBlockRequest := abci.RequestBeginBlock{
+		Hash:   hash[:],
+		Header: abciHeader,
+		LastCommitInfo: abci.LastCommitInfo{
+			Round: 0,
+			Votes: nil,
+		},
+		ByzantineValidators: nil,
+	}
+	abciResponses.BeginBlock, err = e.proxyAppConsensusConn.BeginBlockSync(beginBlockRequest)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if e.fraudProofsEnabled {
+		isrs, err = e.checkIntermediateStateRoot(block, isrs, abci.ToRequestBeginBlock(beginBlockRequest))
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	for _, tx := range block.Data.Txs {
-		res := e.proxyAppConsensusConn.DeliverTxAsync(abci.RequestDeliverTx{Tx: tx})
+		deliverTxRequest := abci.RequestDeliverTx{Tx: tx}
+		res := e.proxyAppConsensusConn.DeliverTxAsync(deliverTxRequest)
 		if res.GetException() != nil {
-			return nil, errors.New(res.GetException().GetError())
+			return nil, nil, errors.New(res.GetException().GetError())
+		}
+		if e.fraudProofsEnabled {
+			// The state root must be queried only once the tx was delivered
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			case <-delivered:
+			}
+			isrs, err = e.checkIntermediateStateRoot(block, isrs, abci.ToRequestDeliverTx(deliverTxRequest))
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 	}
 
-	abciResponses.EndBlock, err = e.proxyAppConsensusConn.EndBlockSync(abci.RequestEndBlock{Height: int64(block.Header.Height)})
+	endBlockRequest := abci.RequestEndBlock{Height: int64(block.Header.Height)}
+	abciResponses.EndBlock, err = e.proxyAppConsensusConn.EndBlockSync(endBlockRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.fraudProofsEnabled {
+		isrs, err = e.checkIntermediateStateRoot(block, isrs, abci.ToRequestEndBlock(endBlockRequest))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return abciResponses, isrs, nil
+}
+
+// checkIntermediateStateRoot queries the app for its state root after the given request was executed and appends
+// it to the given roots. In case the block has intermediate state roots, i.e. it was created by the sequencer,
+// the root is compared with the block's, and a fraud proof is generated on mismatch.
+func (e *BlockExecutor) checkIntermediateStateRoot(block *types.Block, isrs [][]byte, request *abci.Request) ([][]byte, error) {
+	resp, err := e.proxyAppQueryConn.QuerySync(abci.RequestQuery{Path: types.ISRQueryPath})
 	if err != nil {
 		return nil, err
 	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("failed to get intermediate state root from app: %s", resp.Log)
+	}
+	isrs = append(isrs, resp.Value)
 
-	return abciResponses, nil
+	claimedISRs := block.Data.IntermediateStateRoots.RawRootsList
+	stepIndex := len(isrs) - 1
+	if claimedISRs == nil || bytes.Equal(claimedISRs[stepIndex], resp.Value) {
+		return isrs, nil
+	}
+	e.logger.Error("Intermediate state root mismatch, generating fraud proof", "height", block.Header.Height, "step", stepIndex)
+	proof, err := fraudproof.Generate(e.proxyAppQueryConn, block, stepIndex, request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mismatch at step %d, failed to generate fraud proof: %v", types.ErrInvalidISR, stepIndex, err)
+	}
+	return nil, &fraudproof.FraudError{Proof: proof}
 }
 
 func (e *BlockExecutor) getLastCommitHash(lastCommit *types.Commit, header *types.Header) []byte {
@@ -356,13 +460,6 @@ func (e *BlockExecutor) getLastCommitHash(lastCommit *types.Commit, header *type
 	return lastABCICommit.Hash()
 }
 
-func (e *BlockExecutor) getDataHash(block *types.Block) []byte {
-	abciData := tmtypes.Data{
-		Txs: abciconv.ToABCIBlockDataTxs(&block.Data),
-	}
-	return abciData.Hash()
-}
-
 func (e *BlockExecutor) publishEvents(resp *tmstate.ABCIResponses, block *types.Block, state types.State) error {
 	if e.eventBus == nil {
 		return nil
@@ -22,6 +22,7 @@ import (
 	"github.com/dymensionxyz/dymint/mempool"
 	mempoolv1 "github.com/dymensionxyz/dymint/mempool/v1"
 	"github.com/dymensionxyz/dymint/mocks"
+	"github.com/dymensionxyz/dymint/testutil"
 	"github.com/dymensionxyz/dymint/types"
 )
 
@@ -43,7 +44,7 @@ func TestCreateBlock(t *testing.T) {
 	nsID := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
 
 	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(abciClient), 0)
-	executor := NewBlockExecutor([]byte("test address"), nsID, "test", mpool, proxy.NewAppConns(clientCreator), nil, logger)
+	executor := NewBlockExecutor([]byte("test address"), nsID, "test", mpool, proxy.NewAppConns(clientCreator), nil, false, logger)
 
 	state := types.State{}
 	state.ConsensusParams.Block.MaxBytes = 100
@@ -116,7 +117,7 @@ func TestApplyBlock(t *testing.T) {
 	appConns := &mocks.AppConns{}
 	appConns.On("Consensus").Return(abciClient)
 	appConns.On("Query").Return(abciClient)
-	executor := NewBlockExecutor([]byte("test address"), nsID, chainID, mpool, appConns, eventBus, logger)
+	executor := NewBlockExecutor([]byte("test address"), nsID, chainID, mpool, appConns, eventBus, false, logger)
 
 	// Subscribe to tx events
 	txQuery, err := query.New("tm.event='Tx'")
@@ -256,3 +257,89 @@ func TestApplyBlock(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyBlockWithISRs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+	nsID := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	proposerKey := ed25519.GenPrivKey()
+	proposer := &types.Sequencer{
+		PublicKey: proposerKey.PubKey(),
+	}
+
+	getExecutor := func() (*BlockExecutor, mempool.Mempool) {
+		clientCreator := proxy.NewLocalClientCreator(testutil.GetAppMockWithISRs())
+		abciClient, err := clientCreator.NewABCIClient()
+		require.NoError(err)
+		mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(abciClient), 0)
+		appConns := &mocks.AppConns{}
+		appConns.On("Consensus").Return(abciClient)
+		appConns.On("Query").Return(abciClient)
+		return NewBlockExecutor([]byte("test address"), nsID, "test", mpool, appConns, nil, true, logger), mpool
+	}
+
+	state := types.State{
+		NextValidators: tmtypes.NewValidatorSet(nil),
+		Validators:     tmtypes.NewValidatorSet(nil),
+		LastValidators: tmtypes.NewValidatorSet(nil),
+	}
+	state.InitialHeight = 1
+	state.LastBlockHeight = 0
+	state.ConsensusParams.Block.MaxBytes = 100
+	state.ConsensusParams.Block.MaxGas = 100000
+
+	sign := func(block *types.Block) *types.Commit {
+		abciHeaderPb := abciconv.ToABCIHeaderPB(&block.Header)
+		abciHeaderBytes, err := abciHeaderPb.Marshal()
+		require.NoError(err)
+		signature, err := proposerKey.Sign(abciHeaderBytes)
+		require.NoError(err)
+		return &types.Commit{
+			Height:     block.Header.Height,
+			HeaderHash: block.Header.Hash(),
+			Signatures: []types.Signature{signature},
+		}
+	}
+
+	// Create a block with two Txs
+	executor, mpool := getExecutor()
+	require.NoError(mpool.CheckTx([]byte{1, 2, 3, 4}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	require.NoError(mpool.CheckTx([]byte{5, 6, 7, 8}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	block := executor.CreateBlock(1, &types.Commit{Height: 0}, [32]byte{}, state)
+	require.Len(block.Data.Txs, 2)
+	assert.Nil(block.Data.IntermediateStateRoots.RawRootsList)
+	txsDataHash := block.Header.DataHash
+
+	// The ISRs are set after BeginBlock, each DeliverTx and EndBlock, and committed to by the data hash
+	_, _, err := executor.ExecuteBlock(context.Background(), state, block)
+	require.NoError(err)
+	isrs := block.Data.IntermediateStateRoots.RawRootsList
+	require.Len(isrs, 4)
+	for i := 1; i < len(isrs); i++ {
+		assert.NotEqual(isrs[i-1], isrs[i])
+	}
+	assert.NotEqual(txsDataHash, block.Header.DataHash)
+	assert.Equal(block.Data.Hash(), block.Header.DataHash)
+	commit := sign(block)
+
+	// Another node executing the block gets the same ISRs
+	verifier, _ := getExecutor()
+	_, _, err = verifier.ApplyBlock(context.Background(), state, block, commit, proposer)
+	require.NoError(err)
+
+	// ISRs tampered after the block was signed don't match the signed data hash
+	verifier, _ = getExecutor()
+	tampered := *block
+	tampered.Data.IntermediateStateRoots.RawRootsList = append([][]byte{}, isrs...)
+	tampered.Data.IntermediateStateRoots.RawRootsList[2] = make([]byte, 32)
+	_, _, err = verifier.ApplyBlock(context.Background(), state, &tampered, commit, proposer)
+	assert.Error(err)
+	assert.NotErrorIs(err, types.ErrInvalidISR)
+
+	// Wrong ISRs signed by the sequencer are detected
+	tampered.Header.DataHash = tampered.Data.Hash()
+	_, _, err = verifier.ApplyBlock(context.Background(), state, &tampered, sign(&tampered), proposer)
+	assert.ErrorIs(err, types.ErrInvalidISR)
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync/atomic"
@@ -22,6 +23,8 @@ var (
 	statePrefix      = [1]byte{4}
 	responsesPrefix  = [1]byte{5}
 	validatorsPrefix = [1]byte{6}
+	submissionPrefix = [1]byte{7}
+	settledPrefix    = [1]byte{8}
 )
 
 // DefaultStore is a default store implmementation.
@@ -244,6 +247,75 @@ func (s *DefaultStore) LoadValidators(height uint64) (*tmtypes.ValidatorSet, err
 	return tmtypes.ValidatorSetFromProto(&pbValSet)
 }
 
+// SaveBatchSubmission saves the submission state of a batch, keyed by the batch start height.
+func (s *DefaultStore) SaveBatchSubmission(submission *types.BatchSubmission, batch Batch) (Batch, error) {
+	blob, err := json.Marshal(submission)
+	if err != nil {
+		return batch, fmt.Errorf("failed to marshal batch submission: %w", err)
+	}
+
+	if batch == nil {
+		return nil, s.db.Set(getSubmissionKey(submission.StartHeight), blob)
+	}
+	err = batch.Set(getSubmissionKey(submission.StartHeight), blob)
+	return batch, err
+}
+
+// LoadBatchSubmissions returns all the batch submissions saved in Store, ordered by start height.
+func (s *DefaultStore) LoadBatchSubmissions() ([]*types.BatchSubmission, error) {
+	iter := s.db.PrefixIterator(submissionPrefix[:])
+	defer iter.Discard()
+
+	submissions := []*types.BatchSubmission{}
+	for ; iter.Valid(); iter.Next() {
+		submission := new(types.BatchSubmission)
+		if err := json.Unmarshal(iter.Value(), submission); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal batch submission: %w", err)
+		}
+		submissions = append(submissions, submission)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate batch submissions: %w", err)
+	}
+	return submissions, nil
+}
+
+// DeleteBatchSubmission deletes the submission state of the batch starting at the given height.
+func (s *DefaultStore) DeleteBatchSubmission(startHeight uint64, batch Batch) (Batch, error) {
+	if batch == nil {
+		return nil, s.db.Delete(getSubmissionKey(startHeight))
+	}
+	err := batch.Delete(getSubmissionKey(startHeight))
+	return batch, err
+}
+
+// SaveSettledHeight saves the height of the last block accepted by the SL.
+func (s *DefaultStore) SaveSettledHeight(height uint64, batch Batch) (Batch, error) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, height)
+
+	if batch == nil {
+		return nil, s.db.Set(getSettledHeightKey(), buf)
+	}
+	err := batch.Set(getSettledHeightKey(), buf)
+	return batch, err
+}
+
+// LoadSettledHeight returns the height saved with SaveSettledHeight, or 0 if no height was settled yet.
+func (s *DefaultStore) LoadSettledHeight() (uint64, error) {
+	blob, err := s.db.Get(getSettledHeightKey())
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load settled height: %w", err)
+	}
+	if len(blob) != 8 {
+		return 0, errors.New("invalid settled height length")
+	}
+	return binary.BigEndian.Uint64(blob), nil
+}
+
 func (s *DefaultStore) loadHashFromIndex(height uint64) ([32]byte, error) {
 	blob, err := s.db.Get(getIndexKey(height))
 
@@ -287,3 +359,13 @@ func getValidatorsKey(height uint64) []byte {
 	binary.BigEndian.PutUint64(buf, height)
 	return append(validatorsPrefix[:], buf[:]...)
 }
+
+func getSubmissionKey(startHeight uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, startHeight)
+	return append(submissionPrefix[:], buf[:]...)
+}
+
+func getSettledHeightKey() []byte {
+	return settledPrefix[:]
+}
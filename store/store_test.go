@@ -239,6 +239,71 @@ func TestBatch(t *testing.T) {
 	assert.Equal(expected, resp)
 }
 
+func TestBatchSubmissions(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv := NewDefaultInMemoryKVStore()
+	s := New(kv)
+
+	submissions, err := s.LoadBatchSubmissions()
+	require.NoError(err)
+	assert.Empty(submissions)
+
+	// Save out of order to check the submissions are loaded ordered by start height
+	expected := []*types.BatchSubmission{
+		{StartHeight: 1, EndHeight: 10, Status: types.BatchSubmissionSubmittedToSL},
+		{StartHeight: 11, EndHeight: 300, Status: types.BatchSubmissionPostedToDA, DAHeight: 7, DAMessage: "tx hash: 0A1B"},
+		{StartHeight: 301, EndHeight: 310, Status: types.BatchSubmissionCreated},
+	}
+	for _, i := range []int{2, 0, 1} {
+		_, err = s.SaveBatchSubmission(expected[i], nil)
+		require.NoError(err)
+	}
+	submissions, err = s.LoadBatchSubmissions()
+	require.NoError(err)
+	assert.Equal(expected, submissions)
+
+	// Update and delete within a batch
+	batch := s.NewBatch()
+	batch, err = s.DeleteBatchSubmission(1, batch)
+	require.NoError(err)
+	expected[1].Status = types.BatchSubmissionSubmittedToSL
+	batch, err = s.SaveBatchSubmission(expected[1], batch)
+	require.NoError(err)
+	require.NoError(batch.Commit())
+
+	submissions, err = s.LoadBatchSubmissions()
+	require.NoError(err)
+	assert.Equal(expected[1:], submissions)
+}
+
+func TestSettledHeight(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := New(NewDefaultInMemoryKVStore())
+
+	height, err := s.LoadSettledHeight()
+	require.NoError(err)
+	assert.Zero(height)
+
+	_, err = s.SaveSettledHeight(10, nil)
+	require.NoError(err)
+	batch, err := s.SaveSettledHeight(20, s.NewBatch())
+	require.NoError(err)
+	height, err = s.LoadSettledHeight()
+	require.NoError(err)
+	assert.Equal(uint64(10), height)
+
+	require.NoError(batch.Commit())
+	height, err = s.LoadSettledHeight()
+	require.NoError(err)
+	assert.Equal(uint64(20), height)
+}
+
 func getRandomBlock(height uint64, nTxs int) *types.Block {
 	block := &types.Block{
 		Header: types.Header{
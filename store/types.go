@@ -48,4 +48,19 @@ type Store interface {
 	SaveValidators(height uint64, validatorSet *tmtypes.ValidatorSet, batch Batch) (Batch, error)
 
 	LoadValidators(height uint64) (*tmtypes.ValidatorSet, error)
+
+	// SaveBatchSubmission saves the submission state of a batch, keyed by the batch start height.
+	SaveBatchSubmission(submission *types.BatchSubmission, batch Batch) (Batch, error)
+
+	// LoadBatchSubmissions returns all the batch submissions saved in Store, ordered by start height.
+	LoadBatchSubmissions() ([]*types.BatchSubmission, error)
+
+	// DeleteBatchSubmission deletes the submission state of the batch starting at the given height.
+	DeleteBatchSubmission(startHeight uint64, batch Batch) (Batch, error)
+
+	// SaveSettledHeight saves the height of the last block accepted by the SL.
+	SaveSettledHeight(height uint64, batch Batch) (Batch, error)
+
+	// LoadSettledHeight returns the height saved with SaveSettledHeight, or 0 if no height was settled yet.
+	LoadSettledHeight() (uint64, error)
 }
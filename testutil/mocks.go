@@ -1,7 +1,14 @@
 package testutil
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
 	"github.com/dymensionxyz/dymint/mocks"
+	"github.com/dymensionxyz/dymint/types"
 	"github.com/stretchr/testify/mock"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
@@ -33,3 +40,103 @@ func GetAppMock() *mocks.Application {
 
 	return app
 }
+
+// GetAppMockWithISRs returns a dummy abci app mock which supports the intermediate state root and fraud proof queries.
+// The state root is a hash chain over the executed BeginBlock heights, txs and EndBlocks.
+func GetAppMockWithISRs() *mocks.Application {
+	return getAppMockWithISRs(false)
+}
+
+// GetFaultyAppMockWithISRs returns a dummy abci app mock like GetAppMockWithISRs, which computes wrong
+// state roots for txs. It's used for testing fraud proofs of a faulty sequencer.
+func GetFaultyAppMockWithISRs() *mocks.Application {
+	return getAppMockWithISRs(true)
+}
+
+// stateTransition returns the state root resulting from executing the given request on top of the given root.
+func stateTransition(root []byte, req *abci.Request, faulty bool) []byte {
+	var data []byte
+	switch r := req.Value.(type) {
+	case *abci.Request_BeginBlock:
+		data = make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(r.BeginBlock.Header.Height))
+	case *abci.Request_DeliverTx:
+		data = append([]byte{}, r.DeliverTx.Tx...)
+		if faulty {
+			data = append(data, 0)
+		}
+	case *abci.Request_EndBlock:
+		data = []byte("end")
+	}
+	hash := sha256.Sum256(append(append([]byte{}, root...), data...))
+	return hash[:]
+}
+
+func getAppMockWithISRs(faulty bool) *mocks.Application {
+	var mtx sync.Mutex
+	root := make([]byte, 32)
+	prevRoot := make([]byte, 32)
+	update := func(req *abci.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		prevRoot = root
+		root = stateTransition(root, req, faulty)
+	}
+	getRoot := func() []byte {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]byte{}, root...)
+	}
+	getPrevRoot := func() []byte {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]byte{}, prevRoot...)
+	}
+
+	app := &mocks.Application{}
+	app.On("InitChain", mock.Anything).Return(abci.ResponseInitChain{})
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	app.On("BeginBlock", mock.Anything).Run(func(args mock.Arguments) {
+		update(abci.ToRequestBeginBlock(args.Get(0).(abci.RequestBeginBlock)))
+	}).Return(abci.ResponseBeginBlock{})
+	app.On("DeliverTx", mock.Anything).Run(func(args mock.Arguments) {
+		update(abci.ToRequestDeliverTx(args.Get(0).(abci.RequestDeliverTx)))
+	}).Return(abci.ResponseDeliverTx{})
+	app.On("EndBlock", mock.Anything).Run(func(args mock.Arguments) {
+		update(abci.ToRequestEndBlock(args.Get(0).(abci.RequestEndBlock)))
+	}).Return(abci.ResponseEndBlock{})
+	app.On("Commit", mock.Anything).Return(func() abci.ResponseCommit {
+		return abci.ResponseCommit{Data: getRoot()}
+	})
+	app.On("Query", mock.Anything).Return(func(req abci.RequestQuery) abci.ResponseQuery {
+		switch req.Path {
+		case types.ISRQueryPath:
+			return abci.ResponseQuery{Value: getRoot()}
+		case types.FraudProofWitnessesQueryPath:
+			// The only witness needed for executing the last step is the previous root
+			witnesses, err := json.Marshal([][]byte{getPrevRoot()})
+			if err != nil {
+				return abci.ResponseQuery{Code: 1, Log: err.Error()}
+			}
+			return abci.ResponseQuery{Value: witnesses}
+		case types.StateTransitionQueryPath:
+			var transition types.StateTransition
+			if err := json.Unmarshal(req.Data, &transition); err != nil {
+				return abci.ResponseQuery{Code: 1, Log: err.Error()}
+			}
+			if len(transition.Witnesses) != 1 || !bytes.Equal(transition.Witnesses[0], transition.PreStateRoot) {
+				return abci.ResponseQuery{Code: 1, Log: "witnesses don't match pre state root"}
+			}
+			var request abci.Request
+			if err := request.Unmarshal(transition.Request); err != nil {
+				return abci.ResponseQuery{Code: 1, Log: err.Error()}
+			}
+			return abci.ResponseQuery{Value: stateTransition(transition.PreStateRoot, &request, false)}
+		default:
+			return abci.ResponseQuery{Code: 1, Log: "unknown query path"}
+		}
+	})
+	app.On("Info", mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 0, LastBlockAppHash: []byte{0}})
+
+	return app
+}
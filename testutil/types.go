@@ -54,7 +54,6 @@ func GenerateBlocks(startHeight uint64, num uint64, proposerKey crypto.PrivKey)
 				Time:           4567,
 				LastHeaderHash: h[0],
 				LastCommitHash: h[1],
-				DataHash:       h[2],
 				ConsensusHash:  h[3],
 				// AppHash:         h[4],
 				AppHash:         [32]byte{},
@@ -73,6 +72,7 @@ func GenerateBlocks(startHeight uint64, num uint64, proposerKey crypto.PrivKey)
 				Signatures: []types.Signature{},
 			},
 		}
+		block.Header.DataHash = block.Data.Hash()
 		signature, err := generateSignature(proposerKey, &block.Header)
 		if err != nil {
 			return nil, err
@@ -8,3 +8,44 @@ type Batch struct {
 	Blocks      []*Block
 	Commits     []*Commit
 }
+
+// BatchSubmissionStatus is the status of a batch in the submission process.
+type BatchSubmissionStatus uint8
+
+const (
+	// BatchSubmissionCreated means the batch was created but not yet posted to the DA layer.
+	BatchSubmissionCreated BatchSubmissionStatus = iota
+	// BatchSubmissionPostedToDA means the batch was posted to the DA layer but not yet submitted to the SL.
+	BatchSubmissionPostedToDA
+	// BatchSubmissionSubmittedToSL means the batch was submitted to the SL and is waiting to be accepted.
+	BatchSubmissionSubmittedToSL
+	// BatchSubmissionAccepted means the batch was accepted by the SL.
+	BatchSubmissionAccepted
+)
+
+func (s BatchSubmissionStatus) String() string {
+	switch s {
+	case BatchSubmissionCreated:
+		return "created"
+	case BatchSubmissionPostedToDA:
+		return "posted-to-da"
+	case BatchSubmissionSubmittedToSL:
+		return "submitted-to-sl"
+	case BatchSubmissionAccepted:
+		return "accepted"
+	default:
+		return "unknown"
+	}
+}
+
+// BatchSubmission is the submission state of a batch which is persisted so the submission can be resumed after a restart.
+type BatchSubmission struct {
+	StartHeight uint64
+	EndHeight   uint64
+	Status      BatchSubmissionStatus
+	// DAHeight, DAMessage and DAChunked are the result of posting the batch to the DA layer. They are set once the
+	// batch was posted, so the batch isn't posted again after a restart.
+	DAHeight  uint64
+	DAMessage string
+	DAChunked bool
+}
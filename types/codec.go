@@ -0,0 +1,180 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// BatchCodec is the compression codec used for a batch blob posted to the DA layer.
+type BatchCodec uint8
+
+const (
+	// BatchCodecNone means the serialized batch is not compressed.
+	BatchCodecNone BatchCodec = iota
+	// BatchCodecZstd means the serialized batch is compressed with zstd.
+	BatchCodecZstd
+	// BatchCodecSnappy means the serialized batch is compressed with snappy.
+	BatchCodecSnappy
+)
+
+// DefaultBatchCodec is the codec used by DA clients when no codec is configured.
+const DefaultBatchCodec = BatchCodecZstd
+
+// MaxBatchSizeBytes is the max size of a serialized batch. Blobs are never decompressed beyond it, so a small blob
+// can't make a node allocate an arbitrary amount of memory. It is above the max size of a block, so a batch can
+// always hold a single block.
+const MaxBatchSizeBytes = 128 << 20
+
+// batchEnvelopeVersion is the current version of the batch blob envelope.
+const batchEnvelopeVersion = 1
+
+// batchEnvelopeMagic prefixes every enveloped batch blob. The first byte (0x44) is a protobuf end-group tag, which
+// can never start a serialized batch, so enveloped blobs can be told apart from legacy raw protobuf blobs.
+var batchEnvelopeMagic = []byte{'D', 'Y', 'M'}
+
+// batchEnvelopeHeaderLen is the length of magic, version and codec bytes.
+var batchEnvelopeHeaderLen = len(batchEnvelopeMagic) + 2
+
+var (
+	// ErrUnknownBatchCodec is returned when a batch blob uses an unknown codec.
+	ErrUnknownBatchCodec = errors.New("unknown batch codec")
+	// ErrUnknownBatchEnvelopeVersion is returned when a batch blob uses an unknown envelope version.
+	ErrUnknownBatchEnvelopeVersion = errors.New("unknown batch envelope version")
+	// ErrBatchTooLarge is returned when a batch blob decompresses to more than MaxBatchSizeBytes.
+	ErrBatchTooLarge = errors.New("batch too large")
+)
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxBatchSizeBytes))
+)
+
+func (c BatchCodec) String() string {
+	switch c {
+	case BatchCodecNone:
+		return "none"
+	case BatchCodecZstd:
+		return "zstd"
+	case BatchCodecSnappy:
+		return "snappy"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c BatchCodec) MarshalText() ([]byte, error) {
+	if c.String() == "unknown" {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownBatchCodec, c)
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *BatchCodec) UnmarshalText(text []byte) error {
+	codec, err := ParseBatchCodec(string(text))
+	if err != nil {
+		return err
+	}
+	*c = codec
+	return nil
+}
+
+// ParseBatchCodec returns the codec with the given name. Empty name selects the DefaultBatchCodec.
+func ParseBatchCodec(name string) (BatchCodec, error) {
+	switch name {
+	case "":
+		return DefaultBatchCodec, nil
+	case "none":
+		return BatchCodecNone, nil
+	case "zstd":
+		return BatchCodecZstd, nil
+	case "snappy":
+		return BatchCodecSnappy, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownBatchCodec, name)
+	}
+}
+
+// EncodeBatch serializes the batch and wraps it in a versioned envelope, compressing it with the given codec.
+func EncodeBatch(batch *Batch, codec BatchCodec) ([]byte, error) {
+	raw, err := batch.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	switch codec {
+	case BatchCodecNone:
+		payload = raw
+	case BatchCodecZstd:
+		payload = zstdEncoder.EncodeAll(raw, nil)
+	case BatchCodecSnappy:
+		payload = snappy.Encode(nil, raw)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownBatchCodec, codec)
+	}
+
+	blob := make([]byte, 0, batchEnvelopeHeaderLen+len(payload))
+	blob = append(blob, batchEnvelopeMagic...)
+	blob = append(blob, batchEnvelopeVersion, byte(codec))
+	blob = append(blob, payload...)
+	return blob, nil
+}
+
+// DecodeBatch decodes a batch blob written by EncodeBatch. Legacy blobs holding the raw serialized batch are
+// decoded as well.
+func DecodeBatch(blob []byte) (*Batch, error) {
+	raw := blob
+	if bytes.HasPrefix(blob, batchEnvelopeMagic) {
+		if len(blob) < batchEnvelopeHeaderLen {
+			return nil, errors.New("batch envelope too short")
+		}
+		version := blob[len(batchEnvelopeMagic)]
+		if version != batchEnvelopeVersion {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownBatchEnvelopeVersion, version)
+		}
+		codec := BatchCodec(blob[len(batchEnvelopeMagic)+1])
+		payload := blob[batchEnvelopeHeaderLen:]
+
+		var err error
+		switch codec {
+		case BatchCodecNone:
+			raw = payload
+		case BatchCodecZstd:
+			raw, err = zstdDecoder.DecodeAll(payload, nil)
+			if errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+				return nil, ErrBatchTooLarge
+			}
+		case BatchCodecSnappy:
+			raw, err = decodeSnappy(payload)
+		default:
+			return nil, fmt.Errorf("%w: %d", ErrUnknownBatchCodec, codec)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decompress batch: %w", err)
+		}
+	}
+
+	batch := new(Batch)
+	if err := batch.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// decodeSnappy decompresses the snappy payload, unless the decoded length in its header exceeds MaxBatchSizeBytes.
+func decodeSnappy(payload []byte) ([]byte, error) {
+	decodedLen, err := snappy.DecodedLen(payload)
+	if err != nil {
+		return nil, err
+	}
+	if decodedLen > MaxBatchSizeBytes {
+		return nil, ErrBatchTooLarge
+	}
+	return snappy.Decode(nil, payload)
+}
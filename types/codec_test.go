@@ -0,0 +1,112 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchCodecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	batch := &Batch{
+		StartHeight: 1,
+		EndHeight:   2,
+		Blocks: []*Block{
+			{Header: Header{Height: 1}, Data: Data{Txs: Txs{make(Tx, 1024)}}},
+			{Header: Header{Height: 2}, Data: Data{Txs: Txs{make(Tx, 1024)}}},
+		},
+		Commits: []*Commit{{Height: 1}, {Height: 2}},
+	}
+	raw, err := batch.MarshalBinary()
+	require.NoError(t, err)
+
+	for _, codec := range []BatchCodec{BatchCodecNone, BatchCodecZstd, BatchCodecSnappy} {
+		codec := codec
+		t.Run(codec.String(), func(t *testing.T) {
+			blob, err := EncodeBatch(batch, codec)
+			require.NoError(t, err)
+			if codec != BatchCodecNone {
+				assert.Less(t, len(blob), len(raw))
+			}
+
+			decoded, err := DecodeBatch(blob)
+			require.NoError(t, err)
+			assert.Equal(t, batch.StartHeight, decoded.StartHeight)
+			assert.Equal(t, batch.EndHeight, decoded.EndHeight)
+			assert.Equal(t, batch.Blocks, decoded.Blocks)
+		})
+	}
+
+	t.Run("legacy", func(t *testing.T) {
+		decoded, err := DecodeBatch(raw)
+		require.NoError(t, err)
+		assert.Equal(t, batch.Blocks, decoded.Blocks)
+	})
+
+	t.Run("unknown codec", func(t *testing.T) {
+		blob, err := EncodeBatch(batch, BatchCodecNone)
+		require.NoError(t, err)
+		blob[len(batchEnvelopeMagic)+1] = 0xff
+		_, err = DecodeBatch(blob)
+		assert.ErrorIs(t, err, ErrUnknownBatchCodec)
+	})
+}
+
+func TestDecodeBatchTooLarge(t *testing.T) {
+	t.Parallel()
+
+	header := append(append([]byte{}, batchEnvelopeMagic...), batchEnvelopeVersion)
+
+	t.Run("zstd", func(t *testing.T) {
+		// A stream without content size, so the size is only known while decompressing
+		var payload bytes.Buffer
+		encoder, err := zstd.NewWriter(&payload)
+		require.NoError(t, err)
+		_, err = io.CopyN(encoder, zeroReader{}, MaxBatchSizeBytes+1)
+		require.NoError(t, err)
+		require.NoError(t, encoder.Close())
+		require.Less(t, payload.Len(), 1<<20)
+
+		_, err = DecodeBatch(append(append(header, byte(BatchCodecZstd)), payload.Bytes()...))
+		assert.ErrorIs(t, err, ErrBatchTooLarge)
+	})
+
+	t.Run("snappy", func(t *testing.T) {
+		// The header claims a decoded length above the limit
+		payload := make([]byte, binary.MaxVarintLen64+16)
+		binary.PutUvarint(payload, MaxBatchSizeBytes+1)
+		_, err := DecodeBatch(append(append(header, byte(BatchCodecSnappy)), payload...))
+		assert.ErrorIs(t, err, ErrBatchTooLarge)
+	})
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestParseBatchCodec(t *testing.T) {
+	t.Parallel()
+
+	codec, err := ParseBatchCodec("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBatchCodec, codec)
+
+	codec, err = ParseBatchCodec("snappy")
+	require.NoError(t, err)
+	assert.Equal(t, BatchCodecSnappy, codec)
+
+	_, err = ParseBatchCodec("lz4")
+	assert.ErrorIs(t, err, ErrUnknownBatchCodec)
+}
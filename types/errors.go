@@ -5,4 +5,6 @@ import "errors"
 var (
 	// ErrInvalidSignature is returned when a signature is invalid.
 	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidISR is returned when the intermediate state roots of a block don't match the executed ones.
+	ErrInvalidISR = errors.New("invalid intermediate state roots")
 )
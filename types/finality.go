@@ -0,0 +1,35 @@
+package types
+
+import (
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Finality is the finality level of a block.
+type Finality string
+
+const (
+	// FinalitySoftConfirmed means the block was produced by the sequencer but not yet accepted by the SL.
+	FinalitySoftConfirmed Finality = "soft_confirmed"
+	// FinalitySettled means the block was posted to the DA layer and the batch containing it was accepted by the SL.
+	FinalitySettled Finality = "settled"
+)
+
+// GetFinality returns the finality level of the block at the given height, given the last settled height.
+func GetFinality(height uint64, settledHeight uint64) Finality {
+	if height <= settledHeight {
+		return FinalitySettled
+	}
+	return FinalitySoftConfirmed
+}
+
+// EventNewSettledHeight is published on the event bus once a new height is settled.
+const EventNewSettledHeight = "NewSettledHeight"
+
+// EventDataNewSettledHeight defines the structure of the event data for the EventNewSettledHeight
+type EventDataNewSettledHeight struct {
+	// Height is the height of the last settled block
+	Height uint64 `json:"height"`
+}
+
+// EventQueryNewSettledHeight is the query used for getting EventNewSettledHeight
+var EventQueryNewSettledHeight = tmtypes.QueryForEvent(EventNewSettledHeight)
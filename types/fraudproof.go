@@ -0,0 +1,28 @@
+package types
+
+// The ABCI queries below extend the ABCI interface for apps which support fraud proofs.
+const (
+	// ISRQueryPath is the path of the ABCI query used to get the intermediate state root of the app, i.e. the root of
+	// the app state after the last BeginBlock, DeliverTx or EndBlock call, before the state is committed.
+	// Apps which support fraud proofs must respond to this query with the root in the value of the response.
+	ISRQueryPath = "/dymint/intermediate_state_root"
+
+	// FraudProofWitnessesQueryPath is the path of the ABCI query used to get the witnesses of the app state before
+	// the last BeginBlock, DeliverTx or EndBlock call. The data of the query is the proto encoded abci.Request of
+	// that call, and the response value is the JSON encoded list of witnesses.
+	FraudProofWitnessesQueryPath = "/dymint/fraud_proof_witnesses"
+
+	// StateTransitionQueryPath is the path of the ABCI query used to execute a single state transition on top
+	// of the witnesses of the pre-state. The data of the query is the JSON encoded StateTransition, and the
+	// response value is the state root after the transition.
+	StateTransitionQueryPath = "/dymint/state_transition"
+)
+
+// StateTransition is a single step of a block execution, i.e. BeginBlock, DeliverTx or EndBlock, applied on top
+// of a pre-state which is given by its root and the witnesses of the state accessed by the step.
+type StateTransition struct {
+	PreStateRoot []byte
+	Witnesses    [][]byte
+	// Request is the proto encoded abci.Request of the step
+	Request []byte
+}
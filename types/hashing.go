@@ -3,6 +3,7 @@ package types
 import (
 	"time"
 
+	"github.com/tendermint/tendermint/crypto/merkle"
 	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
 	tmtypes "github.com/tendermint/tendermint/types"
 )
@@ -43,3 +44,32 @@ func (h *Header) Hash() [32]byte {
 func (b *Block) Hash() [32]byte {
 	return b.Header.Hash()
 }
+
+// Hash returns the hash of the block data the header commits to. Without intermediate state roots it's the
+// ABCI-compatible hash of the txs. Otherwise the root of the intermediate state roots is hashed along, so the
+// signature of the header covers the state roots claimed by the sequencer.
+func (d *Data) Hash() [32]byte {
+	txs := make(tmtypes.Txs, len(d.Txs))
+	for i := range d.Txs {
+		txs[i] = tmtypes.Tx(d.Txs[i])
+	}
+	txsHash := txs.Hash()
+	var hash [32]byte
+	if len(d.IntermediateStateRoots.RawRootsList) == 0 {
+		copy(hash[:], txsHash)
+		return hash
+	}
+	isrRoot := d.IntermediateStateRoots.Root()
+	copy(hash[:], merkle.HashFromByteSlices([][]byte{txsHash, isrRoot[:]}))
+	return hash
+}
+
+// Root returns the root of the merkle tree built from the intermediate state roots.
+// In case there are no intermediate state roots, a zero root is returned.
+func (isr *IntermediateStateRoots) Root() [32]byte {
+	var root [32]byte
+	if len(isr.RawRootsList) > 0 {
+		copy(root[:], merkle.HashFromByteSlices(isr.RawRootsList))
+	}
+	return root
+}
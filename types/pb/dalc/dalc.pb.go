@@ -120,6 +120,8 @@ func (m *DAResponse) GetDataLayerHeight() uint64 {
 
 type SubmitBatchRequest struct {
 	Batch *dymint.Batch `protobuf:"bytes,1,opt,name=batch,proto3" json:"batch,omitempty"`
+	// blob is the batch encoded into a DA blob envelope. Servers submit it as is when it is set.
+	Blob []byte `protobuf:"bytes,2,opt,name=blob,proto3" json:"blob,omitempty"`
 }
 
 func (m *SubmitBatchRequest) Reset()         { *m = SubmitBatchRequest{} }
@@ -162,6 +164,13 @@ func (m *SubmitBatchRequest) GetBatch() *dymint.Batch {
 	return nil
 }
 
+func (m *SubmitBatchRequest) GetBlob() []byte {
+	if m != nil {
+		return m.Blob
+	}
+	return nil
+}
+
 type SubmitBatchResponse struct {
 	Result *DAResponse `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 }
@@ -349,6 +358,8 @@ func (m *RetrieveBatchesRequest) GetDataLayerHeight() uint64 {
 type RetrieveBatchesResponse struct {
 	Result  *DAResponse     `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 	Batches []*dymint.Batch `protobuf:"bytes,2,rep,name=batches,proto3" json:"batches,omitempty"`
+	// blobs are the DA blobs of the batches, for servers which don't decode them.
+	Blobs [][]byte `protobuf:"bytes,3,rep,name=blobs,proto3" json:"blobs,omitempty"`
 }
 
 func (m *RetrieveBatchesResponse) Reset()         { *m = RetrieveBatchesResponse{} }
@@ -398,6 +409,13 @@ func (m *RetrieveBatchesResponse) GetBatches() []*dymint.Batch {
 	return nil
 }
 
+func (m *RetrieveBatchesResponse) GetBlobs() [][]byte {
+	if m != nil {
+		return m.Blobs
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("dalc.StatusCode", StatusCode_name, StatusCode_value)
 	proto.RegisterType((*DAResponse)(nil), "dalc.DAResponse")
@@ -412,40 +430,42 @@ func init() {
 func init() { proto.RegisterFile("types/dalc/dalc.proto", fileDescriptor_c4ac81039d3c0899) }
 
 var fileDescriptor_c4ac81039d3c0899 = []byte{
-	// 527 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xc1, 0x6e, 0xda, 0x40,
-	0x10, 0x86, 0x31, 0xd0, 0xa4, 0x1d, 0x94, 0x84, 0x6c, 0x94, 0xe0, 0xd0, 0xc6, 0x42, 0x4e, 0xaa,
-	0xa2, 0xa8, 0xa2, 0x12, 0x3d, 0xf5, 0x54, 0x11, 0xdb, 0x51, 0x51, 0xd3, 0x12, 0xad, 0xe1, 0xd2,
-	0x0b, 0xb2, 0xcd, 0x08, 0xac, 0x1a, 0x4c, 0xbc, 0x0b, 0x0a, 0x7d, 0x86, 0x1e, 0xfa, 0x58, 0x3d,
-	0xe6, 0xd8, 0x63, 0x05, 0x2f, 0x52, 0x79, 0xd7, 0x6e, 0x68, 0x20, 0x91, 0xd2, 0x8b, 0xed, 0x9d,
-	0x6f, 0x67, 0xe6, 0x1f, 0xcf, 0x68, 0x60, 0x9f, 0xcf, 0xc6, 0xc8, 0xde, 0xf4, 0x9c, 0xc0, 0x13,
-	0x8f, 0xda, 0x38, 0x0a, 0x79, 0x48, 0xf2, 0xf1, 0x77, 0xf9, 0x30, 0x81, 0xb3, 0xa1, 0x3f, 0xe2,
-	0xc9, 0x4b, 0x5e, 0xd0, 0xaf, 0x01, 0xcc, 0x06, 0x45, 0x36, 0x0e, 0x47, 0x0c, 0xc9, 0x09, 0xe4,
-	0xbd, 0xb0, 0x87, 0xaa, 0x52, 0x51, 0xaa, 0xdb, 0xf5, 0x62, 0x4d, 0x44, 0xb2, 0xb9, 0xc3, 0x27,
-	0xcc, 0x08, 0x7b, 0x48, 0x05, 0x25, 0x2a, 0x6c, 0x0e, 0x91, 0x31, 0xa7, 0x8f, 0x6a, 0xb6, 0xa2,
-	0x54, 0x9f, 0xd1, 0xf4, 0x48, 0x4e, 0x61, 0xb7, 0xe7, 0x70, 0xa7, 0x1b, 0x38, 0x33, 0x8c, 0xba,
-	0x03, 0xf4, 0xfb, 0x03, 0xae, 0xe6, 0x2a, 0x4a, 0x35, 0x4f, 0x77, 0x62, 0x70, 0x11, 0xdb, 0x3f,
-	0x08, 0xb3, 0xfe, 0x0e, 0x88, 0x3d, 0x71, 0x87, 0x3e, 0x3f, 0x73, 0xb8, 0x37, 0xa0, 0x78, 0x35,
-	0x41, 0xc6, 0xc9, 0x31, 0x3c, 0x71, 0xe3, 0xb3, 0x90, 0x50, 0xa8, 0x6f, 0xd5, 0x12, 0xb5, 0xf2,
-	0x92, 0x64, 0xfa, 0x7b, 0xd8, 0xfb, 0xc7, 0x35, 0x51, 0x5f, 0x85, 0x8d, 0x08, 0xd9, 0x24, 0xe0,
-	0x89, 0x73, 0xa2, 0xff, 0xb6, 0x3e, 0x9a, 0x70, 0xfd, 0x23, 0x1c, 0x19, 0x03, 0xf4, 0xbe, 0x0a,
-	0xff, 0xc6, 0xd4, 0xf1, 0x03, 0xc7, 0xf5, 0x03, 0x9f, 0xcf, 0x52, 0x19, 0x6b, 0x0b, 0x51, 0xd6,
-	0x17, 0x72, 0x05, 0xda, 0x7d, 0xc1, 0x1e, 0x2b, 0x8c, 0xbc, 0x84, 0x6d, 0x91, 0xd7, 0x91, 0x61,
-	0x02, 0xf9, 0x87, 0x9f, 0xd2, 0xad, 0xd8, 0xda, 0x48, 0x8d, 0xba, 0x09, 0x07, 0x14, 0x79, 0xe4,
-	0xe3, 0x14, 0x45, 0x56, 0x64, 0xff, 0x23, 0x3c, 0x80, 0xd2, 0x4a, 0x94, 0x47, 0x2b, 0x7e, 0x05,
-	0x9b, 0xae, 0x74, 0x56, 0xb3, 0x95, 0xdc, 0x6a, 0xcb, 0x52, 0x7a, 0x1a, 0x01, 0xdc, 0x4e, 0x12,
-	0x79, 0x0e, 0x25, 0xbb, 0xdd, 0x68, 0x77, 0xec, 0xae, 0xd1, 0x32, 0xad, 0x6e, 0xe7, 0xb3, 0x7d,
-	0x69, 0x19, 0xcd, 0xf3, 0xa6, 0x65, 0x16, 0x33, 0xa4, 0x04, 0x7b, 0xcb, 0xd0, 0xee, 0x18, 0x86,
-	0x65, 0xdb, 0x45, 0xe5, 0x2e, 0x68, 0x37, 0x3f, 0x59, 0xad, 0x4e, 0xbb, 0x98, 0x25, 0xfb, 0xb0,
-	0xbb, 0x0c, 0x2c, 0x4a, 0x5b, 0xb4, 0x98, 0xab, 0x7f, 0xcf, 0x42, 0xc1, 0x6c, 0x5c, 0x18, 0x36,
-	0x46, 0x53, 0xdf, 0x43, 0x62, 0x42, 0x61, 0x69, 0x70, 0x88, 0x9a, 0x0c, 0xf8, 0xca, 0x18, 0x96,
-	0x0f, 0xd7, 0x10, 0x59, 0xb8, 0x9e, 0x21, 0x08, 0x07, 0xeb, 0x1b, 0x4e, 0x8e, 0xa5, 0xdb, 0x83,
-	0xb3, 0x55, 0x3e, 0x79, 0xf8, 0xd2, 0xdf, 0x34, 0x97, 0xb0, 0x73, 0xa7, 0x3d, 0xe4, 0x85, 0x74,
-	0x5d, 0xdf, 0xfb, 0xf2, 0xd1, 0x3d, 0x34, 0x8d, 0x78, 0x76, 0xfe, 0x73, 0xae, 0x29, 0x37, 0x73,
-	0x4d, 0xf9, 0x3d, 0xd7, 0x94, 0x1f, 0x0b, 0x2d, 0x73, 0xb3, 0xd0, 0x32, 0xbf, 0x16, 0x5a, 0xe6,
-	0xcb, 0xeb, 0xbe, 0xcf, 0x07, 0x13, 0xb7, 0xe6, 0x85, 0xc3, 0x78, 0x3f, 0xe0, 0x88, 0xf9, 0xe1,
-	0xe8, 0x7a, 0xf6, 0x2d, 0xdd, 0x19, 0x72, 0x81, 0x8c, 0x5d, 0xb1, 0x5b, 0xdc, 0x0d, 0xb1, 0x3b,
-	0xde, 0xfe, 0x09, 0x00, 0x00, 0xff, 0xff, 0x50, 0x1d, 0xc0, 0x3d, 0x75, 0x04, 0x00, 0x00,
+	// 555 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4f, 0x6f, 0xda, 0x4e,
+	0x10, 0xc5, 0x40, 0x92, 0xdf, 0x6f, 0xf2, 0x8f, 0x6c, 0x9a, 0xc4, 0xa1, 0x8d, 0x85, 0x9c, 0x54,
+	0x45, 0x51, 0x45, 0x25, 0xfa, 0x01, 0x2a, 0x62, 0x3b, 0x2a, 0x6a, 0x52, 0xa2, 0x35, 0x5c, 0x7a,
+	0x41, 0xb6, 0x19, 0x81, 0x55, 0x83, 0x89, 0x77, 0x41, 0xa1, 0xc7, 0x9e, 0x7b, 0xe8, 0xc7, 0xea,
+	0x31, 0xc7, 0x1e, 0x2b, 0xf8, 0x22, 0x95, 0x77, 0xed, 0x86, 0x06, 0x12, 0x29, 0xbd, 0xc0, 0xee,
+	0xbc, 0x9d, 0x37, 0x6f, 0x76, 0x9f, 0x07, 0xf6, 0xf8, 0x64, 0x88, 0xec, 0x4d, 0xc7, 0x09, 0x3c,
+	0xf1, 0x53, 0x19, 0x46, 0x21, 0x0f, 0x49, 0x3e, 0x5e, 0x17, 0x0f, 0x13, 0x70, 0xd2, 0xf7, 0x07,
+	0x3c, 0xf9, 0x93, 0x07, 0xf4, 0x1b, 0x00, 0xb3, 0x46, 0x91, 0x0d, 0xc3, 0x01, 0x43, 0x72, 0x02,
+	0x79, 0x2f, 0xec, 0xa0, 0xaa, 0x94, 0x94, 0xf2, 0x56, 0xb5, 0x50, 0x11, 0x4c, 0x36, 0x77, 0xf8,
+	0x88, 0x19, 0x61, 0x07, 0xa9, 0x40, 0x89, 0x0a, 0x6b, 0x7d, 0x64, 0xcc, 0xe9, 0xa2, 0x9a, 0x2d,
+	0x29, 0xe5, 0xff, 0x69, 0xba, 0x25, 0xa7, 0xb0, 0xd3, 0x71, 0xb8, 0xd3, 0x0e, 0x9c, 0x09, 0x46,
+	0xed, 0x1e, 0xfa, 0xdd, 0x1e, 0x57, 0x73, 0x25, 0xa5, 0x9c, 0xa7, 0xdb, 0x31, 0x70, 0x11, 0xc7,
+	0xdf, 0x8b, 0xb0, 0x7e, 0x09, 0xc4, 0x1e, 0xb9, 0x7d, 0x9f, 0x9f, 0x39, 0xdc, 0xeb, 0x51, 0xbc,
+	0x1e, 0x21, 0xe3, 0xe4, 0x18, 0x56, 0xdc, 0x78, 0x2f, 0x24, 0xac, 0x57, 0x37, 0x2b, 0x89, 0x5a,
+	0x79, 0x48, 0x62, 0x84, 0x40, 0xde, 0x0d, 0x42, 0x57, 0x54, 0xdf, 0xa0, 0x62, 0xad, 0xbf, 0x83,
+	0xdd, 0xbf, 0xe8, 0x92, 0x8e, 0xca, 0xb0, 0x1a, 0x21, 0x1b, 0x05, 0x3c, 0x21, 0x4c, 0x7a, 0xba,
+	0xeb, 0x99, 0x26, 0xb8, 0xfe, 0x01, 0x8e, 0x8c, 0x1e, 0x7a, 0x9f, 0x45, 0x7e, 0x6d, 0xec, 0xf8,
+	0x81, 0xe3, 0xfa, 0x81, 0xcf, 0x27, 0xa9, 0xb4, 0xa5, 0xcd, 0x29, 0xcb, 0x9b, 0xbb, 0x06, 0xed,
+	0x21, 0xb2, 0xa7, 0x0a, 0x23, 0x2f, 0x61, 0x4b, 0xd4, 0x75, 0x24, 0x4d, 0x20, 0x6f, 0xfd, 0x3f,
+	0xba, 0x19, 0x47, 0x6b, 0x69, 0x50, 0x37, 0x61, 0x9f, 0x22, 0x8f, 0x7c, 0x1c, 0xa3, 0xa8, 0x8a,
+	0xec, 0x5f, 0x84, 0x7f, 0x55, 0xe0, 0x60, 0x81, 0xe6, 0xc9, 0x92, 0x5f, 0xc1, 0x9a, 0x2b, 0x93,
+	0xd5, 0x6c, 0x29, 0xb7, 0xf8, 0x8e, 0x29, 0x4a, 0x9e, 0xc1, 0x4a, 0xfc, 0x7a, 0x4c, 0xcd, 0x95,
+	0x72, 0xe5, 0x0d, 0x2a, 0x37, 0xa7, 0x11, 0xc0, 0x9d, 0xe9, 0xc8, 0x73, 0x38, 0xb0, 0x9b, 0xb5,
+	0x66, 0xcb, 0x6e, 0x1b, 0x0d, 0xd3, 0x6a, 0xb7, 0x3e, 0xda, 0x57, 0x96, 0x51, 0x3f, 0xaf, 0x5b,
+	0x66, 0x21, 0x43, 0x0e, 0x60, 0x77, 0x1e, 0xb4, 0x5b, 0x86, 0x61, 0xd9, 0x76, 0x41, 0xb9, 0x0f,
+	0x34, 0xeb, 0x97, 0x56, 0xa3, 0xd5, 0x2c, 0x64, 0xc9, 0x1e, 0xec, 0xcc, 0x03, 0x16, 0xa5, 0x0d,
+	0x5a, 0xc8, 0x55, 0xbf, 0x65, 0x61, 0xdd, 0xac, 0x5d, 0x18, 0x36, 0x46, 0x63, 0xdf, 0x43, 0x62,
+	0xc2, 0xfa, 0x9c, 0x9f, 0x88, 0x9a, 0x7c, 0x0b, 0x0b, 0x8e, 0x2d, 0x1e, 0x2e, 0x41, 0xe4, 0x75,
+	0xe8, 0x19, 0x82, 0xb0, 0xbf, 0xdc, 0x07, 0xe4, 0x58, 0xa6, 0x3d, 0x6a, 0xb9, 0xe2, 0xc9, 0xe3,
+	0x87, 0xfe, 0x94, 0xb9, 0x82, 0xed, 0x7b, 0x8f, 0x46, 0x5e, 0xc8, 0xd4, 0xe5, 0x96, 0x28, 0x1e,
+	0x3d, 0x80, 0xa6, 0x8c, 0x67, 0xe7, 0x3f, 0xa6, 0x9a, 0x72, 0x3b, 0xd5, 0x94, 0x5f, 0x53, 0x4d,
+	0xf9, 0x3e, 0xd3, 0x32, 0xb7, 0x33, 0x2d, 0xf3, 0x73, 0xa6, 0x65, 0x3e, 0xbd, 0xee, 0xfa, 0xbc,
+	0x37, 0x72, 0x2b, 0x5e, 0xd8, 0x8f, 0x47, 0x09, 0x0e, 0x98, 0x1f, 0x0e, 0x6e, 0x26, 0x5f, 0xd2,
+	0xf1, 0x22, 0x67, 0xcd, 0xd0, 0x15, 0x63, 0xc8, 0x5d, 0x15, 0x63, 0xe6, 0xed, 0xef, 0x00, 0x00,
+	0x00, 0xff, 0xff, 0x20, 0xf2, 0x66, 0x8d, 0xa0, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -660,6 +680,13 @@ func (m *SubmitBatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Blob) > 0 {
+		i -= len(m.Blob)
+		copy(dAtA[i:], m.Blob)
+		i = encodeVarintDalc(dAtA, i, uint64(len(m.Blob)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.Batch != nil {
 		{
 			size, err := m.Batch.MarshalToSizedBuffer(dAtA[:i])
@@ -831,6 +858,15 @@ func (m *RetrieveBatchesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	if len(m.Blobs) > 0 {
+		for iNdEx := len(m.Blobs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Blobs[iNdEx])
+			copy(dAtA[i:], m.Blobs[iNdEx])
+			i = encodeVarintDalc(dAtA, i, uint64(len(m.Blobs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Batches) > 0 {
 		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -900,6 +936,10 @@ func (m *SubmitBatchRequest) Size() (n int) {
 		l = m.Batch.Size()
 		n += 1 + l + sovDalc(uint64(l))
 	}
+	l = len(m.Blob)
+	if l > 0 {
+		n += 1 + l + sovDalc(uint64(l))
+	}
 	return n
 }
 
@@ -972,6 +1012,12 @@ func (m *RetrieveBatchesResponse) Size() (n int) {
 			n += 1 + l + sovDalc(uint64(l))
 		}
 	}
+	if len(m.Blobs) > 0 {
+		for _, b := range m.Blobs {
+			l = len(b)
+			n += 1 + l + sovDalc(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1166,6 +1212,40 @@ func (m *SubmitBatchRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blob", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDalc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDalc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDalc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blob = append(m.Blob[:0], dAtA[iNdEx:postIndex]...)
+			if m.Blob == nil {
+				m.Blob = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDalc(dAtA[iNdEx:])
@@ -1616,6 +1696,38 @@ func (m *RetrieveBatchesResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blobs", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDalc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDalc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDalc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blobs = append(m.Blobs, make([]byte, postIndex-iNdEx))
+			copy(m.Blobs[len(m.Blobs)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDalc(dAtA[iNdEx:])
@@ -17,6 +17,9 @@ func (b *Block) ValidateBasic() error {
 	if err != nil {
 		return err
 	}
+	if b.Header.DataHash != b.Data.Hash() {
+		return errors.New("data doesn't match data hash")
+	}
 
 	err = b.LastCommit.ValidateBasic()
 	if err != nil {